@@ -0,0 +1,126 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/pflag"
+)
+
+// AnalysisContext holds the inputs shared by every analyzer in a health-check run.
+type AnalysisContext struct {
+	Repo        *git.Repository
+	Since       time.Time // zero means all history
+	PathFilters []string
+}
+
+// Analyzer is a metric that runs as part of health-check. Built-in metrics and
+// team-specific ones compiled in through RegisterAnalyzer implement the same
+// interface, so a custom binary needs nothing more than:
+//
+//	func main() {
+//		cmd.RegisterAnalyzer(&migrationRollbackAnalyzer{})
+//		cmd.Execute()
+//	}
+//
+// A run calls Start once, ProcessCommit for every commit in the time window
+// (newest first, honoring --first-parent), then Finalize once.
+type Analyzer interface {
+	// Name identifies the analyzer; it is used as the metric name and must be unique.
+	Name() string
+
+	// RegisterFlags adds the analyzer's options to the health-check command.
+	// Prefix flag names with the analyzer name to avoid collisions.
+	RegisterFlags(flags *pflag.FlagSet)
+
+	// Start resets any state from a previous run.
+	Start(ctx *AnalysisContext) error
+
+	// ProcessCommit is called for each commit in the time window. Analyzers
+	// that only look at the current tree can return nil.
+	ProcessCommit(ctx *AnalysisContext, c *object.Commit) error
+
+	// Finalize returns the analyzer's results and the issues that exceed its thresholds.
+	// Issues with an empty Category or zero Score are filled in from the metric and severity.
+	Finalize(ctx *AnalysisContext) (interface{}, []HealthIssue, error)
+}
+
+var (
+	analyzerRegistryMu sync.Mutex
+	analyzerRegistry   []Analyzer
+)
+
+// RegisterAnalyzer adds an analyzer to health-check and registers its flags.
+// It panics if an analyzer with the same name is already registered.
+func RegisterAnalyzer(a Analyzer) {
+	analyzerRegistryMu.Lock()
+	defer analyzerRegistryMu.Unlock()
+
+	for _, existing := range analyzerRegistry {
+		if existing.Name() == a.Name() {
+			panic(fmt.Sprintf("gitallica: analyzer %q registered twice", a.Name()))
+		}
+	}
+	analyzerRegistry = append(analyzerRegistry, a)
+	a.RegisterFlags(healthCheckCmd.Flags())
+}
+
+// RegisteredAnalyzers returns the registered analyzers in registration order.
+func RegisteredAnalyzers() []Analyzer {
+	analyzerRegistryMu.Lock()
+	defer analyzerRegistryMu.Unlock()
+
+	analyzers := make([]Analyzer, len(analyzerRegistry))
+	copy(analyzers, analyzerRegistry)
+	return analyzers
+}
+
+// normalizeHealthIssue fills in the category and score an analyzer left empty
+func normalizeHealthIssue(issue HealthIssue) HealthIssue {
+	if issue.Category == "" {
+		issue.Category = categorizeIssue(issue.Metric)
+	}
+	if issue.Score == 0 {
+		issue.Score = getSeverityScore(issue.Severity)
+	}
+	return issue
+}
+
+// noFlags can be embedded by analyzers without options
+type noFlags struct{}
+
+// RegisterFlags registers nothing
+func (noFlags) RegisterFlags(flags *pflag.FlagSet) {}
+
+// snapshotAnalyzer can be embedded by analyzers that only inspect the current tree
+type snapshotAnalyzer struct{ noFlags }
+
+// Start does nothing
+func (snapshotAnalyzer) Start(ctx *AnalysisContext) error { return nil }
+
+// ProcessCommit does nothing
+func (snapshotAnalyzer) ProcessCommit(ctx *AnalysisContext, c *object.Commit) error { return nil }
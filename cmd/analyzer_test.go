@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestBuiltinAnalyzersRegistered(t *testing.T) {
+	expected := []string{"churn", "test-ratio", "bus-factor", "dead-zones", "commit-size"}
+
+	analyzers := RegisteredAnalyzers()
+	if len(analyzers) < len(expected) {
+		t.Fatalf("Expected at least %d registered analyzers, got %d", len(expected), len(analyzers))
+	}
+	for i, name := range expected {
+		if analyzers[i].Name() != name {
+			t.Errorf("Analyzer %d: expected %s, got %s", i, name, analyzers[i].Name())
+		}
+	}
+}
+
+func TestRegisterAnalyzerDuplicateName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic when registering a duplicate analyzer name")
+		}
+	}()
+	RegisterAnalyzer(&churnHealthAnalyzer{})
+}
+
+func TestNormalizeHealthIssue(t *testing.T) {
+	tests := []struct {
+		name             string
+		issue            HealthIssue
+		expectedCategory string
+		expectedScore    int
+	}{
+		{"fills category and score", HealthIssue{Metric: "churn", Severity: "High"}, "Code Stability", 75},
+		{"unknown metric", HealthIssue{Metric: "migrations", Severity: "Low"}, "General", 25},
+		{"keeps explicit values", HealthIssue{Metric: "churn", Category: "Custom", Severity: "High", Score: 90}, "Custom", 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := normalizeHealthIssue(tt.issue)
+			if result.Category != tt.expectedCategory {
+				t.Errorf("Expected category %s, got %s", tt.expectedCategory, result.Category)
+			}
+			if result.Score != tt.expectedScore {
+				t.Errorf("Expected score %d, got %d", tt.expectedScore, result.Score)
+			}
+		})
+	}
+}
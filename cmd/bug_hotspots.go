@@ -0,0 +1,477 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/spf13/cobra"
+)
+
+// defaultFixPatterns classify a commit as a fix when any of them matches its
+// message. A keyword followed by a hyphen is part of a name such as
+// bug-hotspots or --fix-pattern, not a fix. Teams with issue trackers usually
+// add their own key pattern, e.g. `\bBUG-\d+\b`.
+var defaultFixPatterns = []string{
+	`(?i)\b(fix|fixes|fixed|fixing|hotfix|bugfix)\b([^-]|$)`,
+	`(?i)\b(bug|bugs|defect|regression)\b([^-]|$)`,
+	`(?i)^revert\b`,
+	`(?i)this reverts commit [0-9a-f]{7,40}`,
+}
+
+// FileBugStats ranks one file by how often fixes touch it
+type FileBugStats struct {
+	Path       string
+	Fixes      int // fix commits touching the file
+	Commits    int // all commits touching the file
+	LOC        int
+	FixDensity float64 // fixes per 1,000 lines at HEAD
+}
+
+// FixPercent returns the share of the file's commits that were fixes
+func (s FileBugStats) FixPercent() float64 {
+	if s.Commits == 0 {
+		return 0
+	}
+	return float64(s.Fixes) / float64(s.Commits) * 100
+}
+
+// DirectoryBugStats aggregates fix frequency for one directory
+type DirectoryBugStats struct {
+	Path       string
+	Files      int
+	Fixes      int // distinct fix commits touching the directory
+	LOC        int
+	FixDensity float64
+}
+
+// BugIntroducer is a commit whose lines were later deleted or changed by fixes (SZZ)
+type BugIntroducer struct {
+	Hash    plumbing.Hash
+	Author  string
+	When    time.Time
+	Subject string
+	Fixes   int // distinct fix commits traced back to this commit
+	Lines   int // lines traced back to this commit
+}
+
+// SZZResult summarizes an SZZ trace of fix commits to bug-introducing commits
+type SZZResult struct {
+	FixesTraced     int
+	Introducers     []BugIntroducer
+	MedianDaysToFix float64 // median days from introduction to fix, per traced line
+}
+
+// bugHotspotCounter accumulates fix and commit counts for files present at HEAD
+type bugHotspotCounter struct {
+	fileSizes map[string]int
+	fixes     map[string]int
+	commits   map[string]int
+	dirFixes  map[string]int
+}
+
+func newBugHotspotCounter(fileSizes map[string]int) *bugHotspotCounter {
+	return &bugHotspotCounter{
+		fileSizes: fileSizes,
+		fixes:     make(map[string]int),
+		commits:   make(map[string]int),
+		dirFixes:  make(map[string]int),
+	}
+}
+
+// add records one commit's changed paths; files no longer present at HEAD are ignored
+func (b *bugHotspotCounter) add(paths []string, fix bool) {
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		if _, ok := b.fileSizes[path]; !ok {
+			continue
+		}
+		b.commits[path]++
+		if fix {
+			b.fixes[path]++
+			dirs[reportDirectory(path)] = true
+		}
+	}
+	for dir := range dirs {
+		b.dirFixes[dir]++
+	}
+}
+
+// fixDensity returns fixes per 1,000 lines
+func fixDensity(fixes, loc int) float64 {
+	if loc == 0 {
+		return 0
+	}
+	return float64(fixes) / float64(loc) * 1000
+}
+
+// files returns the files touched by at least one fix, most fixes first
+func (b *bugHotspotCounter) files() []FileBugStats {
+	var stats []FileBugStats
+	for path, fixes := range b.fixes {
+		loc := b.fileSizes[path]
+		stats = append(stats, FileBugStats{
+			Path:       path,
+			Fixes:      fixes,
+			Commits:    b.commits[path],
+			LOC:        loc,
+			FixDensity: fixDensity(fixes, loc),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Fixes != stats[j].Fixes {
+			return stats[i].Fixes > stats[j].Fixes
+		}
+		if stats[i].FixDensity != stats[j].FixDensity {
+			return stats[i].FixDensity > stats[j].FixDensity
+		}
+		return stats[i].Path < stats[j].Path
+	})
+	return stats
+}
+
+// directories returns the directories touched by at least one fix, most fixes first
+func (b *bugHotspotCounter) directories() []DirectoryBugStats {
+	dirMap := make(map[string]*DirectoryBugStats)
+	for path, loc := range b.fileSizes {
+		dir := reportDirectory(path)
+		if b.dirFixes[dir] == 0 {
+			continue
+		}
+		if dirMap[dir] == nil {
+			dirMap[dir] = &DirectoryBugStats{Path: dir, Fixes: b.dirFixes[dir]}
+		}
+		dirMap[dir].Files++
+		dirMap[dir].LOC += loc
+	}
+
+	dirs := make([]DirectoryBugStats, 0, len(dirMap))
+	for _, d := range dirMap {
+		d.FixDensity = fixDensity(d.Fixes, d.LOC)
+		dirs = append(dirs, *d)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].Fixes != dirs[j].Fixes {
+			return dirs[i].Fixes > dirs[j].Fixes
+		}
+		if dirs[i].FixDensity != dirs[j].FixDensity {
+			return dirs[i].FixDensity > dirs[j].FixDensity
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+	return dirs
+}
+
+// deletedLineNumbers returns the 1-based line numbers, in the old version of
+// a file, of the non-blank lines a patch deletes or changes
+func deletedLineNumbers(chunks []diff.Chunk) []int {
+	var lines []int
+	oldLine := 0
+	for _, chunk := range chunks {
+		content := splitSurvivalLines(chunk.Content())
+		switch chunk.Type() {
+		case diff.Equal:
+			oldLine += len(content)
+		case diff.Delete:
+			for _, l := range content {
+				oldLine++
+				if !isEmptyLine(strings.TrimSuffix(l, "\n")) {
+					lines = append(lines, oldLine)
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// traceBugIntroducers approximates SZZ: every line a fix deletes or changes is
+// blamed in the fix's parent, and the commit that last touched it is taken as
+// the one that introduced the bug. Merge and root fixes are not traced.
+func traceBugIntroducers(fixes []*object.Commit, pathFilters []string) *SZZResult {
+	result := &SZZResult{}
+	introducers := make(map[plumbing.Hash]*BugIntroducer)
+	fixedBy := make(map[plumbing.Hash]map[plumbing.Hash]bool)
+	var latencies []float64
+
+	for _, fix := range fixes {
+		if fix.NumParents() != 1 {
+			continue
+		}
+		parent, err := fix.Parent(0)
+		if err != nil {
+			continue
+		}
+		patch, err := parent.Patch(fix)
+		if err != nil {
+			log.Printf("failed to diff fix %s: %v", fix.Hash.String(), err)
+			continue
+		}
+
+		traced := false
+		for _, fp := range patch.FilePatches() {
+			from, _ := fp.Files()
+			if fp.IsBinary() || from == nil || !matchesPathFilter(from.Path(), pathFilters) {
+				continue
+			}
+			lineNumbers := deletedLineNumbers(fp.Chunks())
+			if len(lineNumbers) == 0 {
+				continue
+			}
+			blame, err := git.Blame(parent, from.Path())
+			if err != nil {
+				log.Printf("failed to blame %s at %s: %v", from.Path(), parent.Hash.String(), err)
+				continue
+			}
+			for _, n := range lineNumbers {
+				if n > len(blame.Lines) {
+					continue
+				}
+				line := blame.Lines[n-1]
+				intro := introducers[line.Hash]
+				if intro == nil {
+					intro = &BugIntroducer{Hash: line.Hash, Author: normalizeAuthorName(line.AuthorName, line.Author), When: line.Date}
+					introducers[line.Hash] = intro
+					fixedBy[line.Hash] = make(map[plumbing.Hash]bool)
+				}
+				intro.Lines++
+				fixedBy[line.Hash][fix.Hash] = true
+				latencies = append(latencies, fix.Author.When.Sub(line.Date).Hours()/24)
+				traced = true
+			}
+		}
+		if traced {
+			result.FixesTraced++
+		}
+	}
+
+	for hash, intro := range introducers {
+		intro.Fixes = len(fixedBy[hash])
+		result.Introducers = append(result.Introducers, *intro)
+	}
+	sort.Slice(result.Introducers, func(i, j int) bool {
+		a, b := result.Introducers[i], result.Introducers[j]
+		if a.Fixes != b.Fixes {
+			return a.Fixes > b.Fixes
+		}
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return a.When.After(b.When)
+	})
+	if len(latencies) > 0 {
+		result.MedianDaysToFix = calculatePercentile(latencies, 50)
+	}
+	return result
+}
+
+// fillIntroducerSubjects looks up the subject line of each bug-introducing commit
+func fillIntroducerSubjects(repo *git.Repository, introducers []BugIntroducer) {
+	for i := range introducers {
+		c, err := repo.CommitObject(introducers[i].Hash)
+		if err != nil {
+			continue
+		}
+		introducers[i].Subject = strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0]
+	}
+}
+
+// printBugHotspots prints the file and directory rankings
+func printBugHotspots(files []FileBugStats, dirs []DirectoryBugStats, limit int) {
+	fmt.Printf("\nTop %d files by fix frequency:\n", limit)
+	fmt.Printf("%-50s %6s %8s %7s %8s %10s\n", "File", "Fixes", "Commits", "Fix %", "LOC", "Fixes/KLOC")
+	for i, f := range files {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-50s %6d %8d %6.1f%% %8d %10.1f\n", f.Path, f.Fixes, f.Commits, f.FixPercent(), f.LOC, f.FixDensity)
+	}
+
+	fmt.Printf("\nTop %d directories by fix frequency:\n", limit)
+	fmt.Printf("%-50s %6s %6s %8s %10s\n", "Directory", "Files", "Fixes", "LOC", "Fixes/KLOC")
+	for i, d := range dirs {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-50s %6d %6d %8d %10.1f\n", d.Path, d.Files, d.Fixes, d.LOC, d.FixDensity)
+	}
+}
+
+// printSZZResult prints the likely bug-introducing commits
+func printSZZResult(result *SZZResult, limit int) {
+	fmt.Printf("\nLikely bug-introducing commits (SZZ approximation, top %d):\n", limit)
+	fmt.Printf("Fix commits traced: %d; introducing commits found: %d", result.FixesTraced, len(result.Introducers))
+	if len(result.Introducers) > 0 {
+		fmt.Printf("; median time to fix: %.1f days", result.MedianDaysToFix)
+	}
+	fmt.Println()
+	if len(result.Introducers) == 0 {
+		return
+	}
+
+	fmt.Printf("%-8s %-10s %-25s %5s %6s  %s\n", "Commit", "Date", "Author", "Fixes", "Lines", "Subject")
+	for i, intro := range result.Introducers {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-8s %-10s %-25s %5d %6d  %s\n", intro.Hash.String()[:7], intro.When.Format("2006-01-02"), intro.Author, intro.Fixes, intro.Lines, intro.Subject)
+	}
+}
+
+// bugHotspotsCmd represents the bug-hotspots command
+var bugHotspotsCmd = &cobra.Command{
+	Use:   "bug-hotspots",
+	Short: "Rank files and directories by bug-fix frequency",
+	Long: `Find defect-prone code by counting the fix commits that touch it.
+
+A commit is a fix when its message matches any fix pattern. The defaults match
+"fix", "bug", "hotfix", "regression" and revert commits; add issue-key
+patterns with --fix-pattern or bug-hotspots.fix_patterns in the config file.
+Files present at HEAD are ranked by fix count and fix density (fixes per
+1,000 lines); directories count each fix commit once.
+
+With --szz, every line a fix deletes or changes is blamed in the fix's parent
+to find the commit that introduced it, approximating the SZZ algorithm
+(Śliwerski, Zimmermann & Zeller). Blame is slow on large histories; use
+--last to bound the fixes traced.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse flags
+		lastArg, _ := cmd.Flags().GetString("last")
+		pathFilters, source := getConfigPaths(cmd, "bug-hotspots.paths")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		szzArg, _ := cmd.Flags().GetBool("szz")
+		patterns, patternSource := getMessagePatterns(cmd, "fix-pattern", "bug-hotspots.fix_patterns", defaultFixPatterns)
+		fixPatterns, err := compileMessagePatterns(patterns)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		countMode, err := getCountMode(cmd)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "bug-hotspots", lastArg, pathFilters, source)
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			log.Fatalf("Could not open repository: %v", err)
+		}
+
+		since := time.Time{}
+		if lastArg != "" {
+			cutoff, err := parseDurationArg(lastArg)
+			if err != nil {
+				log.Fatalf("Could not parse --last argument: %v", err)
+			}
+			since = cutoff
+		}
+
+		fileSizes, err := getCurrentFileSizes(repo, pathFilters, countMode)
+		if err != nil {
+			log.Fatalf("Could not get current file sizes: %v", err)
+		}
+
+		ref, err := repo.Head()
+		if err != nil {
+			log.Fatalf("Could not get HEAD: %v", err)
+		}
+		cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
+		if err != nil {
+			log.Fatalf("Could not get commits: %v", err)
+		}
+
+		counter := newBugHotspotCounter(fileSizes)
+		var fixes []*object.Commit
+		totalCommits := 0
+		err = cIter.ForEach(func(c *object.Commit) error {
+			if !since.IsZero() && c.Committer.When.Before(since) {
+				return storer.ErrStop
+			}
+			if !includeMergeCommit(c) {
+				return nil
+			}
+			paths, err := commitChangedPaths(c, pathFilters, false)
+			if err != nil {
+				log.Printf("Error processing commit %s: %v", c.Hash.String(), err)
+				return nil
+			}
+			if len(paths) == 0 {
+				return nil
+			}
+			totalCommits++
+			fix := matchesAnyPattern(c.Message, fixPatterns)
+			if fix {
+				fixes = append(fixes, c)
+			}
+			counter.add(paths, fix)
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Error walking commits: %v", err)
+		}
+
+		fmt.Printf("Bug Hotspot Analysis\n")
+		fmt.Printf("Fix patterns %s: %s\n", patternSource, strings.Join(patterns, "  "))
+		fixRate := 0.0
+		if totalCommits > 0 {
+			fixRate = float64(len(fixes)) / float64(totalCommits) * 100
+		}
+		fmt.Printf("Fix commits: %d of %d (%.1f%%)\n", len(fixes), totalCommits, fixRate)
+		if countMode == countModeCode {
+			fmt.Printf("LOC: code lines only (comments and blank lines excluded)\n")
+		}
+		if len(fixes) == 0 {
+			fmt.Println("\nNo fix commits found. Adjust --fix-pattern to match your commit conventions.")
+			return
+		}
+
+		printBugHotspots(counter.files(), counter.directories(), limitArg)
+
+		if szzArg {
+			result := traceBugIntroducers(fixes, pathFilters)
+			fillIntroducerSubjects(repo, result.Introducers)
+			printSZZResult(result, limitArg)
+		}
+
+		fmt.Println()
+		fmt.Println("Context:", churnFilesBenchmarkContext)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bugHotspotsCmd)
+	bugHotspotsCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
+	bugHotspotsCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+	bugHotspotsCmd.Flags().Int("limit", 10, "Number of top files, directories and commits to show")
+	bugHotspotsCmd.Flags().StringSlice("fix-pattern", []string{}, "Regex matching fix commit messages (can be specified multiple times; replaces the defaults)")
+	bugHotspotsCmd.Flags().Bool("szz", false, "Trace lines changed by fixes back to the commits that introduced them")
+	addCountFlag(bugHotspotsCmd)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+type testChunk struct {
+	content string
+	op      diff.Operation
+}
+
+func (c testChunk) Content() string      { return c.content }
+func (c testChunk) Type() diff.Operation { return c.op }
+
+func TestMatchesFixPatterns(t *testing.T) {
+	patterns, err := compileMessagePatterns(defaultFixPatterns)
+	if err != nil {
+		t.Fatalf("Unexpected error compiling defaults: %v", err)
+	}
+
+	tests := []struct {
+		message  string
+		expected bool
+	}{
+		{"Fix crash on empty input", true},
+		{"fixes #123", true},
+		{"Hotfix: restore login", true},
+		{"Handle regression in parser", true},
+		{"Revert \"Add cache\"\n\nThis reverts commit 0123456789abcdef.", true},
+		{"Add prefix support", false},
+		{"Debug logging", false},
+		{"Add feature", false},
+		{"Add bug-hotspots command", false},
+		{"Link commits to the bug-tracker", false},
+		{"Add --fix-pattern flag", false},
+		{"Fix bug-tracker links", true},
+		{"Found a bug", true},
+	}
+
+	for _, tt := range tests {
+		if result := matchesAnyPattern(tt.message, patterns); result != tt.expected {
+			t.Errorf("matchesAnyPattern(%q) = %v, want %v", tt.message, result, tt.expected)
+		}
+	}
+
+	custom, err := compileMessagePatterns([]string{`\bBUG-\d+\b`})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !matchesAnyPattern("BUG-42 handle nil", custom) || matchesAnyPattern("Fix typo", custom) {
+		t.Error("Expected custom patterns to replace the defaults")
+	}
+
+	if _, err := compileMessagePatterns([]string{"("}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestBugHotspotCounter(t *testing.T) {
+	counter := newBugHotspotCounter(map[string]int{"src/a.go": 500, "src/b.go": 100, "lib/c.go": 2000})
+	counter.add([]string{"src/a.go", "src/b.go"}, true)
+	counter.add([]string{"src/a.go", "deleted.go"}, true)
+	counter.add([]string{"src/a.go", "lib/c.go"}, false)
+	counter.add([]string{"lib/c.go"}, true)
+
+	files := counter.files()
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files with fixes, got %+v", files)
+	}
+	if files[0].Path != "src/a.go" || files[0].Fixes != 2 || files[0].Commits != 3 || files[0].FixDensity != 4 {
+		t.Errorf("Unexpected top file %+v", files[0])
+	}
+	// Equal fix counts rank by density: b.go (10/KLOC) before c.go (0.5/KLOC)
+	if files[1].Path != "src/b.go" || files[2].Path != "lib/c.go" {
+		t.Errorf("Expected src/b.go then lib/c.go, got %s then %s", files[1].Path, files[2].Path)
+	}
+
+	dirs := counter.directories()
+	if len(dirs) != 2 {
+		t.Fatalf("Expected 2 directories, got %+v", dirs)
+	}
+	// The first commit touched two src/ files but counts once
+	if dirs[0].Path != "src/" || dirs[0].Fixes != 2 || dirs[0].Files != 2 || dirs[0].LOC != 600 {
+		t.Errorf("Unexpected src/ stats %+v", dirs[0])
+	}
+}
+
+func TestDeletedLineNumbers(t *testing.T) {
+	chunks := []diff.Chunk{
+		testChunk{"a\nb\n", diff.Equal},
+		testChunk{"c\n\nd\n", diff.Delete},
+		testChunk{"C\n", diff.Add},
+		testChunk{"e\n", diff.Equal},
+		testChunk{"f\n", diff.Delete},
+	}
+
+	lines := deletedLineNumbers(chunks)
+	expected := []int{3, 5, 7}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, lines)
+			break
+		}
+	}
+}
@@ -22,6 +22,7 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"path/filepath"
@@ -45,32 +46,51 @@ const (
 
 const busFactorBenchmarkContext = "Empirical studies show 46% of GitHub projects have bus factor of 1, 28% have bus factor of 2."
 
+// Bus factor methods: commits counts the commits each author made to a
+// directory's files (fast); blame counts the HEAD lines each author last
+// changed, so many small commits don't outweigh writing the code
+const (
+	busFactorMethodCommits = "commits"
+	busFactorMethodBlame   = "blame"
+)
+
 // DirectoryBusFactorStats represents bus factor statistics for a directory
 type DirectoryBusFactorStats struct {
-	Path               string
-	TotalLines         int                    // Total lines of code in directory
-	AuthorLines        map[string]int         // Lines authored by each contributor
-	AuthorPercentages  map[string]float64     // Percentage of lines authored by each contributor
-	BusFactor          int
-	RiskLevel          string
-	Recommendation     string
-	TopContributors    []AuthorContribution
+	Path               string                 `json:"path"`
+	TotalLines         int                    `json:"total"`        // Total lines (blame) or commits (commits method) in directory
+	AuthorLines        map[string]int         `json:"author_lines"` // Lines (or commits) by each contributor
+	AuthorPercentages  map[string]float64     `json:"author_percentages"`
+	BusFactor          int                    `json:"bus_factor"`
+	RiskLevel          string                 `json:"risk_level"`
+	Recommendation     string                 `json:"recommendation"`
+	TopContributors    []AuthorContribution   `json:"top_contributors"`
 }
 
 // AuthorContribution represents an author's contribution to a directory
 type AuthorContribution struct {
-	Author     string
-	Lines      int        // Lines authored (was Commits)
-	Percentage float64
+	Author     string  `json:"author"`
+	Lines      int     `json:"lines"` // Lines authored, or commits with the commits method
+	Percentage float64 `json:"percentage"`
 }
 
 // BusFactorAnalysis represents the overall bus factor analysis
 type BusFactorAnalysis struct {
-	TimeWindow        string
-	TotalDirectories  int
-	DirectoryStats    []DirectoryBusFactorStats
-	OverallRiskDirs   []DirectoryBusFactorStats
-	HealthyDirs       []DirectoryBusFactorStats
+	Method            string                     `json:"method"` // "commits" or "blame"
+	TimeWindow        string                     `json:"time_window"`
+	TotalDirectories  int                        `json:"total_directories"`
+	DirectoryStats    []DirectoryBusFactorStats  `json:"directories"`
+	OverallRiskDirs   []DirectoryBusFactorStats  `json:"-"`
+	HealthyDirs       []DirectoryBusFactorStats  `json:"-"`
+	FileAuthors       map[string]map[string]int  `json:"-"` // HEAD file -> author -> commits or lines
+	AuthorNames       map[string]map[string]bool `json:"-"` // lowercased author name -> identities counted for it
+}
+
+// unit names what AuthorLines counts for the analysis method
+func (a *BusFactorAnalysis) unit() string {
+	if a.Method == busFactorMethodBlame {
+		return "lines"
+	}
+	return "commits"
 }
 
 // normalizeAuthorName normalizes author names to handle different formats
@@ -456,8 +476,8 @@ func findFileAuthorWithFallback(repo *git.Repository, fileName string, headCommi
 }
 
 // buildFileAuthorMap efficiently builds a map of file authors using a single git traversal
-func buildFileAuthorMap(repo *git.Repository, ref *plumbing.Reference, since time.Time, pathFilters []string, fileAuthors map[string]map[string]int) error {
-	cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+func buildFileAuthorMap(repo *git.Repository, ref *plumbing.Reference, since time.Time, pathFilters []string, fileAuthors map[string]map[string]int, names map[string]map[string]bool) error {
+	cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
 	if err != nil {
 		return fmt.Errorf("could not get commits: %v", err)
 	}
@@ -468,87 +488,93 @@ func buildFileAuthorMap(repo *git.Repository, ref *plumbing.Reference, since tim
 			return storer.ErrStop
 		}
 		
-		// Handle merge commits by diffing against their first parent
-		var parentTree *object.Tree
-		if c.NumParents() > 0 {
-			parent, err := c.Parent(0)
-			if err != nil {
-				return nil
-			}
-			parentTree, err = parent.Tree()
-			if err != nil {
-				return nil
+		// Changed files follow the active merge policy; root commits author every file
+		paths, err := commitChangedPaths(c, pathFilters, false)
+		if err != nil {
+			return nil
+		}
+		
+		author := normalizeAuthorName(c.Author.Name, c.Author.Email)
+		recordAuthorName(names, c.Author.Name, author)
+		for _, path := range paths {
+			// Initialize file authors map if needed
+			if fileAuthors[path] == nil {
+				fileAuthors[path] = make(map[string]int)
 			}
+			fileAuthors[path][author]++ // Count commits, not lines
 		}
 		
-		tree, err := c.Tree()
+		return nil
+	})
+	
+	return err
+}
+
+// busFactorDirectory returns the directory a file's knowledge is grouped under
+func busFactorDirectory(fileName string) string {
+	dir := filepath.Dir(fileName)
+	if dir == "." {
+		return "root"
+	}
+	return dir + "/"
+}
+
+// buildFileBlameMap blames every file at HEAD and attributes each non-blank
+// line to the author who last changed it. Like git blame without -M/-C, a
+// renamed or moved line belongs to whoever renamed or moved it. With a cutoff
+// only lines last changed since then count.
+func buildFileBlameMap(headCommit *object.Commit, since time.Time, pathFilters []string, fileAuthors map[string]map[string]int, names map[string]map[string]bool) error {
+	tree, err := headCommit.Tree()
+	if err != nil {
+		return fmt.Errorf("could not get HEAD tree: %v", err)
+	}
+	return tree.Files().ForEach(func(f *object.File) error {
+		if !matchesPathFilter(f.Name, pathFilters) {
+			return nil
+		}
+		if isBinary, err := f.IsBinary(); err != nil || isBinary {
+			return nil
+		}
+		blame, err := git.Blame(headCommit, f.Name)
 		if err != nil {
+			log.Printf("failed to blame %s: %v", f.Name, err)
 			return nil
 		}
-		
-		if parentTree != nil {
-			// Compare with parent to get changed files
-			changes, err := tree.Diff(parentTree)
-			if err != nil {
-				return nil
+		for _, line := range blame.Lines {
+			if strings.TrimSpace(line.Text) == "" {
+				continue
 			}
-			
-			for _, change := range changes {
-				if change.To.Name == "" {
-					continue // skip deletions
-				}
-				
-				// Apply path filter if specified
-				if !matchesPathFilter(change.To.Name, pathFilters) {
-					continue
-				}
-				
-				// Initialize file authors map if needed
-				if fileAuthors[change.To.Name] == nil {
-					fileAuthors[change.To.Name] = make(map[string]int)
-				}
-				
-				author := normalizeAuthorName(c.Author.Name, c.Author.Email)
-				fileAuthors[change.To.Name][author]++ // Count commits, not lines
+			if !since.IsZero() && line.Date.Before(since) {
+				continue
 			}
-		} else {
-			// Initial commit - all files are authored by this commit
-			err = tree.Files().ForEach(func(f *object.File) error {
-				if !matchesPathFilter(f.Name, pathFilters) {
-					return nil
-				}
-				
-				// Initialize file authors map if needed
-				if fileAuthors[f.Name] == nil {
-					fileAuthors[f.Name] = make(map[string]int)
-				}
-				
-				author := normalizeAuthorName(c.Author.Name, c.Author.Email)
-				fileAuthors[f.Name][author]++ // Count commits, not lines
-				
-				return nil
-			})
-			if err != nil {
-				return err
+			if fileAuthors[f.Name] == nil {
+				fileAuthors[f.Name] = make(map[string]int)
 			}
+			author := normalizeAuthorName(line.AuthorName, line.Author)
+			recordAuthorName(names, line.AuthorName, author)
+			fileAuthors[f.Name][author]++
 		}
-		
 		return nil
 	})
-	
-	return err
 }
 
 // analyzeBusFactor performs bus factor analysis using an efficient commit-based approach
 // This provides accurate knowledge measurement while maintaining good performance by
 // analyzing file authorship through commit history rather than line-by-line blame.
 func analyzeBusFactor(repo *git.Repository, since time.Time, pathFilters []string) (*BusFactorAnalysis, error) {
+	return analyzeBusFactorWithMethod(repo, since, pathFilters, busFactorMethodCommits)
+}
+
+// analyzeBusFactorWithMethod performs bus factor analysis, counting each
+// author's commits (commits method) or the HEAD lines they last changed
+// (blame method) per directory
+func analyzeBusFactorWithMethod(repo *git.Repository, since time.Time, pathFilters []string, method string) (*BusFactorAnalysis, error) {
 	ref, err := repo.Head()
 	if err != nil {
 		return nil, fmt.Errorf("could not get HEAD: %v", err)
 	}
 	
-	// Track file authorship per directory using commit-based analysis
+	// Track file authorship per directory
 	directoryOwnership := make(map[string]map[string]int)
 	
 	// Get current HEAD commit and tree
@@ -563,13 +589,19 @@ func analyzeBusFactor(repo *git.Repository, since time.Time, pathFilters []strin
 	}
 	
 	// Build comprehensive file author map efficiently
-	fileAuthors := make(map[string]map[string]int) // file -> author -> lines contributed
-	err = buildFileAuthorMap(repo, ref, since, pathFilters, fileAuthors)
+	fileAuthors := make(map[string]map[string]int) // file -> author -> commits or lines
+	authorNames := make(map[string]map[string]bool)
+	if method == busFactorMethodBlame {
+		err = buildFileBlameMap(headCommit, since, pathFilters, fileAuthors, authorNames)
+	} else {
+		err = buildFileAuthorMap(repo, ref, since, pathFilters, fileAuthors, authorNames)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error building file author map: %v", err)
 	}
 	
 	// Initialize directory structure
+	headFiles := make(map[string]bool)
 	err = tree.Files().ForEach(func(f *object.File) error {
 		// Apply path filter if specified
 		if !matchesPathFilter(f.Name, pathFilters) {
@@ -582,15 +614,9 @@ func analyzeBusFactor(repo *git.Repository, since time.Time, pathFilters []strin
 			return nil
 		}
 		
-		// Get directory for this file
-		dir := filepath.Dir(f.Name)
-		if dir == "." {
-			dir = "root"
-		} else {
-			dir = dir + "/"
-		}
-		
 		// Initialize directory ownership map if needed
+		headFiles[f.Name] = true
+		dir := busFactorDirectory(f.Name)
 		if directoryOwnership[dir] == nil {
 			directoryOwnership[dir] = make(map[string]int)
 		}
@@ -602,45 +628,53 @@ func analyzeBusFactor(repo *git.Repository, since time.Time, pathFilters []strin
 		return nil, fmt.Errorf("error analyzing files: %v", err)
 	}
 	
-	// Count commits by author per directory
-	for fileName, authorCommits := range fileAuthors {
-		// Get directory for this file
-		dir := filepath.Dir(fileName)
-		if dir == "." {
-			dir = "root"
-		} else {
-			dir = dir + "/"
-		}
+	// Count commits or lines by author per directory
+	for fileName, authorCounts := range fileAuthors {
+		dir := busFactorDirectory(fileName)
 		
 		// Initialize directory ownership map if needed
 		if directoryOwnership[dir] == nil {
 			directoryOwnership[dir] = make(map[string]int)
 		}
 		
-		// Add each author's commits to the directory
-		for author, commits := range authorCommits {
-			directoryOwnership[dir][author] += commits
+		// Add each author's count to the directory
+		for author, count := range authorCounts {
+			directoryOwnership[dir][author] += count
 		}
 	}
 	
-	// Calculate bus factor statistics for each directory
+	analysis := summarizeBusFactor(directoryOwnership, since)
+	analysis.Method = method
+	analysis.AuthorNames = authorNames
+	analysis.FileAuthors = make(map[string]map[string]int)
+	for fileName, authorCounts := range fileAuthors {
+		if headFiles[fileName] {
+			analysis.FileAuthors[fileName] = authorCounts
+		}
+	}
+	return analysis, nil
+}
+
+// summarizeBusFactor calculates bus factor statistics for each directory from
+// per-author counts
+func summarizeBusFactor(directoryOwnership map[string]map[string]int, since time.Time) *BusFactorAnalysis {
 	var directoryStats []DirectoryBusFactorStats
-	for dir, authorCommits := range directoryOwnership {
-		totalCommits := 0
-		for _, commits := range authorCommits {
-			totalCommits += commits
+	for dir, authorCounts := range directoryOwnership {
+		total := 0
+		for _, count := range authorCounts {
+			total += count
 		}
 		
-		busFactor := calculateBusFactor(authorCommits)
-		riskLevel := classifyBusFactorRisk(busFactor, len(authorCommits))
-		authorPercentages := calculateAuthorContributionPercentage(authorCommits)
-		topContributors := getTopContributors(authorCommits, authorPercentages, 5)
+		busFactor := calculateBusFactor(authorCounts)
+		riskLevel := classifyBusFactorRisk(busFactor, len(authorCounts))
+		authorPercentages := calculateAuthorContributionPercentage(authorCounts)
+		topContributors := getTopContributors(authorCounts, authorPercentages, 5)
 		recommendation := getRecommendation(riskLevel, busFactor)
 		
 		stats := DirectoryBusFactorStats{
 			Path:              dir,
-			TotalLines:        totalCommits, // Using commits as proxy for lines
-			AuthorLines:       authorCommits, // Using commits as proxy for lines
+			TotalLines:        total,
+			AuthorLines:       authorCounts,
 			AuthorPercentages: authorPercentages,
 			BusFactor:         busFactor,
 			RiskLevel:         riskLevel,
@@ -671,17 +705,110 @@ func analyzeBusFactor(repo *git.Repository, since time.Time, pathFilters []strin
 	}
 	
 	return &BusFactorAnalysis{
+		Method:           busFactorMethodCommits,
 		TimeWindow:       timeWindow,
 		TotalDirectories: len(directoryStats),
 		DirectoryStats:   directoryStats,
 		OverallRiskDirs:  overallRiskDirs,
 		HealthyDirs:      healthyDirs,
-	}, nil
+	}
+}
+
+// BusFactorMethodDifference is a directory whose bus factor or top owner
+// differs between the blame and commits methods
+type BusFactorMethodDifference struct {
+	Path            string
+	BlameBusFactor  int
+	CommitBusFactor int
+	BlameOwner      string // top contributor by lines
+	CommitOwner     string // top contributor by commits
+}
+
+// busFactorMethodDifferences lists the directories where the two methods
+// disagree, in the blame analysis's risk order
+func busFactorMethodDifferences(blame, commits *BusFactorAnalysis) []BusFactorMethodDifference {
+	byPath := make(map[string]DirectoryBusFactorStats)
+	for _, stats := range commits.DirectoryStats {
+		byPath[stats.Path] = stats
+	}
+	topAuthor := func(stats DirectoryBusFactorStats) string {
+		if len(stats.TopContributors) == 0 {
+			return ""
+		}
+		return stats.TopContributors[0].Author
+	}
+
+	var differences []BusFactorMethodDifference
+	for _, stats := range blame.DirectoryStats {
+		other, ok := byPath[stats.Path]
+		if !ok {
+			continue
+		}
+		d := BusFactorMethodDifference{
+			Path:            stats.Path,
+			BlameBusFactor:  stats.BusFactor,
+			CommitBusFactor: other.BusFactor,
+			BlameOwner:      topAuthor(stats),
+			CommitOwner:     topAuthor(other),
+		}
+		if d.BlameBusFactor != d.CommitBusFactor || d.BlameOwner != d.CommitOwner {
+			differences = append(differences, d)
+		}
+	}
+	return differences
+}
+
+// printBusFactorMethodComparison shows where counting commits misjudges
+// ownership compared to counting lines
+func printBusFactorMethodComparison(blame, commits *BusFactorAnalysis, limit int) {
+	differences := busFactorMethodDifferences(blame, commits)
+	fmt.Printf("\nMethod comparison: %d of %d directories differ between blame and commit counting\n", len(differences), blame.TotalDirectories)
+	if len(differences) == 0 {
+		return
+	}
+	fmt.Printf("Directory                    Blame Commits Top owner (lines)    Top owner (commits)\n")
+	fmt.Printf("---------------------------- ----- ------- -------------------- --------------------\n")
+	for i, d := range differences {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-28s %5d %7d %-20s %s\n",
+			truncateDirectoryPath(d.Path, 28),
+			d.BlameBusFactor,
+			d.CommitBusFactor,
+			truncateAuthorName(d.BlameOwner, 20),
+			truncateAuthorName(d.CommitOwner, 20))
+	}
+}
+
+// busFactorReport is the JSON form of bus-factor. The commits method always
+// runs; the blame method is included when selected, so both can be compared.
+type busFactorReport struct {
+	Method      string               `json:"method"`
+	Commits     *BusFactorAnalysis   `json:"commits"`
+	Blame       *BusFactorAnalysis   `json:"blame,omitempty"`
+	TruckFactor *TruckFactorAnalysis `json:"truck_factor,omitempty"`
+	Departure   *DepartureSimulation `json:"departure,omitempty"`
+}
+
+// printBusFactorJSON writes the bus factor report as JSON
+func printBusFactorJSON(report busFactorReport) error {
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
 }
 
 // printBusFactorStats prints bus factor analysis results
 func printBusFactorStats(analysis *BusFactorAnalysis, limit int) {
 	fmt.Printf("Bus Factor Analysis\n")
+	if analysis.Method == busFactorMethodBlame {
+		fmt.Printf("Method: blame (lines at HEAD by last author)\n")
+	} else {
+		fmt.Printf("Method: commits (commits per author)\n")
+	}
 	fmt.Printf("Time window: %s\n", analysis.TimeWindow)
 	fmt.Printf("Total directories analyzed: %d\n", analysis.TotalDirectories)
 	fmt.Printf("High-risk directories: %d\n", len(analysis.OverallRiskDirs))
@@ -728,8 +855,8 @@ func printBusFactorStats(analysis *BusFactorAnalysis, limit int) {
 				if j >= 3 { // Show top 3 contributors
 					break
 				}
-				fmt.Printf("    %s: %d lines (%.1f%%)\n", 
-					truncateAuthorName(contrib.Author, 20), contrib.Lines, contrib.Percentage)
+				fmt.Printf("    %s: %d %s (%.1f%%)\n", 
+					truncateAuthorName(contrib.Author, 20), contrib.Lines, analysis.unit(), contrib.Percentage)
 			}
 			fmt.Printf("  Recommendation: %s\n", stats.Recommendation)
 		}
@@ -793,15 +920,50 @@ Risk Levels:
 - Medium: Bus factor adequate but could be improved
 - Healthy: Good knowledge distribution (25-50% of team)
 
+Methods:
+- commits (default): counts each author's commits to a directory's files. Fast,
+  but fifty typo fixes outweigh writing the module.
+- blame: runs git blame on every file at HEAD and counts the non-blank lines
+  each author last changed. Renames and moves are not followed, so the
+  author who renamed or moved a line owns it. Slower, since every file is
+  blamed; with --last only lines last changed in the window count.
+  Also reports where the two methods disagree.
+
+--truck-factor also computes the repository-level truck factor with the
+degree-of-authorship model (Avelino et al.): a developer authors a file based
+on creating it, their own commits to it and everyone else's, and the top
+authors are removed one by one until more than half the files have no author
+left. Reports the authors in the truck factor and the files each removal
+orphans.
+
+--simulate-departure alice@corp,bob@corp removes those authors from the
+selected method's ownership data and reports how each directory's bus factor
+changes, which directories and files have no remaining contributor, and who
+the next-best owners are. A name removes every email it committed under.
+
+--format json writes the results of both methods that ran.
+
 Based on Martin Fowler's collective ownership principles and industry research.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse flags
 		lastArg, _ := cmd.Flags().GetString("last")
 		pathFilters, source := getConfigPaths(cmd, "bus-factor.paths")
 		limitArg, _ := cmd.Flags().GetInt("limit")
+		methodArg, _ := cmd.Flags().GetString("method")
+		formatArg, _ := cmd.Flags().GetString("format")
+		truckFactorArg, _ := cmd.Flags().GetBool("truck-factor")
+		departureArg, _ := cmd.Flags().GetStringSlice("simulate-departure")
+		if methodArg != busFactorMethodCommits && methodArg != busFactorMethodBlame {
+			log.Fatalf("Invalid --method value %q (expected commits or blame)", methodArg)
+		}
+		if formatArg != "text" && formatArg != "json" {
+			log.Fatalf("Invalid --format value %q (expected text or json)", formatArg)
+		}
 		
 		// Print configuration scope
-		printCommandScope(cmd, "bus-factor", lastArg, pathFilters, source)
+		if formatArg == "text" {
+			printCommandScope(cmd, "bus-factor", lastArg, pathFilters, source)
+		}
 
 		repo, err := git.PlainOpen(".")
 		if err != nil {
@@ -822,8 +984,50 @@ Based on Martin Fowler's collective ownership principles and industry research.`
 		if err != nil {
 			log.Fatalf("Error analyzing bus factor: %v", err)
 		}
+		report := busFactorReport{Method: methodArg, Commits: analysis}
+		if methodArg == busFactorMethodBlame {
+			report.Blame, err = analyzeBusFactorWithMethod(repo, since, pathFilters, busFactorMethodBlame)
+			if err != nil {
+				log.Fatalf("Error analyzing bus factor: %v", err)
+			}
+		}
+		if len(departureArg) > 0 {
+			simulated := report.Commits
+			if report.Blame != nil {
+				simulated = report.Blame
+			}
+			report.Departure = simulateDeparture(simulated, departureArg)
+		}
+		if truckFactorArg {
+			report.TruckFactor, err = analyzeTruckFactor(repo, since, pathFilters)
+			if err != nil {
+				log.Fatalf("Error analyzing truck factor: %v", err)
+			}
+		}
+
+		if formatArg == "json" {
+			if err := printBusFactorJSON(report); err != nil {
+				log.Fatalf("Could not encode bus factor analysis: %v", err)
+			}
+			return
+		}
 
-		printBusFactorStats(analysis, limitArg)
+		if report.Blame != nil {
+			printBusFactorStats(report.Blame, limitArg)
+			printBusFactorMethodComparison(report.Blame, report.Commits, limitArg)
+		} else {
+			printBusFactorStats(analysis, limitArg)
+		}
+		if report.Departure != nil {
+			unit := report.Commits.unit()
+			if report.Blame != nil {
+				unit = report.Blame.unit()
+			}
+			printDepartureSimulation(report.Departure, unit, limitArg)
+		}
+		if report.TruckFactor != nil {
+			printTruckFactor(report.TruckFactor, limitArg)
+		}
 	},
 }
 
@@ -831,5 +1035,9 @@ func init() {
 	busFactorCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
 	busFactorCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
 	busFactorCmd.Flags().Int("limit", 10, "Number of top results to show")
+	busFactorCmd.Flags().String("method", busFactorMethodCommits, "How knowledge is measured: commits (commits per author, fast) or blame (lines at HEAD per last author)")
+	busFactorCmd.Flags().String("format", "text", "Output format: text or json")
+	busFactorCmd.Flags().Bool("truck-factor", false, "Also compute the repository truck factor with the degree-of-authorship model")
+	busFactorCmd.Flags().StringSlice("simulate-departure", []string{}, "Authors (emails or names) to remove, showing what their departure leaves behind")
 	rootCmd.AddCommand(busFactorCmd)
 }
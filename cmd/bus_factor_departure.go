@@ -0,0 +1,243 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DepartureDirectoryImpact is how one directory's bus factor changes when the
+// departing authors leave
+type DepartureDirectoryImpact struct {
+	Path              string               `json:"path"`
+	BusFactorBefore   int                  `json:"bus_factor_before"`
+	BusFactorAfter    int                  `json:"bus_factor_after"`
+	RiskBefore        string               `json:"risk_before"`
+	RiskAfter         string               `json:"risk_after"`
+	DepartedShare     float64              `json:"departed_percentage"` // Share of the directory's lines or commits held by departing authors
+	RemainingAuthors  int                  `json:"remaining_authors"`
+	NextBestOwners    []AuthorContribution `json:"next_best_owners"` // Remaining contributors, percentages of what remains
+	NoRemainingAuthor bool                 `json:"no_remaining_author"`
+}
+
+// DepartureSimulation is the bus factor analysis with some authors removed
+type DepartureSimulation struct {
+	Departed      []string                   `json:"departed"`
+	NotFound      []string                   `json:"not_found"`      // Departing identities with no contributions in the analysis
+	Directories   []DepartureDirectoryImpact `json:"directories"`    // Directories the departing authors contributed to
+	OrphanedFiles []string                   `json:"orphaned_files"` // Files only the departing authors contributed to
+}
+
+// recordAuthorName remembers the identity an author name was normalized to,
+// so departures can be given by name even when authors are counted by email
+func recordAuthorName(names map[string]map[string]bool, name, identity string) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if names == nil || key == "" {
+		return
+	}
+	if names[key] == nil {
+		names[key] = make(map[string]bool)
+	}
+	names[key][identity] = true
+}
+
+// resolveDepartureIdentity maps a --simulate-departure identity to the
+// normalized identities it stands for. Emails are normalized the way commit
+// authors are; a name matches every identity it was recorded under, or is
+// normalized as a name when it was never seen.
+func resolveDepartureIdentity(identity string, names map[string]map[string]bool) []string {
+	identity = strings.TrimSpace(identity)
+	if identity == "" {
+		return nil
+	}
+	if strings.Contains(identity, "@") {
+		return []string{normalizeAuthorName("", identity)}
+	}
+	if ids := names[strings.ToLower(identity)]; len(ids) > 0 {
+		resolved := make([]string, 0, len(ids))
+		for id := range ids {
+			resolved = append(resolved, id)
+		}
+		sort.Strings(resolved)
+		return resolved
+	}
+	return []string{normalizeAuthorName(identity, "")}
+}
+
+// withoutAuthors returns the counts of every author not in departed
+func withoutAuthors(authorCounts map[string]int, departed map[string]bool) map[string]int {
+	remaining := make(map[string]int, len(authorCounts))
+	for author, count := range authorCounts {
+		if !departed[author] {
+			remaining[author] = count
+		}
+	}
+	return remaining
+}
+
+// simulateDeparture removes the departing authors from the analysis's
+// ownership data and recomputes each directory they contributed to
+func simulateDeparture(analysis *BusFactorAnalysis, identities []string) *DepartureSimulation {
+	sim := &DepartureSimulation{
+		Departed:      []string{},
+		NotFound:      []string{},
+		Directories:   []DepartureDirectoryImpact{},
+		OrphanedFiles: []string{},
+	}
+
+	departed := make(map[string]bool)
+	for _, identity := range identities {
+		for _, author := range resolveDepartureIdentity(identity, analysis.AuthorNames) {
+			if departed[author] {
+				continue
+			}
+			departed[author] = true
+			sim.Departed = append(sim.Departed, author)
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, stats := range analysis.DirectoryStats {
+		departedCount := 0
+		for author, count := range stats.AuthorLines {
+			if departed[author] {
+				found[author] = true
+				departedCount += count
+			}
+		}
+		if departedCount == 0 {
+			continue
+		}
+
+		remaining := withoutAuthors(stats.AuthorLines, departed)
+		busFactor := calculateBusFactor(remaining)
+		riskAfter := classifyBusFactorRisk(busFactor, len(remaining))
+		if len(remaining) == 0 {
+			// No one left is worse than a single point of failure
+			riskAfter = "Critical"
+		}
+		impact := DepartureDirectoryImpact{
+			Path:              stats.Path,
+			BusFactorBefore:   stats.BusFactor,
+			BusFactorAfter:    busFactor,
+			RiskBefore:        stats.RiskLevel,
+			RiskAfter:         riskAfter,
+			DepartedShare:     float64(departedCount) / float64(stats.TotalLines) * 100,
+			RemainingAuthors:  len(remaining),
+			NextBestOwners:    getTopContributors(remaining, calculateAuthorContributionPercentage(remaining), 3),
+			NoRemainingAuthor: len(remaining) == 0,
+		}
+		if impact.NextBestOwners == nil {
+			impact.NextBestOwners = []AuthorContribution{}
+		}
+		sim.Directories = append(sim.Directories, impact)
+	}
+
+	for _, author := range sim.Departed {
+		if !found[author] {
+			sim.NotFound = append(sim.NotFound, author)
+		}
+	}
+
+	for path, authorCounts := range analysis.FileAuthors {
+		if len(authorCounts) > 0 && len(withoutAuthors(authorCounts, departed)) == 0 {
+			sim.OrphanedFiles = append(sim.OrphanedFiles, path)
+		}
+	}
+	sort.Strings(sim.OrphanedFiles)
+
+	// Directories left with no one first, then the largest bus factor drops
+	sort.Slice(sim.Directories, func(i, j int) bool {
+		a, b := sim.Directories[i], sim.Directories[j]
+		if a.NoRemainingAuthor != b.NoRemainingAuthor {
+			return a.NoRemainingAuthor
+		}
+		dropA, dropB := a.BusFactorBefore-a.BusFactorAfter, b.BusFactorBefore-b.BusFactorAfter
+		if dropA != dropB {
+			return dropA > dropB
+		}
+		if a.DepartedShare != b.DepartedShare {
+			return a.DepartedShare > b.DepartedShare
+		}
+		return a.Path < b.Path
+	})
+	return sim
+}
+
+// orphanedDirectoryCount counts directories with no remaining author
+func (s *DepartureSimulation) orphanedDirectoryCount() int {
+	count := 0
+	for _, d := range s.Directories {
+		if d.NoRemainingAuthor {
+			count++
+		}
+	}
+	return count
+}
+
+// printDepartureSimulation prints which directories and files lose their
+// remaining contributors and who would own the rest
+func printDepartureSimulation(sim *DepartureSimulation, unit string, limit int) {
+	fmt.Printf("\nDeparture Simulation: %s\n", strings.Join(sim.Departed, ", "))
+	if len(sim.NotFound) > 0 {
+		fmt.Printf("No contributions found for: %s\n", strings.Join(sim.NotFound, ", "))
+	}
+	fmt.Printf("Directories affected: %d (%d left with no remaining contributor)\n", len(sim.Directories), sim.orphanedDirectoryCount())
+	fmt.Printf("Files left with no remaining contributor: %d\n", len(sim.OrphanedFiles))
+
+	if len(sim.Directories) == 0 {
+		return
+	}
+
+	fmt.Printf("\nDirectory impact (showing %d):\n", min(limit, len(sim.Directories)))
+	fmt.Printf("Directory                    Bus Factor  Risk After  Departing  Next-Best Owners\n")
+	fmt.Printf("---------------------------- ----------  ----------  ---------  ------------------------------\n")
+	for i, d := range sim.Directories {
+		if i >= limit {
+			break
+		}
+		owners := "(none)"
+		if len(d.NextBestOwners) > 0 {
+			var names []string
+			for _, owner := range d.NextBestOwners {
+				names = append(names, fmt.Sprintf("%s %.0f%%", truncateAuthorName(owner.Author, 20), owner.Percentage))
+			}
+			owners = strings.Join(names, ", ")
+		}
+		fmt.Printf("%-28s %4d -> %-2d  %-10s  %8.1f%%  %s\n",
+			truncateDirectoryPath(d.Path, 28), d.BusFactorBefore, d.BusFactorAfter, d.RiskAfter, d.DepartedShare, owners)
+	}
+	fmt.Printf("\nDeparting is the share of each directory's %s held by the departing authors.\n", unit)
+
+	if len(sim.OrphanedFiles) > 0 {
+		fmt.Printf("\nFiles left with no remaining contributor (showing %d):\n", min(limit, len(sim.OrphanedFiles)))
+		for i, path := range sim.OrphanedFiles {
+			if i >= limit {
+				fmt.Printf("  ... and %d more\n", len(sim.OrphanedFiles)-limit)
+				break
+			}
+			fmt.Printf("  %s\n", path)
+		}
+	}
+}
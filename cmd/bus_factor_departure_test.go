@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func departureTestAnalysis() *BusFactorAnalysis {
+	analysis := summarizeBusFactor(map[string]map[string]int{
+		"api/":  {"alice@corp": 6, "bob@corp": 4},
+		"web/":  {"alice@corp": 5, "carol@corp": 3, "dave@corp": 2},
+		"docs/": {"carol@corp": 4},
+	}, time.Time{})
+	analysis.FileAuthors = map[string]map[string]int{
+		"api/server.go": {"alice@corp": 4, "bob@corp": 4},
+		"api/routes.go": {"alice@corp": 2},
+		"web/app.js":    {"alice@corp": 5, "carol@corp": 3, "dave@corp": 2},
+		"docs/guide.md": {"carol@corp": 4},
+	}
+	return analysis
+}
+
+func TestSimulateDeparture(t *testing.T) {
+	sim := simulateDeparture(departureTestAnalysis(), []string{"Alice@Corp", " bob@corp", "erin@corp"})
+
+	if !reflect.DeepEqual(sim.Departed, []string{"alice@corp", "bob@corp", "erin@corp"}) {
+		t.Errorf("Departed = %v", sim.Departed)
+	}
+	if !reflect.DeepEqual(sim.NotFound, []string{"erin@corp"}) {
+		t.Errorf("NotFound = %v, want [erin@corp]", sim.NotFound)
+	}
+	if !reflect.DeepEqual(sim.OrphanedFiles, []string{"api/routes.go", "api/server.go"}) {
+		t.Errorf("OrphanedFiles = %v", sim.OrphanedFiles)
+	}
+	if len(sim.Directories) != 2 {
+		t.Fatalf("got %d affected directories, want 2 (docs/ is untouched)", len(sim.Directories))
+	}
+
+	api := sim.Directories[0]
+	if api.Path != "api/" || !api.NoRemainingAuthor || api.BusFactorAfter != 0 || api.DepartedShare != 100 || api.RiskAfter != "Critical" {
+		t.Errorf("first directory = %+v, want api/ with no remaining author", api)
+	}
+	if len(api.NextBestOwners) != 0 {
+		t.Errorf("api/ next-best owners = %v, want none", api.NextBestOwners)
+	}
+
+	web := sim.Directories[1]
+	if web.Path != "web/" || web.NoRemainingAuthor || web.BusFactorBefore != 2 || web.BusFactorAfter != 1 || web.DepartedShare != 50 {
+		t.Errorf("second directory = %+v, want web/ dropping from bus factor 2 to 1 with half its commits departing", web)
+	}
+	wantOwners := []AuthorContribution{
+		{Author: "carol@corp", Lines: 3, Percentage: 60},
+		{Author: "dave@corp", Lines: 2, Percentage: 40},
+	}
+	if !reflect.DeepEqual(web.NextBestOwners, wantOwners) {
+		t.Errorf("web/ next-best owners = %v, want %v", web.NextBestOwners, wantOwners)
+	}
+}
+
+func TestSimulateDepartureNoMatch(t *testing.T) {
+	sim := simulateDeparture(departureTestAnalysis(), []string{"erin@corp"})
+	if len(sim.Directories) != 0 || len(sim.OrphanedFiles) != 0 {
+		t.Errorf("unknown author affected %d directories and %d files, want none", len(sim.Directories), len(sim.OrphanedFiles))
+	}
+}
+
+func TestSimulateDepartureByName(t *testing.T) {
+	analysis := departureTestAnalysis()
+	analysis.AuthorNames = make(map[string]map[string]bool)
+	recordAuthorName(analysis.AuthorNames, "Alice Smith", "alice@corp")
+	recordAuthorName(analysis.AuthorNames, "Alice Smith", "alice@home")
+	recordAuthorName(analysis.AuthorNames, "Bob", "bob@corp")
+
+	sim := simulateDeparture(analysis, []string{"alice smith"})
+	if !reflect.DeepEqual(sim.Departed, []string{"alice@corp", "alice@home"}) {
+		t.Errorf("Departed = %v, want both of Alice Smith's emails", sim.Departed)
+	}
+	if !reflect.DeepEqual(sim.NotFound, []string{"alice@home"}) {
+		t.Errorf("NotFound = %v, want [alice@home]", sim.NotFound)
+	}
+	if !reflect.DeepEqual(sim.OrphanedFiles, []string{"api/routes.go"}) {
+		t.Errorf("OrphanedFiles = %v, want [api/routes.go]", sim.OrphanedFiles)
+	}
+}
+
+func TestResolveDepartureIdentity(t *testing.T) {
+	names := make(map[string]map[string]bool)
+	recordAuthorName(names, "Bob Smith", "bob@corp")
+	recordAuthorName(names, "", "nobody@corp")
+
+	tests := []struct {
+		identity string
+		want     []string
+	}{
+		{"alice@corp", []string{"alice@corp"}},
+		{" Alice@Corp ", []string{"alice@corp"}},
+		{"Bob Smith", []string{"bob@corp"}},
+		{"Carol Jones", []string{"carol jones"}},
+		{" ", nil},
+	}
+	for _, tt := range tests {
+		if got := resolveDepartureIdentity(tt.identity, names); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("resolveDepartureIdentity(%q) = %v, want %v", tt.identity, got, tt.want)
+		}
+	}
+	if len(names) != 1 {
+		t.Errorf("Expected blank names to be skipped, got %v", names)
+	}
+}
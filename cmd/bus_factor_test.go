@@ -22,7 +22,10 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCalculateBusFactor(t *testing.T) {
@@ -414,3 +417,113 @@ func TestMatchesPathFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestSummarizeBusFactor(t *testing.T) {
+	// Lines per author: one author wrote the module, another made many small fixes
+	analysis := summarizeBusFactor(map[string]map[string]int{
+		"core/": {"alice": 900, "bob": 100},
+		"docs/": {"alice": 40, "bob": 35, "carol": 25},
+	}, time.Time{})
+
+	if analysis.TotalDirectories != 2 || analysis.TimeWindow != "all time" {
+		t.Fatalf("Unexpected analysis %+v", analysis)
+	}
+	core := analysis.DirectoryStats[0]
+	if core.Path != "core/" || core.BusFactor != 1 || core.TotalLines != 1000 {
+		t.Errorf("Expected core/ first with bus factor 1, got %+v", core)
+	}
+	if core.TopContributors[0].Author != "alice" || core.TopContributors[0].Percentage != 90 {
+		t.Errorf("Expected alice to own 90%% of core/, got %+v", core.TopContributors)
+	}
+	if docs := analysis.DirectoryStats[1]; docs.BusFactor != 2 {
+		t.Errorf("Expected docs/ to have bus factor 2, got %d", docs.BusFactor)
+	}
+	if len(analysis.OverallRiskDirs) != 2 {
+		t.Errorf("Expected both directories at risk, got %d", len(analysis.OverallRiskDirs))
+	}
+}
+
+func TestBusFactorDirectory(t *testing.T) {
+	tests := map[string]string{
+		"main.go":        "root",
+		"cmd/root.go":    "cmd/",
+		"cmd/sub/a.go":   "cmd/sub/",
+		"docs/README.md": "docs/",
+	}
+	for file, expected := range tests {
+		if result := busFactorDirectory(file); result != expected {
+			t.Errorf("busFactorDirectory(%q) = %q, want %q", file, result, expected)
+		}
+	}
+}
+
+func TestBusFactorMethodDifferences(t *testing.T) {
+	// bob made most commits to core/, but alice wrote most of its lines
+	commits := summarizeBusFactor(map[string]map[string]int{
+		"core/": {"alice": 2, "bob": 50},
+		"docs/": {"alice": 5},
+	}, time.Time{})
+	blame := summarizeBusFactor(map[string]map[string]int{
+		"core/": {"alice": 900, "bob": 100},
+		"docs/": {"alice": 120},
+		"new/":  {"carol": 10},
+	}, time.Time{})
+	blame.Method = busFactorMethodBlame
+
+	differences := busFactorMethodDifferences(blame, commits)
+	if len(differences) != 1 {
+		t.Fatalf("Expected only core/ to differ, got %+v", differences)
+	}
+	d := differences[0]
+	if d.Path != "core/" || d.BlameOwner != "alice" || d.CommitOwner != "bob" {
+		t.Errorf("Unexpected difference %+v", d)
+	}
+	if blame.unit() != "lines" || commits.unit() != "commits" {
+		t.Errorf("Unexpected units %q and %q", blame.unit(), commits.unit())
+	}
+}
+
+func TestBusFactorReportJSON(t *testing.T) {
+	commits := summarizeBusFactor(map[string]map[string]int{"core/": {"alice": 3}}, time.Time{})
+	blame := summarizeBusFactor(map[string]map[string]int{"core/": {"alice": 120}}, time.Time{})
+	blame.Method = busFactorMethodBlame
+
+	out, err := json.Marshal(busFactorReport{Method: busFactorMethodBlame, Commits: commits, Blame: blame})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded struct {
+		Method  string
+		Commits struct {
+			Method      string
+			Directories []struct {
+				AuthorLines map[string]int `json:"author_lines"`
+			}
+		}
+		Blame struct {
+			Method      string
+			Directories []struct {
+				AuthorLines map[string]int `json:"author_lines"`
+			}
+		}
+	}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded.Commits.Method != "commits" || decoded.Commits.Directories[0].AuthorLines["alice"] != 3 {
+		t.Errorf("Unexpected commits result in %s", out)
+	}
+	if decoded.Blame.Method != "blame" || decoded.Blame.Directories[0].AuthorLines["alice"] != 120 {
+		t.Errorf("Unexpected blame result in %s", out)
+	}
+	// Risk groupings repeat the directories, so they stay out of JSON
+	if strings.Contains(string(out), "OverallRiskDirs") {
+		t.Errorf("Expected risk groupings to be omitted, got %s", out)
+	}
+
+	// The commits method alone has no blame section
+	out, _ = json.Marshal(busFactorReport{Method: busFactorMethodCommits, Commits: commits})
+	if strings.Contains(string(out), `"blame"`) {
+		t.Errorf("Expected no blame section, got %s", out)
+	}
+}
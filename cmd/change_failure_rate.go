@@ -0,0 +1,379 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/spf13/cobra"
+)
+
+// DORA change failure rate thresholds in percent of deployments, following
+// the State of DevOps performance bands
+const (
+	eliteChangeFailureThreshold  = 15.0
+	highChangeFailureThreshold   = 30.0
+	mediumChangeFailureThreshold = 45.0
+	// Above 45% is Low performance
+)
+
+const defaultFailureWindowHours = 24
+
+// defaultFailurePatterns mark commits that repair a bad deployment
+var defaultFailurePatterns = []string{
+	`(?i)^revert\b`,
+	`(?i)this reverts commit [0-9a-f]{7,40}`,
+	`(?i)\bhot-?fix(es)?\b`,
+	`(?i)\b(rollback|roll back|rolled back)\b`,
+}
+
+// defaultFailureTagPattern marks tags that ship a hotfix
+const defaultFailureTagPattern = `(?i)hot-?fix`
+
+const (
+	failureKindRevert  = "revert"
+	failureKindMessage = "hotfix commit"
+	failureKindTag     = "hotfix tag"
+)
+
+// FailureSignal is evidence that something deployed had to be repaired
+type FailureSignal struct {
+	Hash        plumbing.Hash
+	Time        time.Time
+	Kind        string
+	Description string
+}
+
+// DeploymentOutcome is a deployment and the failure signals attributed to it
+type DeploymentOutcome struct {
+	Deployment Deployment
+	Failures   []FailureSignal
+}
+
+// Failed reports whether the deployment was followed by a failure
+func (o DeploymentOutcome) Failed() bool {
+	return len(o.Failures) > 0
+}
+
+// ChangeFailureStats summarizes deployment outcomes
+type ChangeFailureStats struct {
+	Deployments    int
+	Failed         int
+	FailureRate    float64
+	Classification string // "Elite", "High", "Medium", "Low", "Unknown"
+	SignalsByKind  map[string]int
+	Outcomes       []DeploymentOutcome
+}
+
+// classifyDORAChangeFailureRate classifies a change failure rate according to DORA benchmarks
+func classifyDORAChangeFailureRate(percent float64) string {
+	if percent <= eliteChangeFailureThreshold {
+		return "Elite"
+	} else if percent <= highChangeFailureThreshold {
+		return "High"
+	} else if percent <= mediumChangeFailureThreshold {
+		return "Medium"
+	}
+	return "Low"
+}
+
+// attributeFailures assigns each failure signal to the most recent deployment
+// before it, if the signal came within window of that deployment. A signal
+// from the deployed commit itself (a hotfix release) counts against the
+// deployment before it. deployments must be sorted oldest first.
+func attributeFailures(deployments []Deployment, signals []FailureSignal, window time.Duration) []DeploymentOutcome {
+	outcomes := make([]DeploymentOutcome, len(deployments))
+	for i, d := range deployments {
+		outcomes[i].Deployment = d
+	}
+
+	for _, signal := range signals {
+		for i := len(deployments) - 1; i >= 0; i-- {
+			d := deployments[i]
+			if d.Time.After(signal.Time) || d.Hash == signal.Hash {
+				continue
+			}
+			if signal.Time.Sub(d.Time) <= window {
+				outcomes[i].Failures = append(outcomes[i].Failures, signal)
+			}
+			break
+		}
+	}
+	return outcomes
+}
+
+// calculateChangeFailureStats computes the change failure rate of deployment outcomes
+func calculateChangeFailureStats(outcomes []DeploymentOutcome) *ChangeFailureStats {
+	stats := &ChangeFailureStats{
+		Deployments:   len(outcomes),
+		SignalsByKind: make(map[string]int),
+		Outcomes:      outcomes,
+	}
+	for _, o := range outcomes {
+		if o.Failed() {
+			stats.Failed++
+		}
+		for _, f := range o.Failures {
+			stats.SignalsByKind[f.Kind]++
+		}
+	}
+
+	if stats.Deployments == 0 {
+		stats.Classification = "Unknown"
+		return stats
+	}
+	stats.FailureRate = float64(stats.Failed) / float64(stats.Deployments) * 100
+	stats.Classification = classifyDORAChangeFailureRate(stats.FailureRate)
+	return stats
+}
+
+// commitFailureSignal classifies a commit as a failure signal, if it is one
+func commitFailureSignal(c *object.Commit, patterns []*regexp.Regexp) (FailureSignal, bool) {
+	subject := strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0]
+	signal := FailureSignal{Hash: c.Hash, Time: c.Committer.When, Description: subject}
+	if _, ok := revertedHashFromMessage(c.Message); ok || strings.HasPrefix(strings.ToLower(subject), "revert") {
+		signal.Kind = failureKindRevert
+		return signal, true
+	}
+	if matchesAnyPattern(c.Message, patterns) {
+		signal.Kind = failureKindMessage
+		return signal, true
+	}
+	return signal, false
+}
+
+// collectFailureSignals finds failure commits reachable from the production
+// head and hotfix tags, at or after since
+func collectFailureSignals(repo *git.Repository, head plumbing.Hash, since time.Time, patterns []*regexp.Regexp, tagPattern *regexp.Regexp) ([]FailureSignal, error) {
+	var signals []FailureSignal
+
+	cIter, err := repo.Log(&git.LogOptions{From: head})
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	defer cIter.Close()
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if !since.IsZero() && c.Committer.When.Before(since) {
+			return storer.ErrStop
+		}
+		if signal, ok := commitFailureSignal(c, patterns); ok {
+			signals = append(signals, signal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking commits: %v", err)
+	}
+
+	tagSignals, err := tagFailureSignals(repo, since, tagPattern)
+	if err != nil {
+		return nil, err
+	}
+	signals = append(signals, tagSignals...)
+
+	sortFailureSignals(signals)
+	return signals, nil
+}
+
+// tagFailureSignals finds hotfix tags created at or after since
+func tagFailureSignals(repo *git.Repository, since time.Time, tagPattern *regexp.Regexp) ([]FailureSignal, error) {
+	tagRefs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+	defer tagRefs.Close()
+
+	var signals []FailureSignal
+	err = tagRefs.ForEach(func(ref *plumbing.Reference) error {
+		if !tagPattern.MatchString(ref.Name().Short()) {
+			return nil
+		}
+		d, ok := resolveTagDeployment(repo, ref)
+		if !ok || (!since.IsZero() && d.Time.Before(since)) {
+			return nil
+		}
+		signals = append(signals, FailureSignal{Hash: d.Hash, Time: d.Time, Kind: failureKindTag, Description: d.Ref})
+		return nil
+	})
+	return signals, err
+}
+
+// sortFailureSignals orders failure signals oldest first
+func sortFailureSignals(signals []FailureSignal) {
+	sort.SliceStable(signals, func(i, j int) bool {
+		return signals[i].Time.Before(signals[j].Time)
+	})
+}
+
+// analyzeChangeFailureRate finds deployments and the failures that followed them
+func analyzeChangeFailureRate(repo *git.Repository, cfg DeploymentConfig, since time.Time, window time.Duration, patterns []*regexp.Regexp, tagPattern *regexp.Regexp) (*ChangeFailureStats, error) {
+	deployments, err := findDeployments(repo, cfg, since)
+	if err != nil {
+		return nil, fmt.Errorf("could not find deployments: %v", err)
+	}
+
+	ref, err := resolveDeployBranch(repo, cfg.Branch)
+	if err != nil {
+		return nil, err
+	}
+	signals, err := collectFailureSignals(repo, ref.Hash(), since, patterns, tagPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return calculateChangeFailureStats(attributeFailures(deployments, signals, window)), nil
+}
+
+// printChangeFailureStats displays the change failure rate analysis
+func printChangeFailureStats(stats *ChangeFailureStats, cfg DeploymentConfig, window time.Duration, limit int) {
+	fmt.Println("Change Failure Rate Analysis")
+	fmt.Printf("Deployments: %s\n", cfg.Describe())
+	fmt.Printf("Failure window: %.0fh after each deployment\n", window.Hours())
+	fmt.Println()
+
+	if stats.Deployments == 0 {
+		fmt.Println("No deployments found. Use --deploy-source and --tag-pattern to match how you release.")
+		return
+	}
+
+	fmt.Printf("Deployments: %d\n", stats.Deployments)
+	fmt.Printf("Followed by a failure: %d\n", stats.Failed)
+	fmt.Printf("Change failure rate: %.1f%% (DORA: %s)\n", stats.FailureRate, stats.Classification)
+	if len(stats.SignalsByKind) > 0 {
+		var kinds []string
+		for _, kind := range []string{failureKindRevert, failureKindMessage, failureKindTag} {
+			if n := stats.SignalsByKind[kind]; n > 0 {
+				kinds = append(kinds, fmt.Sprintf("%d %s", n, kind))
+			}
+		}
+		fmt.Printf("Failure signals: %s\n", strings.Join(kinds, ", "))
+	}
+
+	if stats.Failed > 0 {
+		fmt.Printf("\nFailed deployments (most recent first, showing %d):\n", min(stats.Failed, limit))
+		shown := 0
+		for i := len(stats.Outcomes) - 1; i >= 0 && shown < limit; i-- {
+			o := stats.Outcomes[i]
+			if !o.Failed() {
+				continue
+			}
+			shown++
+			d := o.Deployment
+			fmt.Printf("%d. %s (%s) %s\n", shown, d.Ref, d.Hash.String()[:7], d.Time.Format("2006-01-02 15:04"))
+			for _, f := range o.Failures {
+				fmt.Printf("   %-13s %s after: %s (%s)\n", f.Kind, formatElapsed(f.Time.Sub(d.Time)), f.Description, f.Hash.String()[:7])
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("DORA benchmarks: Elite ≤%.0f%%, High ≤%.0f%%, Medium ≤%.0f%%, Low above.\n", eliteChangeFailureThreshold, highChangeFailureThreshold, mediumChangeFailureThreshold)
+}
+
+// changeFailureRateCmd represents the change-failure-rate command
+var changeFailureRateCmd = &cobra.Command{
+	Use:   "change-failure-rate",
+	Short: "Measure the DORA change failure rate from repository signals",
+	Long: `Estimate the share of deployments that led to a failure in production.
+
+Deployments are release tags matching --tag-pattern (semver by default) or,
+with --deploy-source merges, merge commits on the production branch.
+
+A deployment failed when, within --hours of it and before the next
+deployment, the repository shows a repair:
+- a revert commit
+- a commit matching a failure pattern (hotfix, rollback by default;
+  --failure-pattern or change-failure-rate.failure_patterns in the config)
+- a tag matching --failure-tag-pattern (hotfix by default)
+
+DORA Benchmarks:
+- Elite: 0-15%
+- High: 16-30%
+- Medium: 31-45%
+- Low: >45%`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lastArg, _ := cmd.Flags().GetString("last")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		hoursArg, _ := cmd.Flags().GetInt("hours")
+		tagPatternArg, _ := cmd.Flags().GetString("failure-tag-pattern")
+		if hoursArg <= 0 {
+			return fmt.Errorf("--hours must be positive, got %d", hoursArg)
+		}
+
+		cfg, err := getDeploymentConfig(cmd, "change-failure-rate")
+		if err != nil {
+			return err
+		}
+		patterns, _ := getMessagePatterns(cmd, "failure-pattern", "change-failure-rate.failure_patterns", defaultFailurePatterns)
+		failurePatterns, err := compileMessagePatterns(patterns)
+		if err != nil {
+			return err
+		}
+		failureTagPattern, err := regexp.Compile(tagPatternArg)
+		if err != nil {
+			return fmt.Errorf("invalid failure tag pattern %q: %v", tagPatternArg, err)
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "change-failure-rate", lastArg, nil, "")
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			return fmt.Errorf("could not open repository: %v", err)
+		}
+
+		var since time.Time
+		if lastArg != "" {
+			since, err = parseDurationArg(lastArg)
+			if err != nil {
+				return fmt.Errorf("invalid time window '%s': %v", lastArg, err)
+			}
+		}
+
+		window := time.Duration(hoursArg) * time.Hour
+		stats, err := analyzeChangeFailureRate(repo, cfg, since, window, failurePatterns, failureTagPattern)
+		if err != nil {
+			return err
+		}
+
+		printChangeFailureStats(stats, cfg, window, limitArg)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changeFailureRateCmd)
+	changeFailureRateCmd.Flags().String("last", "", "Specify the time window to analyze (e.g., 30d, 6m, 1y)")
+	changeFailureRateCmd.Flags().Int("limit", 10, "Number of failed deployments to show")
+	changeFailureRateCmd.Flags().Int("hours", defaultFailureWindowHours, "Hours after a deployment within which a repair counts as its failure")
+	changeFailureRateCmd.Flags().StringSlice("failure-pattern", []string{}, "Regex matching repair commit messages (can be specified multiple times; replaces the defaults)")
+	changeFailureRateCmd.Flags().String("failure-tag-pattern", defaultFailureTagPattern, "Regex matching hotfix tags")
+	addDeploymentFlags(changeFailureRateCmd)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestClassifyDORAChangeFailureRate(t *testing.T) {
+	tests := []struct {
+		percent  float64
+		expected string
+	}{
+		{0, "Elite"},
+		{15, "Elite"},
+		{15.1, "High"},
+		{30, "High"},
+		{45, "Medium"},
+		{60, "Low"},
+	}
+
+	for _, tt := range tests {
+		if result := classifyDORAChangeFailureRate(tt.percent); result != tt.expected {
+			t.Errorf("classifyDORAChangeFailureRate(%.1f) = %s, want %s", tt.percent, result, tt.expected)
+		}
+	}
+}
+
+func TestAttributeFailures(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	deployments := []Deployment{
+		{Hash: plumbing.NewHash("01"), Time: start, Ref: "v1.0.0"},
+		{Hash: plumbing.NewHash("02"), Time: start.Add(5 * time.Hour), Ref: "v1.0.1"},
+		{Hash: plumbing.NewHash("03"), Time: start.AddDate(0, 0, 5), Ref: "v1.1.0"},
+	}
+	signals := []FailureSignal{
+		// The hotfix released as v1.0.1 counts against v1.0.0
+		{Hash: plumbing.NewHash("02"), Time: start.Add(5 * time.Hour), Kind: failureKindMessage},
+		// Three days after v1.0.1: outside the window
+		{Hash: plumbing.NewHash("04"), Time: start.AddDate(0, 0, 3), Kind: failureKindRevert},
+		// Before any deployment: ignored
+		{Hash: plumbing.NewHash("05"), Time: start.Add(-time.Hour), Kind: failureKindRevert},
+		{Hash: plumbing.NewHash("06"), Time: start.AddDate(0, 0, 5).Add(time.Hour), Kind: failureKindTag},
+	}
+
+	outcomes := attributeFailures(deployments, signals, 24*time.Hour)
+	if !outcomes[0].Failed() || outcomes[1].Failed() || !outcomes[2].Failed() {
+		t.Errorf("Expected v1.0.0 and v1.1.0 to fail, got %+v", outcomes)
+	}
+
+	stats := calculateChangeFailureStats(outcomes)
+	if stats.Deployments != 3 || stats.Failed != 2 {
+		t.Fatalf("Expected 2 of 3 deployments failed, got %d of %d", stats.Failed, stats.Deployments)
+	}
+	if stats.Classification != "Low" {
+		t.Errorf("Expected Low at %.1f%%, got %s", stats.FailureRate, stats.Classification)
+	}
+	if stats.SignalsByKind[failureKindMessage] != 1 || stats.SignalsByKind[failureKindTag] != 1 || stats.SignalsByKind[failureKindRevert] != 0 {
+		t.Errorf("Unexpected signal counts %v", stats.SignalsByKind)
+	}
+
+	if empty := calculateChangeFailureStats(nil); empty.Classification != "Unknown" {
+		t.Errorf("Expected Unknown with no deployments, got %s", empty.Classification)
+	}
+}
+
+func TestCommitFailureSignal(t *testing.T) {
+	patterns, err := compileMessagePatterns(defaultFailurePatterns)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		message string
+		kind    string
+		ok      bool
+	}{
+		{"Revert \"Add cache\"\n\nThis reverts commit abc1234.", failureKindRevert, true},
+		{"Merge branch 'hotfix/login' into main", failureKindMessage, true},
+		{"Roll back config change", failureKindMessage, true},
+		{"Add feature", "", false},
+	}
+
+	for _, tt := range tests {
+		signal, ok := commitFailureSignal(&object.Commit{Message: tt.message}, patterns)
+		if ok != tt.ok || (ok && signal.Kind != tt.kind) {
+			t.Errorf("commitFailureSignal(%q) = %q, %v, want %q, %v", tt.message, signal.Kind, ok, tt.kind, tt.ok)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -28,12 +27,6 @@ const (
 	// Below 50% elite+high+medium is Low performance
 )
 
-// Sentinel errors for iteration control
-var (
-	ErrFoundTarget = errors.New("target found")
-	ErrFoundCommit = errors.New("commit found") 
-)
-
 // CommitLeadTime represents a commit with its lead time measurement
 type CommitLeadTime struct {
 	Hash           string
@@ -43,6 +36,7 @@ type CommitLeadTime struct {
 	LeadTimeHours  float64
 	Classification string // "Elite", "High", "Medium", "Low"
 	Message        string
+	PR             int // pull request the commit landed with, 0 if none
 }
 
 // ChangeLeadTimeStats contains analysis results for change lead time
@@ -59,6 +53,8 @@ type ChangeLeadTimeStats struct {
 	SlowestCommits         []CommitLeadTime
 	FastestCommits         []CommitLeadTime
 	Commits                []CommitLeadTime
+	PullRequests           []PullRequestLeadTime
+	Stages                 LeadTimeStageStats
 }
 
 // changeLeadTimeCmd represents the change-lead-time command
@@ -82,6 +78,17 @@ Research basis:
 - Measures organizational delivery performance and flow efficiency
 - Correlates with overall software delivery performance and business outcomes
 
+Pull requests are recognized from GitHub, GitLab and Bitbucket merge commit
+messages and from squash titles ending in "(#1234)". Their lead time is split
+into stages: coding (first to last commit on the branch), review (last commit
+to merge) and release (merge to the first tag containing it). Use
+--group-by pr to list the slowest pull requests instead of commits.
+
+With --method deploy, lead time runs to the first deployment whose commit
+contains the change, following the repository graph. Deployments come from
+the shared deployments config or the --deploy-* flags, typically a deploy log
+exported from your CD system; the release stage then ends at that deployment.
+
 The analysis identifies:
 - Lead time distribution across DORA performance levels
 - Delivery flow bottlenecks and optimization opportunities
@@ -97,16 +104,35 @@ The analysis identifies:
 		lastArg, _ := cmd.Flags().GetString("last")
 		limitArg, _ := cmd.Flags().GetInt("limit")
 		methodArg, _ := cmd.Flags().GetString("method")
+		groupByArg, _ := cmd.Flags().GetString("group-by")
+		if groupByArg != "commit" && groupByArg != "pr" {
+			return fmt.Errorf("invalid --group-by value %q (expected commit or pr)", groupByArg)
+		}
 		
+		var deployCfg DeploymentConfig
+		if methodArg == "deploy" {
+			if deployCfg, err = getDeploymentConfig(cmd, "change-lead-time"); err != nil {
+				return err
+			}
+		}
+
 		// Print configuration scope
 		printCommandScope(cmd, "change-lead-time", lastArg, pathFilters, source)
 
-		stats, err := analyzeChangeLeadTime(repo, pathFilters, lastArg, limitArg, methodArg)
+		var deployments []Deployment
+		if methodArg == "deploy" {
+			if deployments, err = findDeployments(repo, deployCfg, time.Time{}); err != nil {
+				return fmt.Errorf("could not find deployments: %v", err)
+			}
+			fmt.Printf("Deployments: %s (%d found)\n\n", deployCfg.Describe(), len(deployments))
+		}
+
+		stats, err := analyzeChangeLeadTime(repo, pathFilters, lastArg, limitArg, methodArg, deployments)
 		if err != nil {
 			return err
 		}
 
-		printChangeLeadTimeStats(stats, limitArg)
+		printChangeLeadTimeStats(stats, limitArg, groupByArg)
 		return nil
 	},
 }
@@ -116,11 +142,14 @@ func init() {
 	changeLeadTimeCmd.Flags().String("last", "", "Specify the time window to analyze (e.g., 30d, 6m, 1y)")
 	changeLeadTimeCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
 	changeLeadTimeCmd.Flags().Int("limit", 5, "Number of slowest/fastest commits to show in detailed output")
-	changeLeadTimeCmd.Flags().String("method", "merge", "Lead time calculation method: 'merge' (commit to main) or 'tag' (commit to release tag)")
+	changeLeadTimeCmd.Flags().String("method", "merge", "Lead time calculation method: 'merge' (commit to main), 'tag' (commit to release tag) or 'deploy' (commit to first deployment)")
+	changeLeadTimeCmd.Flags().String("group-by", "commit", "List slowest and fastest changes by 'commit' or by pull request ('pr')")
+	addDeploymentFlags(changeLeadTimeCmd)
 }
 
-// analyzeChangeLeadTime performs the main lead time analysis
-func analyzeChangeLeadTime(repo *git.Repository, pathFilters []string, lastArg string, limitArg int, method string) (*ChangeLeadTimeStats, error) {
+// analyzeChangeLeadTime performs the main lead time analysis. deployments are
+// only used by the deploy method.
+func analyzeChangeLeadTime(repo *git.Repository, pathFilters []string, lastArg string, limitArg int, method string, deployments []Deployment) (*ChangeLeadTimeStats, error) {
 	// Parse time window if provided
 	var cutoffTime time.Time
 	var err error
@@ -131,30 +160,56 @@ func analyzeChangeLeadTime(repo *git.Repository, pathFilters []string, lastArg s
 		}
 	}
 
+	// Get the default branch (usually main/master)
+	defaultBranch, err := getDefaultBranch(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default branch: %v", err)
+	}
+
+	// Index the branch once so merge and tag lookups don't re-walk history
+	index, err := buildCommitIndex(repo, defaultBranch.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to index commits: %v", err)
+	}
+	if method == "deploy" {
+		// Deployments stand in for tags, so the first one containing a
+		// commit is found the same way
+		index.setTags(deployments)
+	}
+	pullRequests, err := chainPullRequestNumbers(repo, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read merge commits: %v", err)
+	}
+
 	// Get commits with lead time measurements
-	commits, err := getCommitsWithLeadTime(repo, cutoffTime, pathFilters, method)
+	commits, err := getCommitsWithLeadTime(repo, index, cutoffTime, pathFilters, method, pullRequests)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze lead time: %v", err)
 	}
 
 	// Calculate comprehensive statistics
 	stats := calculateChangeLeadTimeStats(commits)
+
+	// Break pull request lead time into stages
+	stats.PullRequests, err = pullRequestLeadTimes(repo, index, pullRequests, cutoffTime, pathFilters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze pull requests: %v", err)
+	}
+	stats.Stages = calculateLeadTimeStageStats(stats.PullRequests)
+	if method == "deploy" {
+		stats.Stages.ReleasedBy = "deploy"
+	}
 	
 	return stats, nil
 }
 
-// getCommitsWithLeadTime retrieves commits and calculates their lead times
-func getCommitsWithLeadTime(repo *git.Repository, cutoffTime time.Time, pathFilters []string, method string) ([]CommitLeadTime, error) {
+// getCommitsWithLeadTime retrieves commits and calculates their lead times.
+// pullRequests maps chain positions of the index to pull request numbers.
+func getCommitsWithLeadTime(repo *git.Repository, index *commitIndex, cutoffTime time.Time, pathFilters []string, method string, pullRequests map[int]int) ([]CommitLeadTime, error) {
 	var commits []CommitLeadTime
 
-	// Get the default branch (usually main/master)
-	defaultBranch, err := getDefaultBranch(repo)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get default branch: %v", err)
-	}
-
 	// Get commit iterator from default branch
-	commitIter, err := repo.Log(&git.LogOptions{From: defaultBranch.Hash()})
+	commitIter, err := repo.Log(&git.LogOptions{From: index.head()})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit log: %v", err)
 	}
@@ -186,7 +241,7 @@ func getCommitsWithLeadTime(repo *git.Repository, cutoffTime time.Time, pathFilt
 		switch method {
 		case "merge":
 			// For merge method, find when this commit was merged to main branch
-			mergeTime, err := findCommitMergeTime(repo, commit.Hash)
+			mergeTime, err := findCommitMergeTime(index, commit.Hash)
 			if err != nil {
 				// If merge time cannot be determined, use commit time as fallback
 				deployTime = commit.Author.When
@@ -197,15 +252,23 @@ func getCommitsWithLeadTime(repo *git.Repository, cutoffTime time.Time, pathFilt
 			}
 		case "tag":
 			// For tag method, find when this commit was tagged
-			tagTime, err := findCommitInTags(repo, commit.Hash)
+			tagTime, err := findCommitInTags(index, commit.Hash)
 			if err != nil {
 				return nil // Skip commits not found in tags
 			}
 			deployTime = tagTime
 			leadTime = calculateLeadTime(commit.Author.When, tagTime)
+		case "deploy":
+			// For deploy method, find the first deployment containing this commit
+			deployedAt, err := findCommitDeployment(index, commit.Hash)
+			if err != nil {
+				return nil // Skip commits not deployed yet
+			}
+			deployTime = deployedAt
+			leadTime = calculateLeadTime(commit.Author.When, deployedAt)
 		default:
 			// Default to merge method with proper calculation
-			mergeTime, err := findCommitMergeTime(repo, commit.Hash)
+			mergeTime, err := findCommitMergeTime(index, commit.Hash)
 			if err != nil {
 				deployTime = commit.Author.When
 				leadTime = 0
@@ -224,6 +287,9 @@ func getCommitsWithLeadTime(repo *git.Repository, cutoffTime time.Time, pathFilt
 			Classification: classifyDORALeadTime(leadTime),
 			Message:        commit.Message,
 		}
+		if pos, ok := index.landed[commit.Hash]; ok {
+			commitLeadTime.PR = pullRequests[pos]
+		}
 
 		commits = append(commits, commitLeadTime)
 		return nil
@@ -261,141 +327,40 @@ func getDefaultBranch(repo *git.Repository) (*plumbing.Reference, error) {
 	return nil, fmt.Errorf("could not determine default branch")
 }
 
-// findCommitMergeTime finds when a commit was merged to the main branch
-func findCommitMergeTime(repo *git.Repository, commitHash plumbing.Hash) (time.Time, error) {
-	// Get the default branch
-	defaultBranch, err := getDefaultBranch(repo)
-	if err != nil {
-		return time.Time{}, err
-	}
-
-	// Get all commits on the default branch
-	commitIter, err := repo.Log(&git.LogOptions{From: defaultBranch.Hash()})
-	if err != nil {
-		return time.Time{}, err
-	}
-	defer commitIter.Close()
-
-	var foundMergeTime time.Time
-	found := false
-
-	// Look for merge commits that include our target commit
-	err = commitIter.ForEach(func(commit *object.Commit) error {
-		// If this is our target commit directly on main, use its commit time
-		if commit.Hash == commitHash {
-			foundMergeTime = commit.Author.When
-			found = true
-			return ErrFoundTarget // Break iteration
-		}
-
-		// Check merge commits (have multiple parents)
-		if len(commit.ParentHashes) > 1 {
-			// Check if our target commit is in any of the parent branches
-			for _, parentHash := range commit.ParentHashes {
-				isReachable, err := isCommitReachableFrom(repo, commitHash, parentHash)
-				if err == nil && isReachable {
-					// Check if this parent is not the main branch (first parent)
-					if parentHash != commit.ParentHashes[0] {
-						foundMergeTime = commit.Author.When
-						found = true
-						return ErrFoundTarget // Break iteration
-					}
-				}
-			}
-		}
-
-		return nil
-	})
-
-	// Check if we found the target (ignore sentinel errors used for flow control)
-	if err != nil && err != ErrFoundTarget {
-		return time.Time{}, err
+// findCommitMergeTime finds when a commit was merged to the main branch: the
+// time of the first first-parent commit that contains it
+func findCommitMergeTime(index *commitIndex, commitHash plumbing.Hash) (time.Time, error) {
+	if mergeTime, ok := index.landedTime(commitHash); ok {
+		return mergeTime, nil
 	}
-
-	if found {
-		return foundMergeTime, nil
-	}
-
-	// If no merge found, this might be a direct commit or we can't determine merge time
 	return time.Time{}, fmt.Errorf("commit merge time not determinable")
 }
 
-// isCommitReachableFrom checks if targetCommit is reachable from fromCommit
-func isCommitReachableFrom(repo *git.Repository, targetCommit, fromCommit plumbing.Hash) (bool, error) {
-	// Start from fromCommit and traverse backwards
-	commitIter, err := repo.Log(&git.LogOptions{From: fromCommit})
+// findCommitInTags finds when a commit was first tagged: the time of the
+// earliest tag containing it
+func findCommitInTags(index *commitIndex, commitHash plumbing.Hash) (time.Time, error) {
+	tagTime, found, err := index.firstTagTime(commitHash)
 	if err != nil {
-		return false, err
+		return time.Time{}, err
 	}
-	defer commitIter.Close()
-
-	found := false
-	err = commitIter.ForEach(func(commit *object.Commit) error {
-		if commit.Hash == targetCommit {
-			found = true
-			return ErrFoundCommit // Break iteration
-		}
-		return nil
-	})
-
-	// Check for actual errors (ignore sentinel error used for flow control)
-	if err != nil && err != ErrFoundCommit {
-		return false, err
+	if !found {
+		return time.Time{}, fmt.Errorf("commit not found in tags")
 	}
-
-	return found, nil
+	return tagTime, nil
 }
 
-// findCommitInTags finds when a commit was first tagged (basic implementation)
-func findCommitInTags(repo *git.Repository, commitHash plumbing.Hash) (time.Time, error) {
-	tagRefs, err := repo.Tags()
-	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to get tags: %w", err)
-	}
-	defer tagRefs.Close()
-
-	var earliestTagTime time.Time
-	found := false
-
-	err = tagRefs.ForEach(func(tagRef *plumbing.Reference) error {
-		tagObj, err := repo.TagObject(tagRef.Hash())
-		if err != nil {
-			// Might be a lightweight tag, try commit object
-			commit, err := repo.CommitObject(tagRef.Hash())
-			if err != nil {
-				return nil // Skip this tag
-			}
-			
-			// Check if our commit is reachable from this tag
-			isReachable, err := isCommitReachableFrom(repo, commitHash, commit.Hash)
-			if err == nil && isReachable {
-				if !found || commit.Author.When.Before(earliestTagTime) {
-					earliestTagTime = commit.Author.When
-					found = true
-				}
-			}
-			return nil
-		}
-
-		// Annotated tag
-		if tagObj.TargetType == plumbing.CommitObject && tagObj.Target == commitHash {
-			if !found || tagObj.Tagger.When.Before(earliestTagTime) {
-				earliestTagTime = tagObj.Tagger.When
-				found = true
-			}
-		}
-		return nil
-	})
-
+// findCommitDeployment finds when a commit was first deployed: the time of
+// the earliest deployment containing it. The index must hold the deployments
+// in place of tags.
+func findCommitDeployment(index *commitIndex, commitHash plumbing.Hash) (time.Time, error) {
+	deployedAt, found, err := index.firstTagTime(commitHash)
 	if err != nil {
 		return time.Time{}, err
 	}
-	
 	if !found {
-		return time.Time{}, fmt.Errorf("commit not found in tags")
+		return time.Time{}, fmt.Errorf("commit not found in deployments")
 	}
-
-	return earliestTagTime, nil
+	return deployedAt, nil
 }
 
 // calculateLeadTime calculates lead time in hours between two timestamps
@@ -536,7 +501,7 @@ func calculatePercentile(values []float64, percentile int) float64 {
 }
 
 // printChangeLeadTimeStats displays the analysis results
-func printChangeLeadTimeStats(stats *ChangeLeadTimeStats, limit int) {
+func printChangeLeadTimeStats(stats *ChangeLeadTimeStats, limit int, groupBy string) {
 	fmt.Println("Change Lead Time Analysis")
 	fmt.Printf("Total commits analyzed: %d\n", stats.TotalCommits)
 
@@ -568,8 +533,12 @@ func printChangeLeadTimeStats(stats *ChangeLeadTimeStats, limit int) {
 	fmt.Println("Context: DORA research shows lead time is a key predictor of software delivery performance.")
 	fmt.Println()
 
+	// Pull request stages; grouping by pull request replaces the commit lists
+	byPR := groupBy == "pr"
+	printLeadTimeStages(stats, limit, byPR)
+
 	// Fastest commits
-	if len(stats.FastestCommits) > 0 {
+	if len(stats.FastestCommits) > 0 && !byPR {
 		displayLimit := len(stats.FastestCommits)
 		if displayLimit > limit {
 			displayLimit = limit
@@ -585,7 +554,7 @@ func printChangeLeadTimeStats(stats *ChangeLeadTimeStats, limit int) {
 	}
 
 	// Slowest commits
-	if len(stats.SlowestCommits) > 0 {
+	if len(stats.SlowestCommits) > 0 && !byPR {
 		displayLimit := len(stats.SlowestCommits)
 		if displayLimit > limit {
 			displayLimit = limit
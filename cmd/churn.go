@@ -3,9 +3,11 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/spf13/cobra"
@@ -18,43 +20,73 @@ const (
 	churnCautionThreshold  = 15
 )
 
+const (
+	churnPeriodWeek  = "week"
+	churnPeriodMonth = "month"
+)
+
+// ChurnPeriod holds the churn of one week or month. Churn is measured against
+// the LOC at the start of the period, taken from the mainline tree at that time.
+type ChurnPeriod struct {
+	Key          string
+	Start        time.Time
+	End          time.Time
+	Additions    int
+	Deletions    int
+	StartLOC     int
+	ChurnPercent float64
+}
+
+// Net returns the lines the period added to the codebase
+func (p ChurnPeriod) Net() int {
+	return p.Additions - p.Deletions
+}
+
+// churnChange is one commit's contribution to a churn timeline
+type churnChange struct {
+	When      time.Time
+	Additions int
+	Deletions int
+}
+
+// processCommitDiffs sums the lines a commit added and deleted under the active merge policy
 func processCommitDiffs(c *object.Commit, pathFilters []string) (int, int) {
 	var additions, deletions int
-	if c.NumParents() == 0 {
+	changes, err := commitLineChanges(c, pathFilters)
+	if err != nil {
+		log.Printf("failed to diff commit %s: %v", c.Hash.String(), err)
 		return 0, 0
 	}
-	parents := c.Parents()
-	defer parents.Close()
-	parents.ForEach(func(parent *object.Commit) error {
-		patch, err := parent.Patch(c)
-		if err != nil {
-			log.Printf("failed to generate patch between parent %s and commit %s: %v", parent.Hash.String(), c.Hash.String(), err)
-			return nil
-		}
-		for _, stat := range patch.Stats() {
-			if !matchesPathFilter(stat.Name, pathFilters) {
-				continue
-			}
-			additions += stat.Addition
-			deletions += stat.Deletion
-		}
-		return nil
-	})
+	for _, delta := range changes {
+		additions += delta.Additions
+		deletions += delta.Deletions
+	}
 	return additions, deletions
 }
 
-
 // churnCmd represents the churn command
 var churnCmd = &cobra.Command{
 	Use:   "churn",
 	Short: "Show additions vs deletions ratio in the repo",
 	Long: `Analyze git history to show how much code was added vs deleted. 
 This helps you understand whether your repo is growing sustainably 
-or accumulating complexity.`,
+or accumulating complexity.
+
+With --period week|month, a timeline shows each period's additions,
+deletions and net growth, with churn measured against the LOC at the start
+of that period rather than today's LOC.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse flags
 		lastArg, _ := cmd.Flags().GetString("last")
+		periodArg, _ := cmd.Flags().GetString("period")
 		pathFilters, source := getConfigPaths(cmd, "churn.paths")
+		countMode, err := getCountMode(cmd)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if periodArg != "" && periodArg != churnPeriodWeek && periodArg != churnPeriodMonth {
+			log.Fatalf("Invalid --period value %q (expected week or month)", periodArg)
+		}
 		
 		// Print configuration scope
 		printCommandScope(cmd, "churn", lastArg, pathFilters, source)
@@ -69,7 +101,7 @@ or accumulating complexity.`,
 			log.Fatalf("Could not get HEAD: %v", err)
 		}
 
-		cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+		cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
 		if err != nil {
 			log.Fatalf("Could not get commits: %v", err)
 		}
@@ -84,6 +116,7 @@ or accumulating complexity.`,
 		}
 
 		var additions, deletions int
+		var changes []churnChange
 		err = cIter.ForEach(func(c *object.Commit) error {
 			if !since.IsZero() && c.Committer.When.Before(since) {
 				return storer.ErrStop
@@ -91,6 +124,9 @@ or accumulating complexity.`,
 			a, d := processCommitDiffs(c, pathFilters)
 			additions += a
 			deletions += d
+			if a > 0 || d > 0 {
+				changes = append(changes, churnChange{When: c.Committer.When, Additions: a, Deletions: d})
+			}
 			return nil
 		})
 		if err != nil {
@@ -102,52 +138,33 @@ or accumulating complexity.`,
 		if err != nil {
 			log.Fatalf("Could not get HEAD commit: %v", err)
 		}
-		tree, err := headCommit.Tree()
+		totalLOC, err := commitLOC(headCommit, pathFilters, countMode)
 		if err != nil {
-			log.Fatalf("Could not get HEAD tree: %v", err)
+			log.Fatalf("Could not count LOC: %v", err)
 		}
-		var totalLOC int
-		err = tree.Files().ForEach(func(f *object.File) error {
-			// Skip binary files
-			isBinary, err := f.IsBinary()
-			if err != nil {
-				return nil
-			}
-			if isBinary {
-				return nil
-			}
-			// Optionally filter by path
-			if !matchesPathFilter(f.Name, pathFilters) {
-				return nil
-			}
-			content, err := f.Contents()
-			if err != nil {
-				return nil
+
+		if periodArg != "" {
+			periods := bucketChurnByPeriod(changes, periodArg)
+			if err := fillChurnPeriodBaselines(headCommit, periods, pathFilters, countMode); err != nil {
+				log.Fatalf("Could not count LOC at period start: %v", err)
 			}
-			totalLOC += countLines(content)
-			return nil
-		})
-		if err != nil {
-			log.Fatalf("Could not count LOC: %v", err)
+			printChurnTimeline(periods, periodArg)
 		}
 
 		churnPercent := 0.0
 		if totalLOC > 0 {
 			churnPercent = float64(additions+deletions) / float64(totalLOC) * 100
 		}
-		status := ""
-		if churnPercent <= float64(churnHealthyThreshold) {
-			status = fmt.Sprintf("Healthy (≤%d%%)", churnHealthyThreshold)
-		} else if churnPercent <= float64(churnCautionThreshold) {
-			status = fmt.Sprintf("Caution (%d–%d%%)", churnHealthyThreshold, churnCautionThreshold)
-		} else {
-			status = fmt.Sprintf("Warning (>%d%%)", churnCautionThreshold)
-		}
+		status := classifyChurnPercent(churnPercent)
 
 		fmt.Printf("Additions vs Deletions:\n")
 		fmt.Printf("- Additions: %d lines\n", additions)
 		fmt.Printf("- Deletions: %d lines\n", deletions)
-		fmt.Printf("- Total LOC: %d lines\n", totalLOC)
+		if countMode == countModeCode {
+			fmt.Printf("- Total LOC: %d lines (code only)\n", totalLOC)
+		} else {
+			fmt.Printf("- Total LOC: %d lines\n", totalLOC)
+		}
 		fmt.Printf("Churn = (Additions + Deletions) / Total LOC\n")
 		fmt.Printf("Churn: %.2f%% — %s\n", churnPercent, status)
 		fmt.Println("Context:", churnBenchmarkContext)
@@ -158,4 +175,140 @@ func init() {
 	rootCmd.AddCommand(churnCmd)
 	churnCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
 	churnCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
-}
\ No newline at end of file
+	churnCmd.Flags().String("period", "", "Break churn down by period (week, month)")
+	addCountFlag(churnCmd)
+}
+
+// classifyChurnPercent returns the status label for a churn percentage
+func classifyChurnPercent(churnPercent float64) string {
+	if churnPercent <= float64(churnHealthyThreshold) {
+		return fmt.Sprintf("Healthy (≤%d%%)", churnHealthyThreshold)
+	} else if churnPercent <= float64(churnCautionThreshold) {
+		return fmt.Sprintf("Caution (%d–%d%%)", churnHealthyThreshold, churnCautionThreshold)
+	}
+	return fmt.Sprintf("Warning (>%d%%)", churnCautionThreshold)
+}
+
+// commitLOC counts the lines in a commit's tree
+func commitLOC(c *object.Commit, pathFilters []string, countMode string) (int, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return 0, err
+	}
+	sizes, err := treeFileSizes(tree, pathFilters, countMode)
+	if err != nil {
+		return 0, err
+	}
+	var total int
+	for _, loc := range sizes {
+		total += loc
+	}
+	return total, nil
+}
+
+// churnPeriodBounds returns the UTC period containing t
+func churnPeriodBounds(t time.Time, period string) (start, end time.Time, key string) {
+	if period == churnPeriodMonth {
+		utcTime := t.UTC()
+		start = time.Date(utcTime.Year(), utcTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+		return start, end, start.Format("2006-01")
+	}
+	start, end, _ = calculateISOWeekPeriod(t)
+	year, week := start.ISOWeek()
+	return start, end, fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// bucketChurnByPeriod sums changes per period, zero-filling quiet periods
+// between the first and last change
+func bucketChurnByPeriod(changes []churnChange, period string) []ChurnPeriod {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	sorted := make([]churnChange, len(changes))
+	copy(sorted, changes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].When.Before(sorted[j].When)
+	})
+
+	var periods []ChurnPeriod
+	for _, change := range sorted {
+		_, _, key := churnPeriodBounds(change.When, period)
+		if len(periods) == 0 {
+			start, end, _ := churnPeriodBounds(change.When, period)
+			periods = append(periods, ChurnPeriod{Key: key, Start: start, End: end})
+		}
+		for periods[len(periods)-1].Key != key {
+			start, end, k := churnPeriodBounds(periods[len(periods)-1].End.Add(time.Nanosecond), period)
+			periods = append(periods, ChurnPeriod{Key: k, Start: start, End: end})
+		}
+		periods[len(periods)-1].Additions += change.Additions
+		periods[len(periods)-1].Deletions += change.Deletions
+	}
+	return periods
+}
+
+// fillChurnPeriodBaselines sets each period's StartLOC from the last mainline
+// (first-parent) commit before the period began, and its churn against that.
+// Periods that start before the first commit keep a StartLOC of zero.
+func fillChurnPeriodBaselines(head *object.Commit, periods []ChurnPeriod, pathFilters []string, countMode string) error {
+	locByCommit := make(map[plumbing.Hash]int)
+	c := head
+	for i := len(periods) - 1; i >= 0; i-- {
+		for c != nil && !c.Committer.When.Before(periods[i].Start) {
+			if c.NumParents() == 0 {
+				c = nil
+				break
+			}
+			parent, err := c.Parent(0)
+			if err == plumbing.ErrObjectNotFound {
+				// Shallow clone: nothing is known before this commit
+				c = nil
+				break
+			}
+			if err != nil {
+				return err
+			}
+			c = parent
+		}
+		if c == nil {
+			continue
+		}
+
+		loc, ok := locByCommit[c.Hash]
+		if !ok {
+			var err error
+			loc, err = commitLOC(c, pathFilters, countMode)
+			if err != nil {
+				return err
+			}
+			locByCommit[c.Hash] = loc
+		}
+		periods[i].StartLOC = loc
+		if loc > 0 {
+			periods[i].ChurnPercent = float64(periods[i].Additions+periods[i].Deletions) / float64(loc) * 100
+		}
+	}
+	return nil
+}
+
+// printChurnTimeline prints one row per period
+func printChurnTimeline(periods []ChurnPeriod, period string) {
+	if len(periods) == 0 {
+		fmt.Printf("No changes to show by %s.\n\n", period)
+		return
+	}
+
+	fmt.Printf("Churn by %s (relative to LOC at the start of each %s):\n", period, period)
+	fmt.Printf("%-10s %10s %10s %10s %10s %8s  %s\n", "Period", "Start LOC", "Additions", "Deletions", "Net", "Churn", "Status")
+	for _, p := range periods {
+		churn, status := "n/a", "New code"
+		if p.StartLOC > 0 {
+			churn = fmt.Sprintf("%.2f%%", p.ChurnPercent)
+			status = classifyChurnPercent(p.ChurnPercent)
+		}
+		fmt.Printf("%-10s %10d %10d %10d %+10d %8s  %s\n", p.Key, p.StartLOC, p.Additions, p.Deletions, p.Net(), churn, status)
+	}
+	fmt.Println()
+}
@@ -140,58 +140,25 @@ func aggregateDirectoryChurn(files []FileChurnStats) []DirectoryChurnStats {
 func processCommitForFileChurn(c *object.Commit, pathFilters []string) (map[string]FileChurnStats, error) {
 	fileStats := make(map[string]FileChurnStats)
 	
-	if c.NumParents() == 0 {
-		return fileStats, nil
+	changes, err := commitLineChanges(c, pathFilters)
+	if err != nil {
+		return fileStats, err
 	}
 	
-	parents := c.Parents()
-	defer parents.Close()
-	
-	parentCount := 0
-	err := parents.ForEach(func(parent *object.Commit) error {
-		parentCount++
-		patch, err := parent.Patch(c)
-		if err != nil {
-			log.Printf("failed to generate patch between parent %s and commit %s: %v", parent.Hash.String(), c.Hash.String(), err)
-			return nil
-		}
-		
-		for _, stat := range patch.Stats() {
-			if !matchesPathFilter(stat.Name, pathFilters) {
-				continue
-			}
-			
-			if existing, exists := fileStats[stat.Name]; exists {
-				existing.Additions += stat.Addition
-				existing.Deletions += stat.Deletion
-				fileStats[stat.Name] = existing
-			} else {
-				fileStats[stat.Name] = FileChurnStats{
-					Path:      stat.Name,
-					Additions: stat.Addition,
-					Deletions: stat.Deletion,
-				}
-			}
-		}
-		return nil
-	})
-	
-	// For merge commits, we need to avoid double-counting line changes
-	// that appear in multiple parent diffs. Since we're already tracking unique files,
-	// we only need to adjust line counts to estimate actual changes in the merge.
-	if parentCount > 1 {
-		for path := range fileStats {
-			stats := fileStats[path]
-			stats.Additions, stats.Deletions = applyMergeCommitAdjustment(stats.Additions, stats.Deletions, parentCount)
-			fileStats[path] = stats
+	for path, delta := range changes {
+		fileStats[path] = FileChurnStats{
+			Path:      path,
+			Additions: delta.Additions,
+			Deletions: delta.Deletions,
 		}
 	}
 	
-	return fileStats, err
+	return fileStats, nil
 }
 
 // getCurrentFileSizes gets the current size (LOC) of all files in the repository.
-func getCurrentFileSizes(repo *git.Repository, pathFilters []string) (map[string]int, error) {
+// countMode selects which lines count toward LOC (see line_classifier.go).
+func getCurrentFileSizes(repo *git.Repository, pathFilters []string, countMode string) (map[string]int, error) {
 	ref, err := repo.Head()
 	if err != nil {
 		return nil, fmt.Errorf("could not get HEAD: %v", err)
@@ -207,8 +174,13 @@ func getCurrentFileSizes(repo *git.Repository, pathFilters []string) (map[string
 		return nil, fmt.Errorf("could not get HEAD tree: %v", err)
 	}
 	
+	return treeFileSizes(tree, pathFilters, countMode)
+}
+
+// treeFileSizes returns the line count of every non-binary file in a tree
+func treeFileSizes(tree *object.Tree, pathFilters []string, countMode string) (map[string]int, error) {
 	fileSizes := make(map[string]int)
-	err = tree.Files().ForEach(func(f *object.File) error {
+	err := tree.Files().ForEach(func(f *object.File) error {
 		// Skip binary files
 		isBinary, err := f.IsBinary()
 		if err != nil {
@@ -228,7 +200,7 @@ func getCurrentFileSizes(repo *git.Repository, pathFilters []string) (map[string
 			return nil
 		}
 		
-		fileSizes[f.Name] = countLines(content)
+		fileSizes[f.Name] = countFileLines(f.Name, content, countMode)
 		return nil
 	})
 	
@@ -282,6 +254,10 @@ Thresholds:
 		pathFilters, source := getConfigPaths(cmd, "churn-files.paths")
 		limitArg, _ := cmd.Flags().GetInt("limit")
 		showDirsArg, _ := cmd.Flags().GetBool("directories")
+		countMode, err := getCountMode(cmd)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
 		
 		// Print configuration scope
 		printCommandScope(cmd, "churn-files", lastArg, pathFilters, source)
@@ -302,7 +278,7 @@ Thresholds:
 		}
 
 		// Get current file sizes
-		fileSizes, err := getCurrentFileSizes(repo, pathFilters)
+		fileSizes, err := getCurrentFileSizes(repo, pathFilters, countMode)
 		if err != nil {
 			log.Fatalf("Could not get current file sizes: %v", err)
 		}
@@ -313,7 +289,7 @@ Thresholds:
 			log.Fatalf("Could not get HEAD: %v", err)
 		}
 
-		cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+		cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
 		if err != nil {
 			log.Fatalf("Could not get commits: %v", err)
 		}
@@ -370,6 +346,9 @@ Thresholds:
 		if len(pathFilters) > 0 {
 			fmt.Printf("Path filters: %s\n", strings.Join(pathFilters, ", "))
 		}
+		if countMode == countModeCode {
+			fmt.Printf("LOC: code lines only (comments and blank lines excluded)\n")
+		}
 		fmt.Printf("Threshold: >%d%% churn flags instability\n", churnFilesCautionThreshold)
 		fmt.Println("Context:", churnFilesBenchmarkContext)
 
@@ -387,5 +366,6 @@ func init() {
 	churnFilesCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
 	churnFilesCmd.Flags().Int("limit", 10, "Number of top results to show")
 	churnFilesCmd.Flags().Bool("directories", false, "Also show directory-level churn statistics")
+	addCountFlag(churnFilesCmd)
 	rootCmd.AddCommand(churnFilesCmd)
 }
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChurnPeriodBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		when          time.Time
+		period        string
+		expectedKey   string
+		expectedStart time.Time
+	}{
+		{"month", time.Date(2025, 3, 17, 15, 0, 0, 0, time.UTC), churnPeriodMonth, "2025-03", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"month in another zone", time.Date(2025, 4, 1, 1, 0, 0, 0, time.FixedZone("CEST", 2*3600)), churnPeriodMonth, "2025-03", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2025, 3, 13, 9, 0, 0, 0, time.UTC), churnPeriodWeek, "2025-W11", time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"week spanning new year", time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), churnPeriodWeek, "2025-W01", time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, key := churnPeriodBounds(tt.when, tt.period)
+			if key != tt.expectedKey {
+				t.Errorf("Expected key %s, got %s", tt.expectedKey, key)
+			}
+			if !start.Equal(tt.expectedStart) {
+				t.Errorf("Expected start %v, got %v", tt.expectedStart, start)
+			}
+			if !end.After(tt.when) || start.After(tt.when) {
+				t.Errorf("Period %v–%v does not contain %v", start, end, tt.when)
+			}
+		})
+	}
+}
+
+func TestBucketChurnByPeriod(t *testing.T) {
+	changes := []churnChange{
+		{When: time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC), Additions: 5, Deletions: 1},
+		{When: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Additions: 100},
+		{When: time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC), Additions: 10, Deletions: 20},
+	}
+
+	periods := bucketChurnByPeriod(changes, churnPeriodMonth)
+	var keys []string
+	for _, p := range periods {
+		keys = append(keys, p.Key)
+	}
+	if strings.Join(keys, ",") != "2025-01,2025-02,2025-03" {
+		t.Fatalf("Expected zero-filled months 2025-01..2025-03, got %v", keys)
+	}
+
+	if periods[0].Additions != 110 || periods[0].Deletions != 20 || periods[0].Net() != 90 {
+		t.Errorf("January: expected +110/-20 net 90, got +%d/-%d net %d", periods[0].Additions, periods[0].Deletions, periods[0].Net())
+	}
+	if periods[1].Additions != 0 || periods[1].Deletions != 0 {
+		t.Errorf("February should be empty, got +%d/-%d", periods[1].Additions, periods[1].Deletions)
+	}
+
+	var additions, deletions int
+	for _, p := range periods {
+		additions += p.Additions
+		deletions += p.Deletions
+	}
+	if additions != 115 || deletions != 21 {
+		t.Errorf("Period totals should match the whole window, got +%d/-%d", additions, deletions)
+	}
+
+	if periods := bucketChurnByPeriod(nil, churnPeriodWeek); len(periods) != 0 {
+		t.Errorf("Expected no periods without changes, got %d", len(periods))
+	}
+}
+
+func TestClassifyChurnPercent(t *testing.T) {
+	tests := []struct {
+		percent  float64
+		expected string
+	}{
+		{0, "Healthy"},
+		{5, "Healthy"},
+		{10, "Caution"},
+		{15.5, "Warning"},
+	}
+
+	for _, tt := range tests {
+		if result := classifyChurnPercent(tt.percent); !strings.HasPrefix(result, tt.expected) {
+			t.Errorf("classifyChurnPercent(%.1f) = %s, want %s", tt.percent, result, tt.expected)
+		}
+	}
+}
@@ -111,7 +111,7 @@ func analyzeCommitCadence(repo *git.Repository, pathFilters []string, lastArg st
 	}
 	
 	// Get commits within time window
-	commitIter, err := repo.Log(&git.LogOptions{
+	commitIter, err := logCommits(repo, &git.LogOptions{
 		Since: since,
 	})
 	if err != nil {
@@ -127,8 +127,8 @@ func analyzeCommitCadence(repo *git.Repository, pathFilters []string, lastArg st
 			return nil
 		}
 		
-		// Skip merge commits for cleaner analysis
-		if commit.NumParents() > 1 {
+		// Merge commits count only when the merge policy includes them
+		if !includeMergeCommit(commit) {
 			return nil
 		}
 		
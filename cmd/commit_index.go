@@ -0,0 +1,329 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// commitIndex answers containment questions about the default branch without
+// re-walking history. It is built once from the branch head and holds the
+// first-parent chain (oldest first), the chain position at which every
+// reachable commit landed, and generation numbers.
+//
+// A commit made directly on the branch lands on itself; a branch commit lands
+// on the merge that brought it in. Chain commits nest, so the ancestors of
+// the chain commit at position p are exactly the commits that landed at or
+// before p. Generation numbers (1 for roots, otherwise one more than the
+// highest parent) let reachability walks from commits off the chain stop at
+// commits too old to contain their target.
+type commitIndex struct {
+	repo       *git.Repository
+	lookup     commitLookup
+	chain      []plumbing.Hash
+	chainTime  []time.Time
+	position   map[plumbing.Hash]int
+	landed     map[plumbing.Hash]int
+	generation map[plumbing.Hash]int
+	parents    map[plumbing.Hash][]plumbing.Hash
+	authorTime map[plumbing.Hash]time.Time
+	landedAt   map[int][]plumbing.Hash // built on first use
+
+	tags        []Deployment // loaded on first use
+	tagsLoaded  bool
+	chainTagMin []time.Time // earliest tag time among chain tags at or after each position
+}
+
+// commitLookup returns a commit's parents and author time
+type commitLookup func(hash plumbing.Hash) ([]plumbing.Hash, time.Time, error)
+
+// buildCommitIndex indexes every commit reachable from head
+func buildCommitIndex(repo *git.Repository, head plumbing.Hash) (*commitIndex, error) {
+	idx, err := newCommitIndex(head, func(hash plumbing.Hash) ([]plumbing.Hash, time.Time, error) {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return c.ParentHashes, c.Author.When, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	idx.repo = repo
+	return idx, nil
+}
+
+// newCommitIndex indexes every commit reachable from head, reading commits through lookup
+func newCommitIndex(head plumbing.Hash, lookup commitLookup) (*commitIndex, error) {
+	idx := &commitIndex{
+		lookup:     lookup,
+		position:   make(map[plumbing.Hash]int),
+		landed:     make(map[plumbing.Hash]int),
+		generation: make(map[plumbing.Hash]int),
+		parents:    make(map[plumbing.Hash][]plumbing.Hash),
+		authorTime: make(map[plumbing.Hash]time.Time),
+	}
+	times := idx.authorTime
+	missing := make(map[plumbing.Hash]bool)
+
+	// Generation numbers, computed parents first with an explicit stack
+	stack := []plumbing.Hash{head}
+	for len(stack) > 0 {
+		hash := stack[len(stack)-1]
+		if _, done := idx.generation[hash]; done {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		parents, loaded := idx.parents[hash]
+		if !loaded {
+			commitParents, when, err := lookup(hash)
+			if err != nil {
+				if hash == head {
+					return nil, fmt.Errorf("could not read commit %s: %v", hash.String()[:7], err)
+				}
+				// A missing parent means a shallow clone; history simply ends there
+				missing[hash] = true
+				idx.generation[hash] = 0
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			parents = commitParents
+			times[hash] = when
+			idx.parents[hash] = parents
+		}
+
+		pending := false
+		for _, p := range parents {
+			if _, done := idx.generation[p]; !done {
+				stack = append(stack, p)
+				pending = true
+			}
+		}
+		if pending {
+			continue
+		}
+		gen := 1
+		for _, p := range parents {
+			if g := idx.generation[p] + 1; g > gen {
+				gen = g
+			}
+		}
+		idx.generation[hash] = gen
+		stack = stack[:len(stack)-1]
+	}
+
+	// The first-parent chain, oldest first
+	for hash := head; ; {
+		idx.chain = append(idx.chain, hash)
+		parents := idx.parents[hash]
+		if len(parents) == 0 || missing[parents[0]] {
+			break
+		}
+		hash = parents[0]
+	}
+	for i, j := 0, len(idx.chain)-1; i < j; i, j = i+1, j-1 {
+		idx.chain[i], idx.chain[j] = idx.chain[j], idx.chain[i]
+	}
+	idx.chainTime = make([]time.Time, len(idx.chain))
+	for i, hash := range idx.chain {
+		idx.position[hash] = i
+		idx.chainTime[i] = times[hash]
+	}
+
+	// Each chain commit lands itself and everything its other parents bring in
+	for i, hash := range idx.chain {
+		idx.landed[hash] = i
+		parents := idx.parents[hash]
+		if len(parents) < 2 {
+			continue
+		}
+		stack := append([]plumbing.Hash{}, parents[1:]...)
+		for len(stack) > 0 {
+			h := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if _, marked := idx.landed[h]; marked || missing[h] {
+				continue
+			}
+			idx.landed[h] = i
+			stack = append(stack, idx.parents[h]...)
+		}
+	}
+	return idx, nil
+}
+
+// head returns the commit the index was built from
+func (idx *commitIndex) head() plumbing.Hash {
+	return idx.chain[len(idx.chain)-1]
+}
+
+// landedTime returns the time of the first chain commit containing hash
+func (idx *commitIndex) landedTime(hash plumbing.Hash) (time.Time, bool) {
+	pos, ok := idx.landed[hash]
+	if !ok {
+		return time.Time{}, false
+	}
+	return idx.chainTime[pos], true
+}
+
+// landedCommits returns the commits that landed with the chain commit at
+// pos, other than the chain commit itself: the commits a merge brought in
+func (idx *commitIndex) landedCommits(pos int) []plumbing.Hash {
+	if idx.landedAt == nil {
+		idx.landedAt = make(map[int][]plumbing.Hash)
+		for hash, p := range idx.landed {
+			if idx.chain[p] != hash {
+				idx.landedAt[p] = append(idx.landedAt[p], hash)
+			}
+		}
+	}
+	return idx.landedAt[pos]
+}
+
+// commitParents returns a commit's parents, reading commits outside the index on demand
+func (idx *commitIndex) commitParents(hash plumbing.Hash) []plumbing.Hash {
+	if parents, ok := idx.parents[hash]; ok {
+		return parents
+	}
+	parents, _, _ := idx.lookup(hash)
+	idx.parents[hash] = parents
+	return parents
+}
+
+// contains reports whether target is tip or one of its ancestors
+func (idx *commitIndex) contains(tip, target plumbing.Hash) bool {
+	targetPos, targetLanded := idx.landed[target]
+	if pos, ok := idx.position[tip]; ok {
+		return targetLanded && targetPos <= pos
+	}
+
+	targetGen, indexed := idx.generation[target]
+	seen := make(map[plumbing.Hash]bool)
+	stack := []plumbing.Hash{tip}
+	for len(stack) > 0 {
+		hash := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if hash == target {
+			return true
+		}
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+
+		if pos, ok := idx.position[hash]; ok {
+			// Everything below a chain commit is answered by landed positions
+			if targetLanded && targetPos <= pos {
+				return true
+			}
+			continue
+		}
+		if gen, ok := idx.generation[hash]; ok && indexed && gen <= targetGen {
+			// Too old to have target as an ancestor
+			continue
+		}
+		stack = append(stack, idx.commitParents(hash)...)
+	}
+	return false
+}
+
+// loadTags resolves every tag once
+func (idx *commitIndex) loadTags() error {
+	if idx.tagsLoaded {
+		return nil
+	}
+	idx.tagsLoaded = true
+
+	tagRefs, err := idx.repo.Tags()
+	if err != nil {
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer tagRefs.Close()
+	var tags []Deployment
+	err = tagRefs.ForEach(func(ref *plumbing.Reference) error {
+		if d, ok := resolveTagDeployment(idx.repo, ref); ok {
+			tags = append(tags, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	idx.setTags(tags)
+	return nil
+}
+
+// setTags records the tags, oldest first, and precomputes the earliest tag
+// time at or after each chain position. Deployments can stand in for tags;
+// those whose commit isn't in the repository are left out.
+func (idx *commitIndex) setTags(tags []Deployment) {
+	idx.tags, idx.tagsLoaded = nil, true
+	for _, tag := range tags {
+		if !tag.Hash.IsZero() {
+			idx.tags = append(idx.tags, tag)
+		}
+	}
+	sortDeployments(idx.tags)
+
+	idx.chainTagMin = make([]time.Time, len(idx.chain))
+	for _, tag := range idx.tags {
+		pos, ok := idx.position[tag.Hash]
+		if ok && (idx.chainTagMin[pos].IsZero() || tag.Time.Before(idx.chainTagMin[pos])) {
+			idx.chainTagMin[pos] = tag.Time
+		}
+	}
+	for i := len(idx.chainTagMin) - 2; i >= 0; i-- {
+		if later := idx.chainTagMin[i+1]; !later.IsZero() && (idx.chainTagMin[i].IsZero() || later.Before(idx.chainTagMin[i])) {
+			idx.chainTagMin[i] = later
+		}
+	}
+}
+
+// firstTagTime returns the time of the earliest tag containing hash
+func (idx *commitIndex) firstTagTime(hash plumbing.Hash) (time.Time, bool, error) {
+	if err := idx.loadTags(); err != nil {
+		return time.Time{}, false, err
+	}
+
+	var earliest time.Time
+	if pos, ok := idx.landed[hash]; ok {
+		earliest = idx.chainTagMin[pos]
+	}
+	// Tags off the chain (release branches, detached builds) need a walk;
+	// tags are sorted, so the first containing one is the earliest
+	for _, tag := range idx.tags {
+		if !earliest.IsZero() && !tag.Time.Before(earliest) {
+			break
+		}
+		if _, onChain := idx.position[tag.Hash]; onChain {
+			continue
+		}
+		if idx.contains(tag.Hash, hash) {
+			earliest = tag.Time
+			break
+		}
+	}
+	return earliest, !earliest.IsZero(), nil
+}
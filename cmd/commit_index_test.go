@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// testCommitGraph is an in-memory history: each commit's parents, first parent first
+type testCommitGraph map[plumbing.Hash][]plumbing.Hash
+
+func (g testCommitGraph) lookup(start time.Time) commitLookup {
+	return func(hash plumbing.Hash) ([]plumbing.Hash, time.Time, error) {
+		parents, ok := g[hash]
+		if !ok {
+			return nil, time.Time{}, fmt.Errorf("object not found")
+		}
+		// Commits are dated by their first hash byte so the times are distinct
+		return parents, start.Add(time.Duration(hash[0]) * time.Hour), nil
+	}
+}
+
+func TestCommitIndex(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	a, b, f1, f2 := plumbing.NewHash("01"), plumbing.NewHash("02"), plumbing.NewHash("03"), plumbing.NewHash("04")
+	m, c, r := plumbing.NewHash("05"), plumbing.NewHash("06"), plumbing.NewHash("07")
+
+	// main: a - b - m - c, where m merges the branch f1 - f2 forked from a;
+	// r is a release commit on top of f1 that never reached main
+	graph := testCommitGraph{
+		a:  nil,
+		b:  {a},
+		f1: {a},
+		f2: {f1},
+		m:  {b, f2},
+		c:  {m},
+		r:  {f1},
+	}
+	idx, err := newCommitIndex(c, graph.lookup(start))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(idx.chain) != 4 || idx.chain[0] != a || idx.chain[3] != c {
+		t.Fatalf("Expected the chain a, b, m, c, got %v", idx.chain)
+	}
+	for hash, want := range map[plumbing.Hash]int{a: 0, b: 1, f1: 2, f2: 2, m: 2, c: 3} {
+		if got := idx.landed[hash]; got != want {
+			t.Errorf("landed[%s] = %d, want %d", hash.String()[:2], got, want)
+		}
+	}
+	for hash, want := range map[plumbing.Hash]int{a: 1, b: 2, f1: 2, f2: 3, m: 4, c: 5} {
+		if got := idx.generation[hash]; got != want {
+			t.Errorf("generation[%s] = %d, want %d", hash.String()[:2], got, want)
+		}
+	}
+
+	// A branch commit merges at the time of its merge
+	if when, ok := idx.landedTime(f2); !ok || !when.Equal(start.Add(5*time.Hour)) {
+		t.Errorf("Expected f2 to land with m, got %v", when)
+	}
+	if _, ok := idx.landedTime(r); ok {
+		t.Error("Expected r not to have landed on main")
+	}
+
+	tests := []struct {
+		tip, target plumbing.Hash
+		expected    bool
+	}{
+		{c, f1, true},
+		{b, f1, false},
+		{m, m, true},
+		{r, f1, true},
+		{r, a, true},
+		{r, b, false},
+		{r, f2, false},
+	}
+	for _, tt := range tests {
+		if result := idx.contains(tt.tip, tt.target); result != tt.expected {
+			t.Errorf("contains(%s, %s) = %v, want %v", tt.tip.String()[:2], tt.target.String()[:2], result, tt.expected)
+		}
+	}
+
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	idx.setTags([]Deployment{
+		{Hash: m, Time: day(10), Ref: "v1.0.0"},
+		{Hash: b, Time: day(5), Ref: "v0.9.0"},
+		{Hash: r, Time: day(3), Ref: "v0.8.1"},
+		{Time: day(1), Ref: "unknown"}, // deployed commit missing from the repository
+	})
+	tagTests := []struct {
+		hash     plumbing.Hash
+		expected time.Time
+	}{
+		{f1, day(3)}, // the release branch tag came first
+		{b, day(5)},
+		{f2, day(10)},
+		{c, time.Time{}},
+	}
+	for _, tt := range tagTests {
+		result, found, err := idx.firstTagTime(tt.hash)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if found != !tt.expected.IsZero() || !result.Equal(tt.expected) {
+			t.Errorf("firstTagTime(%s) = %v, %v, want %v", tt.hash.String()[:2], result, found, tt.expected)
+		}
+	}
+}
+
+func TestCommitIndexShallow(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	gone, a, b := plumbing.NewHash("01"), plumbing.NewHash("02"), plumbing.NewHash("03")
+
+	// The clone stops at a, whose parent isn't available
+	graph := testCommitGraph{a: {gone}, b: {a}}
+	idx, err := newCommitIndex(b, graph.lookup(start))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(idx.chain) != 2 || idx.chain[0] != a {
+		t.Errorf("Expected the chain to end at a, got %v", idx.chain)
+	}
+	if _, ok := idx.landed[gone]; ok {
+		t.Error("Expected the missing commit not to be indexed")
+	}
+
+	if _, err := newCommitIndex(gone, graph.lookup(start)); err == nil {
+		t.Error("Expected an error when the head is missing")
+	}
+}
+
+func TestCommitIndexLandedCommits(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	a, f1, f2, m := plumbing.NewHash("01"), plumbing.NewHash("02"), plumbing.NewHash("03"), plumbing.NewHash("04")
+	graph := testCommitGraph{a: nil, f1: {a}, f2: {f1}, m: {a, f2}}
+	idx, err := newCommitIndex(m, graph.lookup(start))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if landed := idx.landedCommits(1); len(landed) != 2 {
+		t.Errorf("Expected the merge to bring in f1 and f2, got %v", landed)
+	}
+	if landed := idx.landedCommits(0); len(landed) != 0 {
+		t.Errorf("Expected nothing else to land with a, got %v", landed)
+	}
+}
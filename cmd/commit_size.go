@@ -149,47 +149,19 @@ func processCommitForSize(c *object.Commit, pathFilters []string) (int, int, int
 		return additions, deletions, filesChanged, err
 	}
 	
-	// Regular commit - calculate diff
-	parents := c.Parents()
-	defer parents.Close()
-	
-	parentCount := 0
-	fileSet := make(map[string]bool) // Track unique files across all parents
-	err := parents.ForEach(func(parent *object.Commit) error {
-		parentCount++
-		patch, err := parent.Patch(c)
-		if err != nil {
-			log.Printf("failed to generate patch between parent %s and commit %s: %v", parent.Hash.String(), c.Hash.String(), err)
-			return nil
-		}
-		
-		for _, stat := range patch.Stats() {
-			if !matchesPathFilter(stat.Name, pathFilters) {
-				continue
-			}
-			
-			additions += stat.Addition
-			deletions += stat.Deletion
-			fileSet[stat.Name] = true
-		}
-		
-		return nil
-	})
-	
+	// Regular commit - calculate diff under the active merge policy
+	changes, err := commitLineChanges(c, pathFilters)
 	if err != nil {
 		return 0, 0, 0, err
 	}
 	
-	filesChanged = len(fileSet)
-	
-	// For merge commits, we need to avoid double-counting line changes
-	// that appear in multiple parent diffs. Since we're already tracking unique files,
-	// we only need to adjust line counts to estimate actual changes in the merge.
-	if parentCount > 1 {
-		additions, deletions = applyMergeCommitAdjustment(additions, deletions, parentCount)
+	for _, delta := range changes {
+		additions += delta.Additions
+		deletions += delta.Deletions
 	}
+	filesChanged = len(changes)
 	
-	return additions, deletions, filesChanged, err
+	return additions, deletions, filesChanged, nil
 }
 
 // printCommitSizeStats prints commit size statistics in a formatted table.
@@ -275,7 +247,7 @@ Thresholds are based on research showing reviews are most effective under 400 li
 			log.Fatalf("Could not get HEAD: %v", err)
 		}
 
-		cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+		cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
 		if err != nil {
 			log.Fatalf("Could not get commits: %v", err)
 		}
@@ -285,6 +257,9 @@ Thresholds are based on research showing reviews are most effective under 400 li
 			if !since.IsZero() && c.Committer.When.Before(since) {
 				return storer.ErrStop
 			}
+			if !includeMergeCommit(c) {
+				return nil
+			}
 			
 			additions, deletions, filesChanged, err := processCommitForSize(c, pathFilters)
 			if err != nil {
@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// Complexity is measured two ways. Indentation complexity works for any
+// language: every non-blank line contributes its logical indentation depth,
+// so deeply nested code weighs more than flat code of the same length
+// (Hindle et al., "Reading Beside the Lines"). Go files also get McCabe
+// cyclomatic complexity from their syntax tree.
+
+// indentTabWidth is the number of spaces counted as one level of indentation
+const indentTabWidth = 4
+
+// FileComplexity describes the complexity of one version of a file
+type FileComplexity struct {
+	LOC         int
+	Indentation int // sum of logical indentation levels over non-blank lines
+	MaxIndent   int
+	Cyclomatic  int // Go only: sum over functions; -1 when not measured
+	MaxFunction int // Go only: most complex function
+}
+
+// HasCyclomatic reports whether cyclomatic complexity was measured
+func (c FileComplexity) HasCyclomatic() bool {
+	return c.Cyclomatic >= 0
+}
+
+// lineIndentLevel returns a line's logical indentation depth
+func lineIndentLevel(line string) int {
+	spaces := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			spaces++
+		case '\t':
+			spaces += indentTabWidth
+		default:
+			return spaces / indentTabWidth
+		}
+	}
+	return spaces / indentTabWidth
+}
+
+// indentationComplexity returns the summed and maximum indentation depth of the non-blank lines
+func indentationComplexity(content string) (total, max int) {
+	for _, line := range strings.Split(content, "\n") {
+		if isEmptyLine(line) {
+			continue
+		}
+		level := lineIndentLevel(line)
+		total += level
+		if level > max {
+			max = level
+		}
+	}
+	return total, max
+}
+
+// goCyclomaticComplexity returns the summed cyclomatic complexity of every
+// function in a Go source file and that of the most complex one. Each
+// function starts at 1; every if, for, range, non-default case and && or ||
+// adds one.
+func goCyclomaticComplexity(src string) (total, max int, err error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var visit func(body ast.Node) int
+	visit = func(body ast.Node) int {
+		complexity := 1
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				// Closures are functions of their own
+				fn := visit(n.Body)
+				total += fn
+				if fn > max {
+					max = fn
+				}
+				return false
+			case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+				complexity++
+			case *ast.CaseClause:
+				if n.List != nil {
+					complexity++
+				}
+			case *ast.CommClause:
+				if n.Comm != nil {
+					complexity++
+				}
+			case *ast.BinaryExpr:
+				if n.Op == token.LAND || n.Op == token.LOR {
+					complexity++
+				}
+			}
+			return true
+		})
+		return complexity
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		c := visit(fn.Body)
+		total += c
+		if c > max {
+			max = c
+		}
+	}
+	return total, max, nil
+}
+
+// measureComplexity measures one version of a file. countMode selects which
+// lines count toward LOC (see line_classifier.go).
+func measureComplexity(path, content, countMode string) FileComplexity {
+	c := FileComplexity{LOC: countFileLines(path, content, countMode), Cyclomatic: -1, MaxFunction: -1}
+	c.Indentation, c.MaxIndent = indentationComplexity(content)
+	if strings.ToLower(filepath.Ext(path)) == ".go" {
+		if total, max, err := goCyclomaticComplexity(content); err == nil {
+			c.Cyclomatic, c.MaxFunction = total, max
+		}
+	}
+	return c
+}
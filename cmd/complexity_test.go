@@ -0,0 +1,85 @@
+package cmd
+
+import "testing"
+
+func TestIndentationComplexity(t *testing.T) {
+	content := "func f() {\n\tif x {\n\t\treturn\n\t}\n\n        y()\n}\n"
+	total, max := indentationComplexity(content)
+	// 0 + 1 + 2 + 1 + (blank) + 2 + 0
+	if total != 6 || max != 2 {
+		t.Errorf("Expected total 6 and max 2, got %d and %d", total, max)
+	}
+}
+
+func TestLineIndentLevel(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected int
+	}{
+		{"x", 0},
+		{"\tx", 1},
+		{"    x", 1},
+		{"      x", 1},
+		{"\t    x", 2},
+		{"  ", 0},
+	}
+
+	for _, tt := range tests {
+		if result := lineIndentLevel(tt.line); result != tt.expected {
+			t.Errorf("lineIndentLevel(%q) = %d, want %d", tt.line, result, tt.expected)
+		}
+	}
+}
+
+func TestGoCyclomaticComplexity(t *testing.T) {
+	src := `package p
+
+func simple() {}
+
+func branches(a, b bool, xs []int) int {
+	if a && b {
+		return 1
+	}
+	for _, x := range xs {
+		switch x {
+		case 1:
+		case 2, 3:
+		default:
+		}
+	}
+	f := func() bool { return a || b }
+	_ = f
+	return 0
+}
+`
+	total, max, err := goCyclomaticComplexity(src)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// simple: 1; branches: 1 + if + && + range + 2 cases = 6; closure: 1 + || = 2
+	if total != 9 || max != 6 {
+		t.Errorf("Expected total 9 and max 6, got %d and %d", total, max)
+	}
+
+	if _, _, err := goCyclomaticComplexity("not go"); err == nil {
+		t.Error("Expected an error for invalid Go source")
+	}
+}
+
+func TestMeasureComplexity(t *testing.T) {
+	goFile := measureComplexity("a.go", "package p\n\nfunc f() {\n\tif true {\n\t}\n}\n", countModeAll)
+	if !goFile.HasCyclomatic() || goFile.Cyclomatic != 2 {
+		t.Errorf("Expected cyclomatic 2 for a Go file, got %+v", goFile)
+	}
+	if goFile.LOC != 6 {
+		t.Errorf("Expected 6 LOC, got %d", goFile.LOC)
+	}
+
+	pyFile := measureComplexity("a.py", "def f():\n    if x:\n        pass\n", countModeAll)
+	if pyFile.HasCyclomatic() {
+		t.Errorf("Expected no cyclomatic complexity for a Python file, got %+v", pyFile)
+	}
+	if pyFile.Indentation != 3 {
+		t.Errorf("Expected indentation complexity 3, got %d", pyFile.Indentation)
+	}
+}
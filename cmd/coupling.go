@@ -0,0 +1,391 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/spf13/cobra"
+)
+
+// Temporal coupling finds files that change in the same commits. For a pair
+// A, B changed together in n commits:
+// - support is n divided by the number of commits analyzed
+// - confidence A→B is n divided by A's revisions: how often a change to A
+//   also touched B
+// Pairs in different directories are hidden dependencies the directory layout
+// doesn't show.
+
+const (
+	defaultCouplingMinShared = 5
+	defaultCouplingMaxFiles  = 30
+)
+
+// CouplingPair is the co-change relationship between two files, A < B
+type CouplingPair struct {
+	A, B         string
+	RevisionsA   int
+	RevisionsB   int
+	Shared       int
+	Support      float64 // percent of analyzed commits
+	ConfidenceAB float64 // percent of A's commits that also changed B
+	ConfidenceBA float64 // percent of B's commits that also changed A
+}
+
+// Confidence returns the stronger of the two directional confidences
+func (p CouplingPair) Confidence() float64 {
+	if p.ConfidenceAB > p.ConfidenceBA {
+		return p.ConfidenceAB
+	}
+	return p.ConfidenceBA
+}
+
+// CrossDirectory reports whether the pair spans two directories
+func (p CouplingPair) CrossDirectory() bool {
+	return reportDirectory(p.A) != reportDirectory(p.B)
+}
+
+// CouplingCluster is a group of files connected by cross-directory coupling
+type CouplingCluster struct {
+	Files       []string
+	Directories []string
+	Pairs       int
+	Shared      int // summed shared commits across the cluster's pairs
+}
+
+// CouplingAnalysis holds the result of a coupling run
+type CouplingAnalysis struct {
+	Commits     int // commits analyzed
+	MegaCommits int // commits skipped for touching more than the file cap
+	Pairs       []CouplingPair
+	Clusters    []CouplingCluster
+}
+
+// couplingCounter accumulates revisions and co-changes from per-commit file sets
+type couplingCounter struct {
+	maxFiles    int
+	commits     int
+	megaCommits int
+	revisions   map[string]int
+	shared      map[[2]string]int
+}
+
+func newCouplingCounter(maxFiles int) *couplingCounter {
+	return &couplingCounter{
+		maxFiles:  maxFiles,
+		revisions: make(map[string]int),
+		shared:    make(map[[2]string]int),
+	}
+}
+
+// add records one commit's changed files. Commits touching more than maxFiles
+// files (mass reformats, imports, renames) would couple everything to
+// everything and are skipped.
+func (c *couplingCounter) add(files []string) {
+	if len(files) == 0 {
+		return
+	}
+	if c.maxFiles > 0 && len(files) > c.maxFiles {
+		c.megaCommits++
+		return
+	}
+	c.commits++
+
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+	for i, a := range sorted {
+		c.revisions[a]++
+		for _, b := range sorted[i+1:] {
+			if a != b {
+				c.shared[[2]string{a, b}]++
+			}
+		}
+	}
+}
+
+// pairs returns the pairs sharing at least minShared commits, strongest
+// confidence first
+func (c *couplingCounter) pairs(minShared int) []CouplingPair {
+	var pairs []CouplingPair
+	for key, n := range c.shared {
+		if n < minShared {
+			continue
+		}
+		revA, revB := c.revisions[key[0]], c.revisions[key[1]]
+		pairs = append(pairs, CouplingPair{
+			A:            key[0],
+			B:            key[1],
+			RevisionsA:   revA,
+			RevisionsB:   revB,
+			Shared:       n,
+			Support:      float64(n) / float64(c.commits) * 100,
+			ConfidenceAB: float64(n) / float64(revA) * 100,
+			ConfidenceBA: float64(n) / float64(revB) * 100,
+		})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Confidence() != pairs[j].Confidence() {
+			return pairs[i].Confidence() > pairs[j].Confidence()
+		}
+		if pairs[i].Shared != pairs[j].Shared {
+			return pairs[i].Shared > pairs[j].Shared
+		}
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+	return pairs
+}
+
+// couplingClusters groups files connected through cross-directory pairs,
+// largest cluster first
+func couplingClusters(pairs []CouplingPair) []CouplingCluster {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(f string) string {
+		if parent[f] != f {
+			parent[f] = find(parent[f])
+		}
+		return parent[f]
+	}
+
+	var cross []CouplingPair
+	for _, p := range pairs {
+		if !p.CrossDirectory() {
+			continue
+		}
+		cross = append(cross, p)
+		for _, f := range []string{p.A, p.B} {
+			if _, ok := parent[f]; !ok {
+				parent[f] = f
+			}
+		}
+		if ra, rb := find(p.A), find(p.B); ra != rb {
+			parent[ra] = rb
+		}
+	}
+
+	byRoot := make(map[string]*CouplingCluster)
+	for f := range parent {
+		root := find(f)
+		if byRoot[root] == nil {
+			byRoot[root] = &CouplingCluster{}
+		}
+		byRoot[root].Files = append(byRoot[root].Files, f)
+	}
+	for _, p := range cross {
+		cluster := byRoot[find(p.A)]
+		cluster.Pairs++
+		cluster.Shared += p.Shared
+	}
+
+	clusters := make([]CouplingCluster, 0, len(byRoot))
+	for _, cluster := range byRoot {
+		sort.Strings(cluster.Files)
+		dirs := make(map[string]bool)
+		for _, f := range cluster.Files {
+			dirs[reportDirectory(f)] = true
+		}
+		for d := range dirs {
+			cluster.Directories = append(cluster.Directories, d)
+		}
+		sort.Strings(cluster.Directories)
+		clusters = append(clusters, *cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Files) != len(clusters[j].Files) {
+			return len(clusters[i].Files) > len(clusters[j].Files)
+		}
+		if clusters[i].Shared != clusters[j].Shared {
+			return clusters[i].Shared > clusters[j].Shared
+		}
+		return clusters[i].Files[0] < clusters[j].Files[0]
+	})
+	return clusters
+}
+
+// analyzeCoupling walks the window's commits and measures co-change between files
+func analyzeCoupling(repo *git.Repository, since time.Time, pathFilters []string, minShared, maxFiles int) (*CouplingAnalysis, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD: %v", err)
+	}
+
+	cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	defer cIter.Close()
+
+	counter := newCouplingCounter(maxFiles)
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if !since.IsZero() && c.Committer.When.Before(since) {
+			return storer.ErrStop
+		}
+		if !includeMergeCommit(c) {
+			return nil
+		}
+		files, err := commitChangedPaths(c, pathFilters, true)
+		if err != nil {
+			log.Printf("Error processing commit %s: %v", c.Hash.String(), err)
+			return nil
+		}
+		counter.add(files)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking commits: %v", err)
+	}
+
+	pairs := counter.pairs(minShared)
+	return &CouplingAnalysis{
+		Commits:     counter.commits,
+		MegaCommits: counter.megaCommits,
+		Pairs:       pairs,
+		Clusters:    couplingClusters(pairs),
+	}, nil
+}
+
+// printCouplingAnalysis prints the top coupled pairs and cross-directory clusters
+func printCouplingAnalysis(analysis *CouplingAnalysis, minShared, maxFiles, limit int) {
+	fmt.Printf("Temporal Coupling Analysis\n")
+	fmt.Printf("Commits analyzed: %d", analysis.Commits)
+	if analysis.MegaCommits > 0 {
+		fmt.Printf(" (skipped %d with more than %d files)", analysis.MegaCommits, maxFiles)
+	}
+	fmt.Println()
+	fmt.Printf("Pairs sharing at least %d commits: %d\n", minShared, len(analysis.Pairs))
+
+	if len(analysis.Pairs) == 0 {
+		fmt.Println("\nNo coupled files found. Try a lower --min-shared or a longer --last window.")
+		return
+	}
+
+	fmt.Printf("\nTop %d coupled pairs (by confidence):\n", limit)
+	fmt.Printf("%-40s %-40s %6s %8s %10s\n", "File", "Coupled with", "Shared", "Support", "Confidence")
+	for i, p := range analysis.Pairs {
+		if i >= limit {
+			break
+		}
+		// Show the direction with the stronger confidence: changing From usually changes To
+		from, to := p.A, p.B
+		if p.ConfidenceBA > p.ConfidenceAB {
+			from, to = p.B, p.A
+		}
+		marker := ""
+		if p.CrossDirectory() {
+			marker = "cross-directory"
+		}
+		fmt.Printf("%-40s %-40s %6d %7.1f%% %9.1f%%  %s\n", from, to, p.Shared, p.Support, p.Confidence(), marker)
+	}
+
+	fmt.Printf("\nCross-directory coupling clusters (top %d):\n", limit)
+	if len(analysis.Clusters) == 0 {
+		fmt.Println("None: coupled files all live in the same directory.")
+	}
+	for i, cluster := range analysis.Clusters {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%d. %d files across %s (%d pairs, %d shared commits)\n", i+1, len(cluster.Files), strings.Join(cluster.Directories, ", "), cluster.Pairs, cluster.Shared)
+		for _, f := range cluster.Files {
+			fmt.Printf("   %s\n", f)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Confidence is the share of the first file's commits that also changed the second.")
+	fmt.Println("Files that change together across directories are hidden dependencies: consider moving them together or decoupling them.")
+}
+
+// couplingCmd represents the coupling command
+var couplingCmd = &cobra.Command{
+	Use:   "coupling",
+	Short: "Find files that change together (temporal coupling)",
+	Long: `Discover files that keep changing in the same commits even though nothing
+in the directory layout connects them: hidden dependencies that
+directory-entropy can't see.
+
+For each pair of files the command reports how many commits changed both
+(shared), that count as a share of all analyzed commits (support), and the
+share of one file's commits that also changed the other (confidence).
+Commits touching more than --max-files files are skipped so mass reformats
+don't couple everything to everything. Cross-directory pairs are grouped into
+clusters of files that are coupled to each other.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse flags
+		lastArg, _ := cmd.Flags().GetString("last")
+		pathFilters, source := getConfigPaths(cmd, "coupling.paths")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		minSharedArg, _ := cmd.Flags().GetInt("min-shared")
+		maxFilesArg, _ := cmd.Flags().GetInt("max-files")
+		if minSharedArg < 1 {
+			log.Fatalf("--min-shared must be at least 1, got %d", minSharedArg)
+		}
+		if maxFilesArg < 2 {
+			log.Fatalf("--max-files must be at least 2, got %d", maxFilesArg)
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "coupling", lastArg, pathFilters, source)
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			log.Fatalf("Could not open repository: %v", err)
+		}
+
+		since := time.Time{}
+		if lastArg != "" {
+			cutoff, err := parseDurationArg(lastArg)
+			if err != nil {
+				log.Fatalf("Could not parse --last argument: %v", err)
+			}
+			since = cutoff
+		}
+
+		analysis, err := analyzeCoupling(repo, since, pathFilters, minSharedArg, maxFilesArg)
+		if err != nil {
+			log.Fatalf("Error analyzing coupling: %v", err)
+		}
+
+		printCouplingAnalysis(analysis, minSharedArg, maxFilesArg, limitArg)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(couplingCmd)
+	couplingCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
+	couplingCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+	couplingCmd.Flags().Int("limit", 10, "Number of top pairs and clusters to show")
+	couplingCmd.Flags().Int("min-shared", defaultCouplingMinShared, "Minimum number of commits a pair must share")
+	couplingCmd.Flags().Int("max-files", defaultCouplingMaxFiles, "Skip commits touching more than this many files")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import "testing"
+
+func TestCouplingCounter(t *testing.T) {
+	counter := newCouplingCounter(3)
+	counter.add([]string{"api/handler.go", "db/schema.sql"})
+	counter.add([]string{"api/handler.go", "db/schema.sql", "api/routes.go"})
+	counter.add([]string{"api/handler.go"})
+	counter.add([]string{"db/schema.sql", "api/handler.go"})
+	// Mega-commit: skipped entirely
+	counter.add([]string{"a", "b", "c", "d"})
+	counter.add(nil)
+
+	if counter.commits != 4 || counter.megaCommits != 1 {
+		t.Fatalf("Expected 4 commits and 1 mega-commit, got %d and %d", counter.commits, counter.megaCommits)
+	}
+
+	pairs := counter.pairs(2)
+	if len(pairs) != 1 {
+		t.Fatalf("Expected 1 pair sharing at least 2 commits, got %+v", pairs)
+	}
+	p := pairs[0]
+	if p.A != "api/handler.go" || p.B != "db/schema.sql" || p.Shared != 3 {
+		t.Errorf("Unexpected pair %+v", p)
+	}
+	if p.Support != 75 || p.ConfidenceAB != 75 || p.ConfidenceBA != 100 || p.Confidence() != 100 {
+		t.Errorf("Expected support 75%%, confidence 75%%/100%%, got %+v", p)
+	}
+	if !p.CrossDirectory() {
+		t.Error("Expected the pair to be cross-directory")
+	}
+
+	if all := counter.pairs(1); len(all) != 3 {
+		t.Errorf("Expected 3 pairs sharing at least 1 commit, got %d", len(all))
+	}
+}
+
+func TestCouplingClusters(t *testing.T) {
+	pairs := []CouplingPair{
+		{A: "api/a.go", B: "db/b.sql", Shared: 5},
+		{A: "db/b.sql", B: "web/c.ts", Shared: 3},
+		{A: "api/a.go", B: "api/d.go", Shared: 9}, // same directory: not clustered
+		{A: "x/e.go", B: "y/f.go", Shared: 4},
+	}
+
+	clusters := couplingClusters(pairs)
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 clusters, got %+v", clusters)
+	}
+	first := clusters[0]
+	if len(first.Files) != 3 || first.Pairs != 2 || first.Shared != 8 {
+		t.Errorf("Unexpected first cluster %+v", first)
+	}
+	if len(first.Directories) != 3 || first.Directories[0] != "api/" {
+		t.Errorf("Expected directories api/, db/, web/, got %v", first.Directories)
+	}
+	if clusters[1].Files[0] != "x/e.go" || clusters[1].Files[1] != "y/f.go" {
+		t.Errorf("Unexpected second cluster %+v", clusters[1])
+	}
+}
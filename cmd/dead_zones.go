@@ -49,6 +49,7 @@ type DeadZoneFileStats struct {
 	LastModified time.Time
 	AgeInMonths  int
 	Size         int64
+	Lines        int
 	RiskLevel    string
 	Recommendation string
 }
@@ -155,7 +156,7 @@ func findFileInCommitHistoryWithTimeWindow(repo *git.Repository, fileName string
 		return err
 	}
 	
-	cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
 	if err != nil {
 		return err
 	}
@@ -167,41 +168,13 @@ func findFileInCommitHistoryWithTimeWindow(repo *git.Repository, fileName string
 			return storer.ErrStop
 		}
 		
-		// Handle merge commits by diffing against their first parent.
-		// This is a common strategy to identify changes introduced by a merge.
-		var parentTree *object.Tree
-		if c.NumParents() > 0 {
-			parent, err := c.Parent(0)
-			if err != nil {
-				return nil
-			}
-			parentTree, err = parent.Tree()
-			if err != nil {
-				return nil
-			}
-		}
-		
-		tree, err := c.Tree()
+		paths, err := commitChangedPaths(c, []string{fileName}, false)
 		if err != nil {
 			return nil
 		}
 		
-		if parentTree != nil {
-			// Compare with parent to get changed files
-			changes, err := tree.Diff(parentTree)
-			if err != nil {
-				return nil
-			}
-			
-			for _, change := range changes {
-				if change.To.Name == fileName {
-					return handler(c)
-				}
-			}
-		} else {
-			// Initial commit - check if file exists
-			_, err = tree.File(fileName)
-			if err == nil {
+		for _, path := range paths {
+			if path == fileName {
 				return handler(c)
 			}
 		}
@@ -212,7 +185,7 @@ func findFileInCommitHistoryWithTimeWindow(repo *git.Repository, fileName string
 
 // buildFileModificationMap efficiently builds a map of file modification times
 func buildFileModificationMap(repo *git.Repository, ref *plumbing.Reference, since time.Time, pathFilters []string, fileLastModified map[string]time.Time) error {
-	cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
 	if err != nil {
 		return fmt.Errorf("could not get commits: %v", err)
 	}
@@ -223,61 +196,16 @@ func buildFileModificationMap(repo *git.Repository, ref *plumbing.Reference, sin
 			return storer.ErrStop
 		}
 		
-		// Handle merge commits by diffing against their first parent.
-		// This is a common strategy to identify changes introduced by a merge.
-		var parentTree *object.Tree
-		if c.NumParents() > 0 {
-			parent, err := c.Parent(0)
-			if err != nil {
-				return nil
-			}
-			parentTree, err = parent.Tree()
-			if err != nil {
-				return nil
-			}
-		}
-		
-		tree, err := c.Tree()
+		// Changed files follow the active merge policy; root commits "modify" every file
+		paths, err := commitChangedPaths(c, pathFilters, false)
 		if err != nil {
 			return nil
 		}
 		
-		if parentTree != nil {
-			// Compare with parent to get changed files
-			changes, err := tree.Diff(parentTree)
-			if err != nil {
-				return nil
-			}
-			
-			for _, change := range changes {
-				if change.To.Name == "" {
-					continue // skip deletions
-				}
-				
-				// Apply path filter if specified
-				if !matchesPathFilter(change.To.Name, pathFilters) {
-					continue
-				}
-				
-				// Update last modified time for this file (only if not already set, since we iterate newest to oldest)
-				if _, exists := fileLastModified[change.To.Name]; !exists {
-					fileLastModified[change.To.Name] = c.Committer.When
-				}
-			}
-		} else {
-			// Initial commit - all files are "modified" at this time
-			err = tree.Files().ForEach(func(f *object.File) error {
-				if !matchesPathFilter(f.Name, pathFilters) {
-					return nil
-				}
-				
-				if _, exists := fileLastModified[f.Name]; !exists {
-					fileLastModified[f.Name] = c.Committer.When
-				}
-				return nil
-			})
-			if err != nil {
-				return err
+		for _, path := range paths {
+			// Update last modified time for this file (only if not already set, since we iterate newest to oldest)
+			if _, exists := fileLastModified[path]; !exists {
+				fileLastModified[path] = c.Committer.When
 			}
 		}
 		
@@ -299,8 +227,9 @@ func sortDeadZonesByAge(files []DeadZoneFileStats) []DeadZoneFileStats {
 	return sorted
 }
 
-// analyzeDeadZones performs dead zone analysis on the repository
-func analyzeDeadZones(repo *git.Repository, since time.Time, pathFilters []string) (*DeadZoneAnalysis, error) {
+// analyzeDeadZones performs dead zone analysis on the repository.
+// countMode selects which lines count toward each dead file's LOC (see line_classifier.go).
+func analyzeDeadZones(repo *git.Repository, since time.Time, pathFilters []string, countMode string) (*DeadZoneAnalysis, error) {
 	ref, err := repo.Head()
 	if err != nil {
 		return nil, fmt.Errorf("could not get HEAD: %v", err)
@@ -374,6 +303,12 @@ func analyzeDeadZones(repo *git.Repository, since time.Time, pathFilters []strin
 				}
 			}
 			
+			// Line count is best effort as well
+			var lines int
+			if content, err := f.Contents(); err == nil {
+				lines = countFileLines(f.Name, content, countMode)
+			}
+			
 			riskLevel, recommendation := classifyDeadZoneRisk(ageInMonths)
 			
 			deadZoneFiles = append(deadZoneFiles, DeadZoneFileStats{
@@ -381,6 +316,7 @@ func analyzeDeadZones(repo *git.Repository, since time.Time, pathFilters []strin
 				LastModified:   lastModified,
 				AgeInMonths:    ageInMonths,
 				Size:           size,
+				Lines:          lines,
 				RiskLevel:      riskLevel,
 				Recommendation: recommendation,
 			})
@@ -451,8 +387,8 @@ func printDeadZoneStats(analysis *DeadZoneAnalysis, limit int) {
 	}
 	
 	fmt.Printf("⚠️  Dead Zone Files (showing top %d):\n", limit)
-	fmt.Printf("File                                  Age     Size      Risk Level    Recommendation\n")
-	fmt.Printf("------------------------------------- ------- --------- ------------- -----------------------\n")
+	fmt.Printf("File                                  Age     Size      LOC     Risk Level    Recommendation\n")
+	fmt.Printf("------------------------------------- ------- --------- ------- ------------- -----------------------\n")
 	
 	for i, file := range analysis.DeadZoneFiles {
 		if i >= limit {
@@ -462,8 +398,8 @@ func printDeadZoneStats(analysis *DeadZoneAnalysis, limit int) {
 		ageStr := fmt.Sprintf("%d months", file.AgeInMonths)
 		sizeStr := formatFileSize(file.Size)
 		
-		fmt.Printf("%-37s %7s %9s %-13s %s\n",
-			truncateFilePath(file.Path, 37), ageStr, sizeStr, file.RiskLevel, file.Recommendation)
+		fmt.Printf("%-37s %7s %9s %7d %-13s %s\n",
+			truncateFilePath(file.Path, 37), ageStr, sizeStr, file.Lines, file.RiskLevel, file.Recommendation)
 	}
 	
 	if len(analysis.DeadZoneFiles) > limit {
@@ -499,6 +435,10 @@ Based on Clean Code principles - untouched code becomes a liability over time.`,
 		lastArg, _ := cmd.Flags().GetString("last")
 		pathFilters, source := getConfigPaths(cmd, "dead-zones.paths")
 		limitArg, _ := cmd.Flags().GetInt("limit")
+		countMode, err := getCountMode(cmd)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
 		
 		// Print configuration scope
 		printCommandScope(cmd, "dead-zones", lastArg, pathFilters, source)
@@ -518,7 +458,7 @@ Based on Clean Code principles - untouched code becomes a liability over time.`,
 			since = cutoff
 		}
 
-		analysis, err := analyzeDeadZones(repo, since, pathFilters)
+		analysis, err := analyzeDeadZones(repo, since, pathFilters, countMode)
 		if err != nil {
 			log.Fatalf("Error analyzing dead zones: %v", err)
 		}
@@ -531,5 +471,6 @@ func init() {
 	deadZonesCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
 	deadZonesCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
 	deadZonesCmd.Flags().Int("limit", 10, "Number of top results to show")
+	addCountFlag(deadZonesCmd)
 	rootCmd.AddCommand(deadZonesCmd)
 }
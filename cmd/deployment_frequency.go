@@ -0,0 +1,251 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+// DeploymentGap is the time between two consecutive deployments
+type DeploymentGap struct {
+	From     Deployment
+	To       Deployment
+	Duration time.Duration
+}
+
+// DeploymentFrequencyStats summarizes how often deployments happen
+type DeploymentFrequencyStats struct {
+	Deployments    int
+	SpanDays       float64
+	PerDay         float64
+	PerWeek        float64
+	Classification string // "Elite", "High", "Medium", "Low", "Unknown"
+	Description    string
+	Periods        []TimePeriod
+	Gaps           []DeploymentGap // longest first
+	MedianGapHours float64
+	SinceLast      time.Duration
+}
+
+// classifyDORADeploymentFrequency classifies a deployment rate according to DORA benchmarks
+func classifyDORADeploymentFrequency(perDay float64) (string, string) {
+	if perDay >= 1 {
+		return "Elite", "On-demand (daily or more often)"
+	} else if perDay*7 >= 1 {
+		return "High", "Between once per day and once per week"
+	} else if perDay*30 >= 1 {
+		return "Medium", "Between once per week and once per month"
+	}
+	return "Low", "Less than once per month"
+}
+
+// deploymentGaps returns the gaps between consecutive deployments, longest
+// first. deployments must be sorted oldest first.
+func deploymentGaps(deployments []Deployment) []DeploymentGap {
+	var gaps []DeploymentGap
+	for i := 1; i < len(deployments); i++ {
+		gaps = append(gaps, DeploymentGap{
+			From:     deployments[i-1],
+			To:       deployments[i],
+			Duration: deployments[i].Time.Sub(deployments[i-1].Time),
+		})
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return gaps[i].Duration > gaps[j].Duration
+	})
+	return gaps
+}
+
+// calculateDeploymentFrequencyStats measures deployment frequency between
+// since (or the first deployment, when zero) and now, grouping deployments
+// into periods the way commit-cadence groups commits
+func calculateDeploymentFrequencyStats(deployments []Deployment, since, now time.Time, period string) *DeploymentFrequencyStats {
+	stats := &DeploymentFrequencyStats{Deployments: len(deployments)}
+	if len(deployments) == 0 {
+		stats.Classification = "Unknown"
+		return stats
+	}
+
+	start := since
+	if start.IsZero() {
+		start = deployments[0].Time
+	}
+	stats.SpanDays = now.Sub(start).Hours() / 24
+	if stats.SpanDays < 1 {
+		stats.SpanDays = 1
+	}
+	stats.PerDay = float64(len(deployments)) / stats.SpanDays
+	stats.PerWeek = stats.PerDay * 7
+	stats.Classification, stats.Description = classifyDORADeploymentFrequency(stats.PerDay)
+
+	infos := make([]CommitInfo, len(deployments))
+	for i, d := range deployments {
+		infos[i] = CommitInfo{Hash: d.Hash.String(), Time: d.Time}
+	}
+	stats.Periods = groupCommitsByTimePeriod(infos, period)
+
+	stats.Gaps = deploymentGaps(deployments)
+	if len(stats.Gaps) > 0 {
+		hours := make([]float64, len(stats.Gaps))
+		for i, g := range stats.Gaps {
+			hours[i] = g.Duration.Hours()
+		}
+		stats.MedianGapHours = calculatePercentile(hours, 50)
+	}
+	stats.SinceLast = now.Sub(deployments[len(deployments)-1].Time)
+	return stats
+}
+
+// formatPeriodLabel names a period for display
+func formatPeriodLabel(p TimePeriod, period string) string {
+	switch period {
+	case "day":
+		return p.Start.Format("2006-01-02")
+	case "month":
+		return p.Start.Format("2006-01")
+	case "quarter":
+		return fmt.Sprintf("%d-Q%d", p.Start.Year(), (int(p.Start.Month())-1)/3+1)
+	default:
+		year, week := p.Start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+}
+
+// printDeploymentFrequencyStats displays the deployment frequency analysis
+func printDeploymentFrequencyStats(stats *DeploymentFrequencyStats, cfg DeploymentConfig, period string, limit int) {
+	fmt.Println("Deployment Frequency Analysis")
+	fmt.Printf("Deployments: %s\n", cfg.Describe())
+	fmt.Println()
+
+	if stats.Deployments == 0 {
+		fmt.Println("No deployments found. Use --deploy-source, --tag-pattern or --deploy-log to match how you release.")
+		return
+	}
+
+	fmt.Printf("Deployments: %d over %.0f days\n", stats.Deployments, stats.SpanDays)
+	fmt.Printf("Frequency: %.2f per day, %.1f per week\n", stats.PerDay, stats.PerWeek)
+	fmt.Printf("DORA classification: %s (%s)\n", stats.Classification, stats.Description)
+	if len(stats.Gaps) > 0 {
+		fmt.Printf("Median gap between deployments: %s\n", formatElapsed(time.Duration(stats.MedianGapHours*float64(time.Hour))))
+	}
+	fmt.Printf("Since last deployment: %s\n", formatElapsed(stats.SinceLast))
+
+	if len(stats.Periods) > 0 {
+		shown := stats.Periods
+		if len(shown) > limit {
+			shown = shown[len(shown)-limit:]
+		}
+		fmt.Printf("\nDeployments per %s (last %d):\n", period, len(shown))
+		for _, p := range shown {
+			row := fmt.Sprintf("  %-10s %4d %s", formatPeriodLabel(p, period), p.CommitCount, strings.Repeat("#", min(p.CommitCount, 50)))
+			fmt.Println(strings.TrimRight(row, " "))
+		}
+	}
+
+	if len(stats.Gaps) > 0 {
+		fmt.Printf("\nLongest gaps (showing %d):\n", min(len(stats.Gaps), limit))
+		for i, g := range stats.Gaps {
+			if i >= limit {
+				break
+			}
+			fmt.Printf("  %-8s %s → %s  (%s to %s)\n", formatElapsed(g.Duration), g.From.Ref, g.To.Ref, g.From.Time.Format("2006-01-02"), g.To.Time.Format("2006-01-02"))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("DORA benchmarks: Elite on-demand, High daily to weekly, Medium weekly to monthly, Low less than monthly.")
+}
+
+// deploymentFrequencyCmd represents the deployment-frequency command
+var deploymentFrequencyCmd = &cobra.Command{
+	Use:   "deployment-frequency",
+	Short: "Measure DORA deployment frequency",
+	Long: `Measure how often you deploy to production.
+
+Deployments come from one of:
+- tags: release tags matching --tag-pattern (semver by default), lightweight
+  or annotated
+- merges: merge commits into the production branch (--branch)
+- log: a deploy log exported from your CD system (--deploy-log), CSV or JSON
+  with a sha and a timestamp per deployment
+
+Reports deployments per day and week, deployments per period, the longest
+gaps between deployments and the DORA classification:
+- Elite: on-demand (daily or more often)
+- High: between once per day and once per week
+- Medium: between once per week and once per month
+- Low: less than once per month`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lastArg, _ := cmd.Flags().GetString("last")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		periodArg, _ := cmd.Flags().GetString("period")
+		switch periodArg {
+		case "day", "week", "month":
+		default:
+			return fmt.Errorf("invalid --period value %q (expected day, week or month)", periodArg)
+		}
+
+		cfg, err := getDeploymentConfig(cmd, "deployment-frequency")
+		if err != nil {
+			return err
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "deployment-frequency", lastArg, nil, "")
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			return fmt.Errorf("could not open repository: %v", err)
+		}
+
+		var since time.Time
+		if lastArg != "" {
+			since, err = parseDurationArg(lastArg)
+			if err != nil {
+				return fmt.Errorf("invalid time window '%s': %v", lastArg, err)
+			}
+		}
+
+		deployments, err := findDeployments(repo, cfg, since)
+		if err != nil {
+			return fmt.Errorf("could not find deployments: %v", err)
+		}
+
+		stats := calculateDeploymentFrequencyStats(deployments, since, time.Now(), periodArg)
+		printDeploymentFrequencyStats(stats, cfg, periodArg, limitArg)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deploymentFrequencyCmd)
+	deploymentFrequencyCmd.Flags().String("last", "", "Specify the time window to analyze (e.g., 30d, 6m, 1y)")
+	deploymentFrequencyCmd.Flags().Int("limit", 10, "Number of periods and gaps to show")
+	deploymentFrequencyCmd.Flags().String("period", "week", "Period to group deployments by: day, week or month")
+	addDeploymentFlags(deploymentFrequencyCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestClassifyDORADeploymentFrequency(t *testing.T) {
+	tests := []struct {
+		perDay   float64
+		expected string
+	}{
+		{3, "Elite"},
+		{1, "Elite"},
+		{0.5, "High"},
+		{1.0 / 7, "High"},
+		{0.1, "Medium"},
+		{1.0 / 30, "Medium"},
+		{0.01, "Low"},
+	}
+
+	for _, tt := range tests {
+		if result, _ := classifyDORADeploymentFrequency(tt.perDay); result != tt.expected {
+			t.Errorf("classifyDORADeploymentFrequency(%.3f) = %s, want %s", tt.perDay, result, tt.expected)
+		}
+	}
+}
+
+func TestDeploymentGaps(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployments := []Deployment{
+		{Time: start, Ref: "v1"},
+		{Time: start.Add(2 * time.Hour), Ref: "v2"},
+		{Time: start.Add(50 * time.Hour), Ref: "v3"},
+		{Time: start.Add(60 * time.Hour), Ref: "v4"},
+	}
+
+	gaps := deploymentGaps(deployments)
+	if len(gaps) != 3 {
+		t.Fatalf("Expected 3 gaps, got %d", len(gaps))
+	}
+	if gaps[0].From.Ref != "v2" || gaps[0].To.Ref != "v3" || gaps[0].Duration != 48*time.Hour {
+		t.Errorf("Expected the longest gap v2 → v3 of 48h, got %s → %s of %v", gaps[0].From.Ref, gaps[0].To.Ref, gaps[0].Duration)
+	}
+	if gaps[2].Duration != 2*time.Hour {
+		t.Errorf("Expected the shortest gap last, got %v", gaps[2].Duration)
+	}
+}
+
+func TestCalculateDeploymentFrequencyStats(t *testing.T) {
+	start := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC) // a Monday
+	var deployments []Deployment
+	for i := 0; i < 14; i++ {
+		deployments = append(deployments, Deployment{Time: start.AddDate(0, 0, i)})
+	}
+	now := start.AddDate(0, 0, 14)
+
+	stats := calculateDeploymentFrequencyStats(deployments, time.Time{}, now, "week")
+	if stats.PerDay != 1 || stats.Classification != "Elite" {
+		t.Errorf("Expected 1 deploy per day (Elite), got %.2f (%s)", stats.PerDay, stats.Classification)
+	}
+	if len(stats.Periods) != 2 || stats.Periods[0].CommitCount != 7 {
+		t.Errorf("Expected two weeks of 7 deployments, got %+v", stats.Periods)
+	}
+	if stats.MedianGapHours != 24 || stats.SinceLast != 24*time.Hour {
+		t.Errorf("Expected a 24h median gap and 24h since last, got %.1f and %v", stats.MedianGapHours, stats.SinceLast)
+	}
+
+	// A window wider than the deployments lowers the rate
+	stats = calculateDeploymentFrequencyStats(deployments, now.AddDate(0, 0, -70), now, "week")
+	if math.Abs(stats.PerWeek-1.4) > 1e-9 || stats.Classification != "High" {
+		t.Errorf("Expected 1.4 deploys per week (High), got %.2f (%s)", stats.PerWeek, stats.Classification)
+	}
+
+	if stats := calculateDeploymentFrequencyStats(nil, time.Time{}, now, "week"); stats.Classification != "Unknown" {
+		t.Errorf("Expected Unknown without deployments, got %s", stats.Classification)
+	}
+}
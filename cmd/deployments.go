@@ -0,0 +1,586 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Deployments are what the DORA commands measure against. Git has no record
+// of what reached production, so a deployment is inferred from one of:
+// - tags: release tags matching a pattern (lightweight or annotated)
+// - merges: merge commits on the production branch's first-parent history
+// - log: a deploy log exported from the CD system (CSV, JSON or JSON lines with
+//   a sha, a timestamp and optionally an environment)
+//
+// Every command reads the same flags, then its own config section, then the
+// shared deployments section, so one deploy log configured once feeds all of
+// them.
+
+const (
+	deploySourceTags   = "tags"
+	deploySourceMerges = "merges"
+	deploySourceLog    = "log"
+)
+
+// Deploy log formats
+const (
+	deployFormatCSV       = "csv"
+	deployFormatJSON      = "json"
+	deployFormatJSONLines = "jsonl"
+)
+
+// defaultDeployTagPattern matches semantic version tags such as v1.2.3 or 1.2.3-rc.1
+const defaultDeployTagPattern = `^v?\d+\.\d+\.\d+([-+].*)?$`
+
+// Deployment is one inferred release of a commit to production
+type Deployment struct {
+	Hash        plumbing.Hash
+	Time        time.Time
+	Ref         string // tag or branch name the deployment was found through
+	Source      string
+	Environment string // from the deploy log, empty for tags and merges
+}
+
+// DeploymentConfig says how deployments are found
+type DeploymentConfig struct {
+	Source      string
+	TagPattern  string
+	Branch      string // production branch for merges; empty means the default branch
+	LogFile     string // deploy log for the log source
+	LogFormat   string // csv, json or jsonl; empty means by file extension
+	Environment string // only count deploy log entries for this environment
+}
+
+// addDeploymentFlags registers the flags shared by every command that needs deployments
+func addDeploymentFlags(cmd *cobra.Command) {
+	cmd.Flags().String("deploy-source", "", "How deployments are found: tags, merges or log (default tags, or log with --deploy-log)")
+	cmd.Flags().String("tag-pattern", "", "Regex matching release tags when --deploy-source is tags (default semver)")
+	cmd.Flags().String("branch", "", "Production branch whose merges are deployments when --deploy-source is merges (default: the default branch)")
+	cmd.Flags().String("deploy-log", "", "Deploy log file (CSV, JSON or JSON lines with sha and timestamp) when --deploy-source is log")
+	cmd.Flags().String("deploy-format", "", "Deploy log format: csv, json or jsonl (default by file extension)")
+	cmd.Flags().String("environment", "", "Only count deploy log entries for this environment (e.g., production)")
+}
+
+// getDeploymentConfig reads the deployment flags, falling back to
+// <configKey>.deploy_source, <configKey>.tag_pattern, <configKey>.branch,
+// <configKey>.deploy_log, <configKey>.deploy_format and
+// <configKey>.environment in the config file, then to the shared
+// deployments.source, deployments.tag_pattern, deployments.branch,
+// deployments.file, deployments.format and deployments.environment, then to
+// the defaults
+func getDeploymentConfig(cmd *cobra.Command, configKey string) (DeploymentConfig, error) {
+	value := func(flag, key, shared, fallback string) string {
+		if v, _ := cmd.Flags().GetString(flag); v != "" {
+			return v
+		}
+		if v := viper.GetString(configKey + "." + key); v != "" {
+			return v
+		}
+		if v := viper.GetString("deployments." + shared); v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	cfg := DeploymentConfig{
+		TagPattern:  value("tag-pattern", "tag_pattern", "tag_pattern", defaultDeployTagPattern),
+		Branch:      value("branch", "branch", "branch", ""),
+		LogFile:     value("deploy-log", "deploy_log", "file", ""),
+		LogFormat:   value("deploy-format", "deploy_format", "format", ""),
+		Environment: value("environment", "environment", "environment", ""),
+	}
+	defaultSource := deploySourceTags
+	if cfg.LogFile != "" {
+		defaultSource = deploySourceLog
+	}
+	cfg.Source = value("deploy-source", "deploy_source", "source", defaultSource)
+
+	switch cfg.Source {
+	case deploySourceTags, deploySourceMerges:
+	case deploySourceLog:
+		if cfg.LogFile == "" {
+			return cfg, fmt.Errorf("--deploy-log is required when the deployment source is log")
+		}
+	default:
+		return cfg, fmt.Errorf("invalid deployment source %q (expected tags, merges or log)", cfg.Source)
+	}
+	switch cfg.LogFormat {
+	case "", deployFormatCSV, deployFormatJSON, deployFormatJSONLines:
+	default:
+		return cfg, fmt.Errorf("invalid deploy log format %q (expected csv, json or jsonl)", cfg.LogFormat)
+	}
+	if _, err := regexp.Compile(cfg.TagPattern); err != nil {
+		return cfg, fmt.Errorf("invalid tag pattern %q: %v", cfg.TagPattern, err)
+	}
+	return cfg, nil
+}
+
+// Describe summarizes the deployment definition for report headers
+func (cfg DeploymentConfig) Describe() string {
+	switch cfg.Source {
+	case deploySourceMerges:
+		branch := cfg.Branch
+		if branch == "" {
+			branch = "default branch"
+		}
+		return fmt.Sprintf("merges into %s", branch)
+	case deploySourceLog:
+		if cfg.Environment != "" {
+			return fmt.Sprintf("deploy log %s (%s)", cfg.LogFile, cfg.Environment)
+		}
+		return fmt.Sprintf("deploy log %s", cfg.LogFile)
+	default:
+		return fmt.Sprintf("tags matching %s", cfg.TagPattern)
+	}
+}
+
+// findDeployments returns the deployments at or after since (all when zero), oldest first
+func findDeployments(repo *git.Repository, cfg DeploymentConfig, since time.Time) ([]Deployment, error) {
+	var deployments []Deployment
+	var err error
+	switch cfg.Source {
+	case deploySourceMerges:
+		deployments, err = mergeDeployments(repo, cfg.Branch, since)
+	case deploySourceLog:
+		deployments, err = logDeployments(repo, cfg, since)
+	default:
+		deployments, err = tagDeployments(repo, regexp.MustCompile(cfg.TagPattern), since)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sortDeployments(deployments)
+	return deployments, nil
+}
+
+// sortDeployments orders deployments oldest first
+func sortDeployments(deployments []Deployment) {
+	sort.SliceStable(deployments, func(i, j int) bool {
+		if !deployments[i].Time.Equal(deployments[j].Time) {
+			return deployments[i].Time.Before(deployments[j].Time)
+		}
+		return deployments[i].Ref < deployments[j].Ref
+	})
+}
+
+// tagDeployments treats each release tag as a deployment. Annotated tags are
+// deployed when they were tagged; lightweight tags when their commit was
+// committed. A commit tagged several times is deployed once, at its earliest tag.
+func tagDeployments(repo *git.Repository, pattern *regexp.Regexp, since time.Time) ([]Deployment, error) {
+	tagRefs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+	defer tagRefs.Close()
+
+	byCommit := make(map[plumbing.Hash]Deployment)
+	err = tagRefs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !pattern.MatchString(name) {
+			return nil
+		}
+		deployment, ok := resolveTagDeployment(repo, ref)
+		if !ok {
+			return nil
+		}
+		if !since.IsZero() && deployment.Time.Before(since) {
+			return nil
+		}
+		if existing, seen := byCommit[deployment.Hash]; !seen || deployment.Time.Before(existing.Time) {
+			byCommit[deployment.Hash] = deployment
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make([]Deployment, 0, len(byCommit))
+	for _, d := range byCommit {
+		deployments = append(deployments, d)
+	}
+	return deployments, nil
+}
+
+// resolveTagDeployment peels a tag to its commit and deployment time
+func resolveTagDeployment(repo *git.Repository, ref *plumbing.Reference) (Deployment, bool) {
+	d := Deployment{Ref: ref.Name().Short(), Source: deploySourceTags}
+	if tag, err := repo.TagObject(ref.Hash()); err == nil {
+		commit, err := tag.Commit()
+		if err != nil {
+			return d, false
+		}
+		d.Hash, d.Time = commit.Hash, tag.Tagger.When
+		return d, true
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return d, false
+	}
+	d.Hash, d.Time = commit.Hash, commit.Committer.When
+	return d, true
+}
+
+// walkDeployedCommits visits every commit shipped by deployments exactly
+// once, attributing it to the first deployment that contains it, then the
+// commits reachable only from head, which are not deployed yet (deployment
+// -1). The walk doesn't go past commits committed before stop, when set.
+// deployments must be sorted oldest first; entries without a commit are
+// skipped.
+func walkDeployedCommits(repo *git.Repository, deployments []Deployment, head plumbing.Hash, stop time.Time, fn func(c *object.Commit, deployment int) error) error {
+	seen := make(map[plumbing.Hash]bool)
+	walk := func(from plumbing.Hash, deployment int) error {
+		stack := []plumbing.Hash{from}
+		for len(stack) > 0 {
+			hash := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[hash] {
+				continue
+			}
+			seen[hash] = true
+
+			c, err := repo.CommitObject(hash)
+			if err != nil {
+				return fmt.Errorf("could not read commit %s: %v", hash.String()[:7], err)
+			}
+			if !stop.IsZero() && c.Committer.When.Before(stop) {
+				continue
+			}
+			if err := fn(c, deployment); err != nil {
+				return err
+			}
+			stack = append(stack, c.ParentHashes...)
+		}
+		return nil
+	}
+
+	for i, d := range deployments {
+		if d.Hash.IsZero() {
+			continue
+		}
+		if err := walk(d.Hash, i); err != nil {
+			return err
+		}
+	}
+	if head.IsZero() {
+		return nil
+	}
+	return walk(head, -1)
+}
+
+// resolveDeployBranch returns the production branch reference; an empty name
+// means the default branch
+func resolveDeployBranch(repo *git.Repository, branch string) (*plumbing.Reference, error) {
+	if branch == "" {
+		return getDefaultBranch(repo)
+	}
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(branch),
+		plumbing.NewRemoteReferenceName("origin", branch),
+		plumbing.ReferenceName(branch),
+	} {
+		if ref, err := repo.Reference(name, true); err == nil {
+			return ref, nil
+		}
+	}
+	return nil, fmt.Errorf("branch %q not found", branch)
+}
+
+// mergeDeployments treats each merge commit on the production branch's
+// first-parent history as a deployment, at its commit time
+func mergeDeployments(repo *git.Repository, branch string, since time.Time) ([]Deployment, error) {
+	ref, err := resolveDeployBranch(repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("could not get branch head: %v", err)
+	}
+
+	name := strings.TrimPrefix(ref.Name().Short(), "origin/")
+	var deployments []Deployment
+	iter := &firstParentIter{next: head}
+	err = iter.ForEach(func(c *object.Commit) error {
+		if !since.IsZero() && c.Committer.When.Before(since) {
+			return storer.ErrStop
+		}
+		if c.NumParents() > 1 {
+			deployments = append(deployments, Deployment{Hash: c.Hash, Time: c.Committer.When, Ref: name, Source: deploySourceMerges})
+		}
+		return nil
+	})
+	return deployments, err
+}
+
+// deployLogEntry is one row of a deploy log
+type deployLogEntry struct {
+	SHA         string
+	Time        time.Time
+	Environment string
+}
+
+// deployLogSHAFields, deployLogTimeFields and deployLogEnvironmentFields are
+// the accepted column or key names, matched case-insensitively
+var (
+	deployLogSHAFields         = []string{"sha", "commit", "hash", "revision"}
+	deployLogTimeFields        = []string{"timestamp", "time", "deployed_at", "date"}
+	deployLogEnvironmentFields = []string{"environment", "env"}
+)
+
+// parseDeployTime accepts RFC 3339, "2006-01-02 15:04:05", a bare date or Unix seconds
+func parseDeployTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
+
+// fieldIndex returns the position of the first header matching one of names, or -1
+func fieldIndex(header []string, names []string) int {
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, name := range names {
+			if h == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseDeployLogCSV reads a CSV deploy log. With a header row the sha,
+// timestamp and environment columns are found by name; without one the sha
+// and timestamp are the first two columns and the environment the third, if any.
+func parseDeployLogCSV(r io.Reader) ([]deployLogEntry, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	shaCol, timeCol, envCol := 0, 1, 2
+	if i, j := fieldIndex(rows[0], deployLogSHAFields), fieldIndex(rows[0], deployLogTimeFields); i >= 0 && j >= 0 {
+		shaCol, timeCol, envCol = i, j, fieldIndex(rows[0], deployLogEnvironmentFields)
+		rows = rows[1:]
+	}
+
+	var entries []deployLogEntry
+	for n, row := range rows {
+		if shaCol >= len(row) || timeCol >= len(row) {
+			return nil, fmt.Errorf("row %d: expected sha and timestamp columns", n+1)
+		}
+		t, err := parseDeployTime(row[timeCol])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %v", n+1, err)
+		}
+		entry := deployLogEntry{SHA: strings.TrimSpace(row[shaCol]), Time: t}
+		if envCol >= 0 && envCol < len(row) {
+			entry.Environment = strings.TrimSpace(row[envCol])
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// decodeJSONRecords reads a JSON array of objects or JSON lines, one object
+// per line. Numbers are kept as written so Unix timestamps aren't rendered in
+// exponent form.
+func decodeJSONRecords(data []byte) ([]map[string]interface{}, error) {
+	var records []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := decoder.Decode(&records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+
+	for {
+		var record map[string]interface{}
+		if err := decoder.Decode(&record); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("line %d: %v", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
+
+// recordField returns the first field of a JSON record whose key matches one
+// of names case-insensitively, or "" when there is none
+func recordField(record map[string]interface{}, names []string) string {
+	for _, name := range names {
+		for key, v := range record {
+			if strings.EqualFold(key, name) && v != nil {
+				return strings.TrimSpace(fmt.Sprint(v))
+			}
+		}
+	}
+	return ""
+}
+
+// parseDeployLogJSON reads deploy objects from a JSON array or JSON lines
+func parseDeployLogJSON(data []byte) ([]deployLogEntry, error) {
+	records, err := decodeJSONRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []deployLogEntry
+	for n, record := range records {
+		sha, ts := recordField(record, deployLogSHAFields), recordField(record, deployLogTimeFields)
+		if sha == "" || ts == "" {
+			return nil, fmt.Errorf("record %d: expected sha and timestamp fields", n+1)
+		}
+		t, err := parseDeployTime(ts)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", n+1, err)
+		}
+		entries = append(entries, deployLogEntry{SHA: sha, Time: t, Environment: recordField(record, deployLogEnvironmentFields)})
+	}
+	return entries, nil
+}
+
+// deployLogFormat returns the configured format, or guesses it from the file
+// extension: .json is JSON, .jsonl and .ndjson are JSON lines, anything else CSV
+func deployLogFormat(path, format string) string {
+	if format != "" {
+		return format
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return deployFormatJSON
+	case ".jsonl", ".ndjson":
+		return deployFormatJSONLines
+	default:
+		return deployFormatCSV
+	}
+}
+
+// loadDeployLog reads a deploy log in the given format, or the one its file
+// extension suggests
+func loadDeployLog(path, format string) ([]deployLogEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read deploy log: %v", err)
+	}
+
+	var entries []deployLogEntry
+	switch deployLogFormat(path, format) {
+	case deployFormatJSON, deployFormatJSONLines:
+		entries, err = parseDeployLogJSON(data)
+	default:
+		entries, err = parseDeployLogCSV(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse deploy log %s: %v", path, err)
+	}
+	return entries, nil
+}
+
+// earliestDeployPerSHA keeps the first deploy of each sha, in log order, so a
+// commit promoted through several environments counts once
+func earliestDeployPerSHA(entries []deployLogEntry) []deployLogEntry {
+	first := make(map[string]int)
+	var kept []deployLogEntry
+	for _, e := range entries {
+		key := strings.ToLower(e.SHA)
+		if i, seen := first[key]; seen {
+			if e.Time.Before(kept[i].Time) {
+				kept[i] = e
+			}
+			continue
+		}
+		first[key] = len(kept)
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// logDeployments reads deployments from a deploy log, keeping only the
+// configured environment when one is set. Without one, each sha counts once,
+// at its earliest deploy, as with tags. Shas are resolved against the
+// repository; entries whose sha is unknown here are kept, so frequency still
+// counts them, but they can't be matched to commits.
+func logDeployments(repo *git.Repository, cfg DeploymentConfig, since time.Time) ([]Deployment, error) {
+	entries, err := loadDeployLog(cfg.LogFile, cfg.LogFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	var inScope []deployLogEntry
+	for _, e := range entries {
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		if cfg.Environment != "" && !strings.EqualFold(e.Environment, cfg.Environment) {
+			continue
+		}
+		inScope = append(inScope, e)
+	}
+	if cfg.Environment == "" {
+		inScope = earliestDeployPerSHA(inScope)
+	}
+
+	var deployments []Deployment
+	for _, e := range inScope {
+		ref := e.SHA
+		if len(ref) > 12 {
+			ref = ref[:12]
+		}
+		d := Deployment{Time: e.Time, Ref: ref, Source: deploySourceLog, Environment: e.Environment}
+		if hash, err := repo.ResolveRevision(plumbing.Revision(e.SHA)); err == nil {
+			d.Hash = *hash
+		}
+		deployments = append(deployments, d)
+	}
+	return deployments, nil
+}
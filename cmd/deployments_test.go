@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func TestDefaultDeployTagPattern(t *testing.T) {
+	pattern := regexp.MustCompile(defaultDeployTagPattern)
+	tests := []struct {
+		tag      string
+		expected bool
+	}{
+		{"v1.2.3", true},
+		{"1.2.3", true},
+		{"v1.2.3-rc.1", true},
+		{"v1.2", false},
+		{"release-2025", false},
+	}
+
+	for _, tt := range tests {
+		if result := pattern.MatchString(tt.tag); result != tt.expected {
+			t.Errorf("pattern.MatchString(%q) = %v, want %v", tt.tag, result, tt.expected)
+		}
+	}
+}
+
+func TestSortDeployments(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployments := []Deployment{
+		{Time: start.Add(time.Hour), Ref: "v2"},
+		{Time: start, Ref: "v1b"},
+		{Time: start, Ref: "v1a"},
+	}
+
+	sortDeployments(deployments)
+	if deployments[0].Ref != "v1a" || deployments[1].Ref != "v1b" || deployments[2].Ref != "v2" {
+		t.Errorf("Expected v1a, v1b, v2, got %s, %s, %s", deployments[0].Ref, deployments[1].Ref, deployments[2].Ref)
+	}
+}
+
+func TestEarliestDeployPerSHA(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []deployLogEntry{
+		{SHA: "abc123", Time: start.Add(2 * time.Hour), Environment: "production"},
+		{SHA: "ABC123", Time: start, Environment: "staging"},
+		{SHA: "def456", Time: start.Add(time.Hour), Environment: "staging"},
+		{SHA: "def456", Time: start.Add(3 * time.Hour), Environment: "production"},
+	}
+
+	kept := earliestDeployPerSHA(entries)
+	if len(kept) != 2 {
+		t.Fatalf("Expected one deploy per sha, got %+v", kept)
+	}
+	if kept[0].SHA != "ABC123" || !kept[0].Time.Equal(start) {
+		t.Errorf("Expected abc123's earlier staging deploy, got %+v", kept[0])
+	}
+	if kept[1].SHA != "def456" || kept[1].Environment != "staging" {
+		t.Errorf("Expected def456's staging deploy, got %+v", kept[1])
+	}
+}
+
+func TestDeploymentConfigDescribe(t *testing.T) {
+	if d := (DeploymentConfig{Source: deploySourceMerges}).Describe(); d != "merges into default branch" {
+		t.Errorf("Unexpected description %q", d)
+	}
+	if d := (DeploymentConfig{Source: deploySourceMerges, Branch: "production"}).Describe(); d != "merges into production" {
+		t.Errorf("Unexpected description %q", d)
+	}
+	if d := (DeploymentConfig{Source: deploySourceTags, TagPattern: "^v"}).Describe(); d != "tags matching ^v" {
+		t.Errorf("Unexpected description %q", d)
+	}
+	if d := (DeploymentConfig{Source: deploySourceLog, LogFile: "deploys.jsonl", Environment: "production"}).Describe(); d != "deploy log deploys.jsonl (production)" {
+		t.Errorf("Unexpected description %q", d)
+	}
+}
+
+func TestGetDeploymentConfigShared(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("deployments.file", "deploys.jsonl")
+	viper.Set("deployments.environment", "production")
+	viper.Set("dora.environment", "canary")
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		addDeploymentFlags(cmd)
+		return cmd
+	}
+
+	// The shared section applies to every command and implies the log source
+	cfg, err := getDeploymentConfig(newCmd(), "change-lead-time")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Source != deploySourceLog || cfg.LogFile != "deploys.jsonl" || cfg.Environment != "production" {
+		t.Errorf("Expected the shared deploy log, got %+v", cfg)
+	}
+
+	// A command's own section overrides it, and flags override both
+	if cfg, _ := getDeploymentConfig(newCmd(), "dora"); cfg.Environment != "canary" {
+		t.Errorf("Expected the dora environment, got %q", cfg.Environment)
+	}
+	cmd := newCmd()
+	cmd.Flags().Set("environment", "staging")
+	if cfg, _ := getDeploymentConfig(cmd, "dora"); cfg.Environment != "staging" {
+		t.Errorf("Expected the flag environment, got %q", cfg.Environment)
+	}
+
+	viper.Set("deployments.format", "xml")
+	if _, err := getDeploymentConfig(newCmd(), "change-lead-time"); err == nil {
+		t.Error("Expected an error for an unknown deploy log format")
+	}
+}
+
+func TestParseDeployTime(t *testing.T) {
+	want := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"2025-02-01T10:00:00Z", true},
+		{"2025-02-01 10:00:00", true},
+		{"1738404000", true},
+		{"yesterday", false},
+	}
+
+	for _, tt := range tests {
+		result, err := parseDeployTime(tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseDeployTime(%q) error = %v, want ok %v", tt.value, err, tt.ok)
+			continue
+		}
+		if tt.ok && !result.Equal(want) {
+			t.Errorf("parseDeployTime(%q) = %v, want %v", tt.value, result, want)
+		}
+	}
+}
+
+func TestParseDeployLogCSV(t *testing.T) {
+	// Columns are found by name when there is a header
+	entries, err := parseDeployLogCSV(strings.NewReader("env,deployed_at,sha\nprod,2025-02-01,abc123\nprod,2025-02-03,def456\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].SHA != "abc123" || entries[1].Time.Day() != 3 {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+
+	// Without a header the first two columns are sha and timestamp
+	entries, err = parseDeployLogCSV(strings.NewReader("abc123,2025-02-01\n"))
+	if err != nil || len(entries) != 1 || entries[0].SHA != "abc123" {
+		t.Errorf("Expected one headerless entry, got %+v (%v)", entries, err)
+	}
+
+	// The environment column is optional
+	entries, err = parseDeployLogCSV(strings.NewReader("sha,timestamp,environment\nabc123,2025-02-01,production\n"))
+	if err != nil || entries[0].Environment != "production" {
+		t.Errorf("Expected a production entry, got %+v (%v)", entries, err)
+	}
+	entries, err = parseDeployLogCSV(strings.NewReader("abc123,2025-02-01,staging\n"))
+	if err != nil || entries[0].Environment != "staging" {
+		t.Errorf("Expected a headerless staging entry, got %+v (%v)", entries, err)
+	}
+
+	if _, err := parseDeployLogCSV(strings.NewReader("sha,timestamp\nabc123,soon\n")); err == nil {
+		t.Error("Expected an error for an invalid timestamp")
+	}
+}
+
+func TestParseDeployLogJSON(t *testing.T) {
+	entries, err := parseDeployLogJSON([]byte(`[{"SHA": "abc123", "timestamp": "2025-02-01T10:00:00Z"}, {"commit": "def456", "time": 1738404000}]`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].SHA != "abc123" || entries[1].SHA != "def456" {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+
+	if _, err := parseDeployLogJSON([]byte(`[{"sha": "abc123"}]`)); err == nil {
+		t.Error("Expected an error for a record without a timestamp")
+	}
+
+	// JSON lines, one deployment per line
+	entries, err = parseDeployLogJSON([]byte(`{"sha": "abc123", "deployed_at": "2025-02-01T10:00:00Z", "env": "production"}
+{"sha": "def456", "deployed_at": "2025-02-02T10:00:00Z", "env": "staging"}
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Environment != "production" || entries[1].Environment != "staging" {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+
+	if _, err := parseDeployLogJSON([]byte("{\"sha\": \"abc123\"}\n{broken\n")); err == nil {
+		t.Error("Expected an error for a malformed line")
+	}
+}
+
+func TestDeployLogFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		format   string
+		expected string
+	}{
+		{"deploys.csv", "", deployFormatCSV},
+		{"deploys.json", "", deployFormatJSON},
+		{"deploys.JSONL", "", deployFormatJSONLines},
+		{"deploys.ndjson", "", deployFormatJSONLines},
+		{"deploys.log", "", deployFormatCSV},
+		{"deploys.log", deployFormatJSONLines, deployFormatJSONLines},
+	}
+
+	for _, tt := range tests {
+		if result := deployLogFormat(tt.path, tt.format); result != tt.expected {
+			t.Errorf("deployLogFormat(%q, %q) = %s, want %s", tt.path, tt.format, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,551 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// The dora scorecard combines the four key metrics. One walk over the
+// production history attributes every commit to the first deployment that
+// shipped it, which gives lead times, failure signals and the teams each
+// deployment touched; deployments and incidents come from the same sources as
+// deployment-frequency and time-to-restore.
+
+// doraLevels ranks classifications from best to worst
+var doraLevels = []string{"Elite", "High", "Medium", "Low"}
+
+// DORAMetrics are the four key metrics for one slice of history
+type DORAMetrics struct {
+	Deployments         int
+	DeploysPerWeek      float64
+	DeploymentFrequency string
+	LeadTimeCommits     int
+	MedianLeadTimeHours float64
+	LeadTime            string
+	FailedDeployments   int
+	ChangeFailureRate   float64
+	ChangeFailure       string
+	Incidents           int
+	Restored            int
+	MedianRestoreHours  float64
+	TimeToRestore       string
+	Overall             string
+}
+
+// DORAPeriod is the scorecard for one period of the trend
+type DORAPeriod struct {
+	Label   string
+	Start   time.Time
+	Metrics DORAMetrics
+}
+
+// DORATeam is the scorecard for one team
+type DORATeam struct {
+	Team    string
+	Metrics DORAMetrics
+}
+
+// DORAScorecard is the full report
+type DORAScorecard struct {
+	Overall DORAMetrics
+	Periods []DORAPeriod
+	Teams   []DORATeam
+}
+
+// doraHistory is what the walk gathers; deployments, deployTeams and outcomes
+// run in parallel, as do commits and commitTeams
+type doraHistory struct {
+	deployments  []Deployment
+	deployTeams  []map[string]bool
+	outcomes     []DeploymentOutcome
+	commits      []CommitLeadTime
+	commitTeams  []map[string]bool
+	restores     []IncidentRestore
+	restoreTeams []map[string]bool
+}
+
+// overallDORALevel returns the weakest of the known classifications: a team
+// is only as strong as its weakest key metric
+func overallDORALevel(levels ...string) string {
+	worst := -1
+	for _, level := range levels {
+		for rank, name := range doraLevels {
+			if level == name && rank > worst {
+				worst = rank
+			}
+		}
+	}
+	if worst < 0 {
+		return "Unknown"
+	}
+	return doraLevels[worst]
+}
+
+// getDORATeams reads dora.teams from the config: team names mapped to the
+// paths they own
+func getDORATeams() map[string][]string {
+	return viper.GetStringMapStringSlice("dora.teams")
+}
+
+// pathTeams returns the teams owning any of paths. Without configured teams
+// every top-level directory is a team.
+func pathTeams(paths []string, teams map[string][]string) map[string]bool {
+	owners := make(map[string]bool)
+	for _, path := range paths {
+		if len(teams) == 0 {
+			top, _, found := strings.Cut(path, "/")
+			if !found {
+				owners["root/"] = true
+			} else {
+				owners[top+"/"] = true
+			}
+			continue
+		}
+		for team, owned := range teams {
+			if matchesPathFilter(path, owned) {
+				owners[team] = true
+			}
+		}
+	}
+	return owners
+}
+
+// doraPeriodStart returns the start of the period containing t
+func doraPeriodStart(t time.Time, period string) time.Time {
+	t = t.UTC()
+	switch period {
+	case "week":
+		start, _, _ := calculateISOWeekPeriod(t)
+		return start
+	case "quarter":
+		return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// nextDORAPeriod returns the start of the period after the one starting at start
+func nextDORAPeriod(start time.Time, period string) time.Time {
+	switch period {
+	case "week":
+		return start.AddDate(0, 0, 7)
+	case "quarter":
+		return start.AddDate(0, 3, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// doraPeriods returns the start times of the last count periods up to now
+func doraPeriods(now time.Time, period string, count int) []time.Time {
+	starts := []time.Time{doraPeriodStart(now, period)}
+	for len(starts) < count {
+		// Step back from just before the earliest period found so far
+		starts = append(starts, doraPeriodStart(starts[len(starts)-1].Add(-time.Nanosecond), period))
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		return starts[i].Before(starts[j])
+	})
+	return starts
+}
+
+// inDORARange reports whether t lies in [start, end); a zero start is unbounded
+func inDORARange(t, start, end time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && t.Before(end)
+}
+
+// metrics computes the scorecard for [start, end), for one team or, when
+// team is empty, for everyone
+func (h *doraHistory) metrics(start, end time.Time, team string) DORAMetrics {
+	forTeam := func(teams map[string]bool) bool {
+		return team == "" || teams[team]
+	}
+
+	var deployments []Deployment
+	var outcomes []DeploymentOutcome
+	for i, d := range h.deployments {
+		if inDORARange(d.Time, start, end) && forTeam(h.deployTeams[i]) {
+			deployments = append(deployments, d)
+			outcomes = append(outcomes, h.outcomes[i])
+		}
+	}
+	var commits []CommitLeadTime
+	for i, c := range h.commits {
+		if inDORARange(c.DeployTime, start, end) && forTeam(h.commitTeams[i]) {
+			commits = append(commits, c)
+		}
+	}
+	var restores []IncidentRestore
+	for i, r := range h.restores {
+		if inDORARange(r.Incident.Start, start, end) && forTeam(h.restoreTeams[i]) {
+			restores = append(restores, r)
+		}
+	}
+
+	m := DORAMetrics{Deployments: len(deployments), LeadTimeCommits: len(commits), Incidents: len(restores)}
+
+	frequency := calculateDeploymentFrequencyStats(deployments, start, end, "week")
+	m.DeploysPerWeek, m.DeploymentFrequency = frequency.PerWeek, frequency.Classification
+
+	leadTime := calculateChangeLeadTimeStats(commits)
+	m.MedianLeadTimeHours, m.LeadTime = leadTime.MedianLeadTimeHours, leadTime.DORAPerformanceLevel
+
+	failures := calculateChangeFailureStats(outcomes)
+	m.FailedDeployments, m.ChangeFailureRate, m.ChangeFailure = failures.Failed, failures.FailureRate, failures.Classification
+
+	restore := calculateTimeToRestoreStats(restores, 0)
+	m.Restored, m.MedianRestoreHours, m.TimeToRestore = restore.Restored, restore.MedianHours, restore.Classification
+
+	m.Overall = overallDORALevel(m.DeploymentFrequency, m.LeadTime, m.ChangeFailure, m.TimeToRestore)
+	return m
+}
+
+// teamNames returns the teams seen in deployments, most deployments first
+func (h *doraHistory) teamNames() []string {
+	counts := make(map[string]int)
+	for _, teams := range h.deployTeams {
+		for team := range teams {
+			counts[team]++
+		}
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// doraBaseline returns the index of the deployment that bounds the walk: the
+// last one before since, or without a window the first one, whose commits
+// reach back to the start of history. The baseline's commits predate the
+// measured deployments, so they don't count toward lead time. It is -1 when a
+// window is set and no deployment precedes it; since bounds the walk then, so
+// every deployment is measured.
+func doraBaseline(deployments []Deployment, since time.Time) int {
+	if since.IsZero() {
+		if len(deployments) == 0 {
+			return -1
+		}
+		return 0
+	}
+	baseline := -1
+	for i, d := range deployments {
+		if !d.Time.Before(since) {
+			break
+		}
+		baseline = i
+	}
+	return baseline
+}
+
+// collectDORAHistory walks the production history once and gathers everything
+// the scorecard needs
+func collectDORAHistory(repo *git.Repository, deployCfg DeploymentConfig, incidentCfg IncidentConfig, since time.Time, window time.Duration, patterns []*regexp.Regexp, tagPattern *regexp.Regexp, teams map[string][]string) (*doraHistory, error) {
+	all, err := findDeployments(repo, deployCfg, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("could not find deployments: %v", err)
+	}
+	branch, err := resolveDeployBranch(repo, deployCfg.Branch)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &doraHistory{}
+	stop := since
+	measured := 0 // the first deployment whose commits count toward lead time
+	if baseline := doraBaseline(all, since); baseline >= 0 {
+		// Commits older than the previous deployment shipped with it
+		all = all[baseline:]
+		stop = all[0].Time
+		measured = 1
+	}
+	h.deployments = all
+	h.deployTeams = make([]map[string]bool, len(all))
+	for i := range h.deployTeams {
+		h.deployTeams[i] = make(map[string]bool)
+	}
+
+	var signals []FailureSignal
+	commitTeams := make(map[plumbing.Hash]map[string]bool)
+	err = walkDeployedCommits(repo, all, branch.Hash(), stop, func(c *object.Commit, deployment int) error {
+		if since.IsZero() || !c.Committer.When.Before(since) {
+			if signal, ok := commitFailureSignal(c, patterns); ok {
+				signals = append(signals, signal)
+			}
+		}
+		if deployment < measured || c.NumParents() > 1 {
+			return nil
+		}
+
+		paths, err := commitChangedPaths(c, nil, true)
+		if err != nil {
+			return nil
+		}
+		owners := pathTeams(paths, teams)
+		for team := range owners {
+			h.deployTeams[deployment][team] = true
+		}
+		commitTeams[c.Hash] = owners
+
+		d := all[deployment]
+		leadTime := calculateLeadTime(c.Author.When, d.Time)
+		h.commits = append(h.commits, CommitLeadTime{
+			Hash:           c.Hash.String()[:8],
+			Author:         c.Author.Name,
+			CommitTime:     c.Author.When,
+			DeployTime:     d.Time,
+			LeadTimeHours:  leadTime,
+			Classification: classifyDORALeadTime(leadTime),
+			Message:        c.Message,
+		})
+		h.commitTeams = append(h.commitTeams, owners)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking commits: %v", err)
+	}
+
+	tagSignals, err := tagFailureSignals(repo, since, tagPattern)
+	if err != nil {
+		return nil, err
+	}
+	signals = append(signals, tagSignals...)
+	sortFailureSignals(signals)
+	h.outcomes = attributeFailures(all, signals, window)
+
+	incidents, _, err := findIncidents(repo, incidentCfg, since)
+	if err != nil {
+		return nil, fmt.Errorf("could not find incidents: %v", err)
+	}
+	h.restores = matchIncidentRestores(incidents, all)
+	deployTeams := make(map[plumbing.Hash]map[string]bool)
+	for i, d := range all {
+		deployTeams[d.Hash] = h.deployTeams[i]
+	}
+	for _, r := range h.restores {
+		owners := commitTeams[r.Hash]
+		if owners == nil {
+			owners = deployTeams[r.Hash]
+		}
+		h.restoreTeams = append(h.restoreTeams, owners)
+	}
+	return h, nil
+}
+
+// buildDORAScorecard computes the overall scorecard, the trend over the last
+// periods and the per-team breakdown
+func buildDORAScorecard(h *doraHistory, since, now time.Time, period string, periods int) *DORAScorecard {
+	card := &DORAScorecard{Overall: h.metrics(since, now, "")}
+
+	for _, start := range doraPeriods(now, period, periods) {
+		if !since.IsZero() && nextDORAPeriod(start, period).Before(since) {
+			continue
+		}
+		end := nextDORAPeriod(start, period)
+		if end.After(now) {
+			end = now
+		}
+		card.Periods = append(card.Periods, DORAPeriod{
+			Label:   formatPeriodLabel(TimePeriod{Start: start}, period),
+			Start:   start,
+			Metrics: h.metrics(start, end, ""),
+		})
+	}
+
+	for _, team := range h.teamNames() {
+		card.Teams = append(card.Teams, DORATeam{Team: team, Metrics: h.metrics(since, now, team)})
+	}
+	return card
+}
+
+// formatDORAHours formats a median in hours, or "-" without data
+func formatDORAHours(hours float64, n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return formatElapsed(hoursDuration(hours))
+}
+
+// formatDORARate formats a change failure rate, or "-" without deployments
+func formatDORARate(m DORAMetrics) string {
+	if m.Deployments == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", m.ChangeFailureRate)
+}
+
+// printDORAScorecard displays the scorecard
+func printDORAScorecard(card *DORAScorecard, deployCfg DeploymentConfig, incidentCfg IncidentConfig, period string, limit int) {
+	fmt.Println("DORA Scorecard")
+	fmt.Printf("Deployments: %s\n", deployCfg.Describe())
+	fmt.Printf("Incidents: %s\n", incidentCfg.Describe())
+	fmt.Println()
+
+	m := card.Overall
+	fmt.Printf("%-24s %-32s %s\n", "Metric", "Value", "DORA")
+	fmt.Printf("%-24s %-32s %s\n", "Deployment frequency", fmt.Sprintf("%.1f per week (%d deployments)", m.DeploysPerWeek, m.Deployments), m.DeploymentFrequency)
+	fmt.Printf("%-24s %-32s %s\n", "Lead time for changes", fmt.Sprintf("median %s (%d commits)", formatDORAHours(m.MedianLeadTimeHours, m.LeadTimeCommits), m.LeadTimeCommits), m.LeadTime)
+	fmt.Printf("%-24s %-32s %s\n", "Change failure rate", fmt.Sprintf("%s (%d of %d deployments)", formatDORARate(m), m.FailedDeployments, m.Deployments), m.ChangeFailure)
+	fmt.Printf("%-24s %-32s %s\n", "Time to restore", fmt.Sprintf("median %s (%d incidents)", formatDORAHours(m.MedianRestoreHours, m.Restored), m.Incidents), m.TimeToRestore)
+	fmt.Printf("%-24s %-32s %s\n", "Overall", "", m.Overall)
+
+	row := func(name string, m DORAMetrics) {
+		fmt.Printf("%-16s %8d %9.1f %10s %9s %9s  %s\n", name, m.Deployments, m.DeploysPerWeek, formatDORAHours(m.MedianLeadTimeHours, m.LeadTimeCommits), formatDORARate(m), formatDORAHours(m.MedianRestoreHours, m.Restored), m.Overall)
+	}
+	header := func(name string) {
+		fmt.Printf("%-16s %8s %9s %10s %9s %9s  %s\n", name, "Deploys", "Per week", "Lead time", "Failures", "Restore", "Overall")
+	}
+
+	if len(card.Periods) > 0 {
+		fmt.Printf("\nTrend by %s:\n", period)
+		header(titleCase(period))
+		for _, p := range card.Periods {
+			row(p.Label, p.Metrics)
+		}
+	}
+
+	if len(card.Teams) > 0 {
+		fmt.Printf("\nBy team (most deployments first, showing %d):\n", min(len(card.Teams), limit))
+		header("Team")
+		for i, t := range card.Teams {
+			if i >= limit {
+				break
+			}
+			row(t.Team, t.Metrics)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Overall is the weakest of the four classifications. Lead time and failures are medians and rates over deployments in each slice.")
+}
+
+// doraCmd represents the dora command
+var doraCmd = &cobra.Command{
+	Use:   "dora",
+	Short: "Report all four DORA key metrics as one scorecard",
+	Long: `Report deployment frequency, lead time for changes, change failure rate and
+time to restore service together, with an overall classification, a trend
+over recent periods and a breakdown by team.
+
+Deployments and incidents are found as in deployment-frequency and
+time-to-restore, configured once under the dora: key of .gitallica.yaml
+(deploy_source, tag_pattern, branch, deploy_log, incident_source,
+incident_prefix, notes_ref, incidents, failure_patterns). One walk over the
+production history attributes every commit to the first deployment that
+shipped it; lead time runs from authoring to that deployment.
+
+Teams are configured as dora.teams, mapping team names to the paths they own;
+without it every top-level directory is a team. A deployment counts for
+every team whose code it shipped.
+
+The overall classification is the weakest of the four metrics.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lastArg, _ := cmd.Flags().GetString("last")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		periodArg, _ := cmd.Flags().GetString("period")
+		periodsArg, _ := cmd.Flags().GetInt("periods")
+		hoursArg, _ := cmd.Flags().GetInt("hours")
+		tagPatternArg, _ := cmd.Flags().GetString("failure-tag-pattern")
+		switch periodArg {
+		case "week", "month", "quarter":
+		default:
+			return fmt.Errorf("invalid --period value %q (expected week, month or quarter)", periodArg)
+		}
+		if hoursArg <= 0 {
+			return fmt.Errorf("--hours must be positive, got %d", hoursArg)
+		}
+
+		deployCfg, err := getDeploymentConfig(cmd, "dora")
+		if err != nil {
+			return err
+		}
+		incidentCfg, err := getIncidentConfig(cmd, "dora")
+		if err != nil {
+			return err
+		}
+		patterns, _ := getMessagePatterns(cmd, "failure-pattern", "dora.failure_patterns", defaultFailurePatterns)
+		failurePatterns, err := compileMessagePatterns(patterns)
+		if err != nil {
+			return err
+		}
+		failureTagPattern, err := regexp.Compile(tagPatternArg)
+		if err != nil {
+			return fmt.Errorf("invalid failure tag pattern %q: %v", tagPatternArg, err)
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "dora", lastArg, nil, "")
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			return fmt.Errorf("could not open repository: %v", err)
+		}
+
+		var since time.Time
+		if lastArg != "" {
+			since, err = parseDurationArg(lastArg)
+			if err != nil {
+				return fmt.Errorf("invalid time window '%s': %v", lastArg, err)
+			}
+		}
+
+		window := time.Duration(hoursArg) * time.Hour
+		history, err := collectDORAHistory(repo, deployCfg, incidentCfg, since, window, failurePatterns, failureTagPattern, getDORATeams())
+		if err != nil {
+			return err
+		}
+
+		card := buildDORAScorecard(history, since, time.Now(), periodArg, periodsArg)
+		printDORAScorecard(card, deployCfg, incidentCfg, periodArg, limitArg)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doraCmd)
+	doraCmd.Flags().String("last", "", "Specify the time window to analyze (e.g., 30d, 6m, 1y)")
+	doraCmd.Flags().Int("limit", 10, "Number of teams to show")
+	doraCmd.Flags().String("period", "month", "Trend period: week, month or quarter")
+	doraCmd.Flags().Int("periods", 6, "Number of recent periods in the trend")
+	doraCmd.Flags().Int("hours", defaultFailureWindowHours, "Hours after a deployment within which a repair counts as its failure")
+	doraCmd.Flags().StringSlice("failure-pattern", []string{}, "Regex matching repair commit messages (can be specified multiple times; replaces the defaults)")
+	doraCmd.Flags().String("failure-tag-pattern", defaultFailureTagPattern, "Regex matching hotfix tags")
+	addDeploymentFlags(doraCmd)
+	addIncidentFlags(doraCmd)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOverallDORALevel(t *testing.T) {
+	tests := []struct {
+		levels   []string
+		expected string
+	}{
+		{[]string{"Elite", "Elite", "Elite", "Elite"}, "Elite"},
+		{[]string{"Elite", "Medium", "High", "Elite"}, "Medium"},
+		{[]string{"Elite", "Unknown", "Low", "High"}, "Low"},
+		{[]string{"High", "Unknown"}, "High"},
+		{[]string{"Unknown", "Unknown"}, "Unknown"},
+	}
+
+	for _, tt := range tests {
+		if result := overallDORALevel(tt.levels...); result != tt.expected {
+			t.Errorf("overallDORALevel(%v) = %s, want %s", tt.levels, result, tt.expected)
+		}
+	}
+}
+
+func TestPathTeams(t *testing.T) {
+	paths := []string{"api/handler.go", "web/app.js", "README.md"}
+
+	owners := pathTeams(paths, nil)
+	if len(owners) != 3 || !owners["api/"] || !owners["web/"] || !owners["root/"] {
+		t.Errorf("Expected top-level directories as teams, got %v", owners)
+	}
+
+	teams := map[string][]string{"backend": {"api/"}, "docs": {"docs/"}}
+	owners = pathTeams(paths, teams)
+	if len(owners) != 1 || !owners["backend"] {
+		t.Errorf("Expected only backend, got %v", owners)
+	}
+}
+
+func TestDORAPeriods(t *testing.T) {
+	now := time.Date(2025, 5, 14, 12, 0, 0, 0, time.UTC)
+
+	months := doraPeriods(now, "month", 3)
+	if len(months) != 3 || months[0] != time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC) || months[2] != time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Unexpected months %v", months)
+	}
+
+	quarters := doraPeriods(now, "quarter", 2)
+	if quarters[0] != time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) || quarters[1] != time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Unexpected quarters %v", quarters)
+	}
+	if label := formatPeriodLabel(TimePeriod{Start: quarters[1]}, "quarter"); label != "2025-Q2" {
+		t.Errorf("Expected 2025-Q2, got %s", label)
+	}
+
+	weeks := doraPeriods(now, "week", 2)
+	if weeks[1].Weekday() != time.Monday || weeks[1].Sub(weeks[0]) != 7*24*time.Hour {
+		t.Errorf("Expected consecutive Monday-based weeks, got %v", weeks)
+	}
+}
+
+func TestDORABaseline(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployments := []Deployment{{Time: start}, {Time: start.AddDate(0, 0, 10)}, {Time: start.AddDate(0, 0, 20)}}
+
+	// Without a window the first deployment ships all earlier history
+	if b := doraBaseline(deployments, time.Time{}); b != 0 {
+		t.Errorf("Expected the first deployment without a window, got %d", b)
+	}
+	if b := doraBaseline(nil, time.Time{}); b != -1 {
+		t.Errorf("Expected no baseline without deployments, got %d", b)
+	}
+	if b := doraBaseline(deployments, start.AddDate(0, 0, 15)); b != 1 {
+		t.Errorf("Expected the last deployment before the window, got %d", b)
+	}
+	if b := doraBaseline(deployments, start.AddDate(0, 0, 25)); b != 2 {
+		t.Errorf("Expected the last deployment when all precede the window, got %d", b)
+	}
+	// Every deployment inside the window is measured, the first one included
+	if b := doraBaseline(deployments, start.AddDate(0, 0, -5)); b != -1 {
+		t.Errorf("Expected no baseline when none precede the window, got %d", b)
+	}
+	if b := doraBaseline(deployments, start); b != -1 {
+		t.Errorf("Expected no baseline for a deployment at the window start, got %d", b)
+	}
+}
+
+func TestDORAHistoryMetrics(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	backend := map[string]bool{"backend": true}
+	frontend := map[string]bool{"frontend": true}
+
+	h := &doraHistory{
+		deployments: []Deployment{{Time: day(1), Ref: "v1"}, {Time: day(2), Ref: "v2"}, {Time: day(3), Ref: "v3"}},
+		deployTeams: []map[string]bool{backend, frontend, backend},
+		commits: []CommitLeadTime{
+			{CommitTime: day(0), DeployTime: day(1), LeadTimeHours: 24},
+			{CommitTime: day(2), DeployTime: day(2), LeadTimeHours: 0.5},
+			{CommitTime: day(2), DeployTime: day(3), LeadTimeHours: 12},
+		},
+		commitTeams: []map[string]bool{backend, frontend, backend},
+		restores: []IncidentRestore{
+			{Incident: Incident{ID: "INC-1", Start: day(1)}, Restored: true, RestoredAt: day(1).Add(30 * time.Minute)},
+		},
+		restoreTeams: []map[string]bool{backend},
+	}
+	h.outcomes = []DeploymentOutcome{
+		{Deployment: h.deployments[0], Failures: []FailureSignal{{Kind: failureKindRevert}}},
+		{Deployment: h.deployments[1]},
+		{Deployment: h.deployments[2]},
+	}
+
+	all := h.metrics(time.Time{}, day(4), "")
+	if all.Deployments != 3 || all.LeadTimeCommits != 3 || all.FailedDeployments != 1 || all.Incidents != 1 {
+		t.Errorf("Unexpected overall metrics %+v", all)
+	}
+	if all.MedianLeadTimeHours != 12 || all.TimeToRestore != "Elite" {
+		t.Errorf("Expected a 12h median lead time and Elite restore, got %.1f and %s", all.MedianLeadTimeHours, all.TimeToRestore)
+	}
+
+	team := h.metrics(time.Time{}, day(4), "backend")
+	if team.Deployments != 2 || team.LeadTimeCommits != 2 || team.ChangeFailureRate != 50 || team.Incidents != 1 {
+		t.Errorf("Unexpected backend metrics %+v", team)
+	}
+	if team := h.metrics(time.Time{}, day(4), "frontend"); team.Incidents != 0 || team.FailedDeployments != 0 {
+		t.Errorf("Unexpected frontend metrics %+v", team)
+	}
+
+	// Periods are half-open
+	if period := h.metrics(day(2), day(3), ""); period.Deployments != 1 || period.LeadTimeCommits != 1 {
+		t.Errorf("Expected one deployment and commit in the period, got %+v", period)
+	}
+}
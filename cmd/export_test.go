@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"database/sql"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// newTestRepo creates an empty in-memory repository with a worktree
+func newTestRepo(t *testing.T) *git.Repository {
+	t.Helper()
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Unexpected error creating repository: %v", err)
+	}
+	return repo
+}
+
+// testRepoCommit writes files into the worktree and commits them as author
+func testRepoCommit(t *testing.T, repo *git.Repository, files map[string]string, author string, when time.Time) plumbing.Hash {
+	t.Helper()
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Unexpected error opening worktree: %v", err)
+	}
+	for name, content := range files {
+		if err := util.WriteFile(wt.Filesystem, name, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("Unexpected error adding %s: %v", name, err)
+		}
+	}
+	sig := &object.Signature{Name: author, Email: author + "@example.com", When: when}
+	hash, err := wt.Commit("Update "+author, &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		t.Fatalf("Unexpected error committing: %v", err)
+	}
+	return hash
+}
+
+// countExportRows returns the row count of an export table
+func countExportRows(t *testing.T, db *sql.DB, query string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query).Scan(&n); err != nil {
+		t.Fatalf("Unexpected error running %q: %v", query, err)
+	}
+	return n
+}
+
+func TestClassifyChange(t *testing.T) {
+	tests := []struct {
+		name            string
+		action          merkletrie.Action
+		from, to        string
+		expectedType    string
+		expectedPath    string
+		expectedOldPath string
+	}{
+		{"added", merkletrie.Insert, "", "a.go", changeTypeAdded, "a.go", ""},
+		{"deleted", merkletrie.Delete, "a.go", "", changeTypeDeleted, "a.go", ""},
+		{"modified", merkletrie.Modify, "a.go", "a.go", changeTypeModified, "a.go", ""},
+		{"renamed", merkletrie.Modify, "old/a.go", "new/a.go", changeTypeRenamed, "new/a.go", "old/a.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeType, path, oldPath := classifyChange(tt.action, tt.from, tt.to)
+			if changeType != tt.expectedType || path != tt.expectedPath || oldPath != tt.expectedOldPath {
+				t.Errorf("classifyChange() = (%s, %s, %s), want (%s, %s, %s)",
+					changeType, path, oldPath, tt.expectedType, tt.expectedPath, tt.expectedOldPath)
+			}
+		})
+	}
+}
+
+func TestCheckExportCompatibility(t *testing.T) {
+	defer func(policy string, firstParent bool) {
+		mergePolicyArg, firstParentArg = policy, firstParent
+	}(mergePolicyArg, firstParentArg)
+	mergePolicyArg, firstParentArg = "", false
+
+	current := strconv.Itoa(exportSchemaVersion)
+	tests := []struct {
+		name        string
+		meta        map[string]string
+		expectError bool
+	}{
+		{"new database", map[string]string{}, false},
+		{"same settings", map[string]string{"schema_version": current, "merge_policy": "skip", "first_parent": "false"}, false},
+		{"old schema version", map[string]string{"schema_version": "0", "merge_policy": "skip", "first_parent": "false"}, true},
+		{"different merge policy", map[string]string{"schema_version": current, "merge_policy": "all", "first_parent": "false"}, true},
+		{"different walk mode", map[string]string{"schema_version": current, "merge_policy": "skip", "first_parent": "true"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkExportCompatibility(tt.meta)
+			if (err != nil) != tt.expectError {
+				t.Errorf("checkExportCompatibility() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestExportToSQLiteIncremental(t *testing.T) {
+	defer func(policy string, firstParent bool) {
+		mergePolicyArg, firstParentArg = policy, firstParent
+	}(mergePolicyArg, firstParentArg)
+	mergePolicyArg, firstParentArg = "", false
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newTestRepo(t)
+	testRepoCommit(t, repo, map[string]string{"main.go": "package main\n", "README.md": "# demo\n"}, "alice", start)
+	testRepoCommit(t, repo, map[string]string{"main.go": "package main\n\nfunc main() {}\n"}, "bob", start.Add(time.Hour))
+
+	dbPath := filepath.Join(t.TempDir(), "export.db")
+	stats, err := exportToSQLite(repo, dbPath, false)
+	if err != nil {
+		t.Fatalf("Unexpected error on first export: %v", err)
+	}
+	if stats.Incremental || stats.NewCommits != 2 || stats.NewFileChanges != 3 || stats.Authors != 2 {
+		t.Errorf("Expected a full export of 2 commits, 3 file changes and 2 authors, got %+v", stats)
+	}
+
+	testRepoCommit(t, repo, map[string]string{"util.go": "package main\n"}, "alice", start.Add(2*time.Hour))
+	stats, err = exportToSQLite(repo, dbPath, false)
+	if err != nil {
+		t.Fatalf("Unexpected error on incremental export: %v", err)
+	}
+	if !stats.Incremental || stats.NewCommits != 1 || stats.TotalCommits != 3 || stats.NewFileChanges != 1 {
+		t.Errorf("Expected an incremental export of 1 new commit, got %+v", stats)
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Unexpected error opening export: %v", err)
+	}
+	defer db.Close()
+
+	checks := []struct {
+		query    string
+		expected int
+	}{
+		{`SELECT COUNT(*) FROM commits`, 3},
+		{`SELECT COUNT(DISTINCT hash) FROM commits`, 3},
+		{`SELECT COUNT(*) FROM commit_parents`, 2},
+		{`SELECT COUNT(*) FROM file_changes`, 4},
+		{`SELECT COUNT(DISTINCT commit_hash || path) FROM file_changes`, 4},
+		{`SELECT COUNT(*) FROM authors`, 2},
+		{`SELECT COUNT(*) FROM author_aliases`, 2},
+	}
+	for _, c := range checks {
+		if n := countExportRows(t, db, c.query); n != c.expected {
+			t.Errorf("%s = %d, want %d", c.query, n, c.expected)
+		}
+	}
+
+	stats, err = exportToSQLite(repo, dbPath, false)
+	if err != nil {
+		t.Fatalf("Unexpected error on unchanged export: %v", err)
+	}
+	if stats.NewCommits != 0 || stats.TotalCommits != 3 {
+		t.Errorf("Expected nothing new when HEAD is exported, got %+v", stats)
+	}
+}
+
+func TestExportCommitIterStopsAtExported(t *testing.T) {
+	defer func(firstParent bool) { firstParentArg = firstParent }(firstParentArg)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newTestRepo(t)
+	root := testRepoCommit(t, repo, map[string]string{"a.txt": "1\n"}, "alice", start)
+	exported := testRepoCommit(t, repo, map[string]string{"a.txt": "2\n"}, "alice", start.Add(time.Hour))
+	head := testRepoCommit(t, repo, map[string]string{"a.txt": "3\n"}, "alice", start.Add(2*time.Hour))
+
+	// Only the newest exported commit is known, so reaching the root means the
+	// walk went past it
+	known := map[string]bool{exported.String(): true}
+	for _, firstParent := range []bool{false, true} {
+		firstParentArg = firstParent
+		iter, err := exportCommitIter(repo, head, known)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var visited []string
+		for {
+			c, err := iter.Next()
+			if err != nil {
+				break
+			}
+			if known[c.Hash.String()] {
+				break // exportToSQLite stops the first-parent chain here
+			}
+			visited = append(visited, c.Hash.String())
+		}
+		iter.Close()
+		sort.Strings(visited)
+		if len(visited) != 1 || visited[0] != head.String() {
+			t.Errorf("firstParent=%v: expected only HEAD, got %v (root %s)", firstParent, visited, root)
+		}
+	}
+}
@@ -61,6 +61,7 @@ type HealthReport struct {
 	MediumIssues   int
 	LowIssues      int
 	Issues         []HealthIssue
+	Results        map[string]interface{} // per-analyzer results, keyed by analyzer name
 	Summary        string
 }
 
@@ -104,52 +105,58 @@ func categorizeIssue(metric string) string {
 	}
 }
 
-// analyzeChurnHealth checks churn patterns for issues
-func analyzeChurnHealth(repo *git.Repository, since time.Time, pathFilters []string) []HealthIssue {
-	var issues []HealthIssue
-	
-	// Run churn analysis
-	ref, err := repo.Head()
-	if err != nil {
-		return issues
-	}
-	
-	cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+// ChurnHealthResult holds the churn figures behind the churn health check
+type ChurnHealthResult struct {
+	Additions    int
+	Deletions    int
+	TotalLOC     int
+	ChurnPercent float64
+}
+
+// churnHealthAnalyzer checks churn patterns for issues
+type churnHealthAnalyzer struct {
+	noFlags
+	additions int
+	deletions int
+}
+
+func (a *churnHealthAnalyzer) Name() string { return "churn" }
+
+func (a *churnHealthAnalyzer) Start(ctx *AnalysisContext) error {
+	a.additions, a.deletions = 0, 0
+	return nil
+}
+
+func (a *churnHealthAnalyzer) ProcessCommit(ctx *AnalysisContext, c *object.Commit) error {
+	additions, deletions, _, err := processCommitForSize(c, ctx.PathFilters)
 	if err != nil {
-		return issues
+		return nil // Skip commits with errors
 	}
+	a.additions += additions
+	a.deletions += deletions
+	return nil
+}
+
+func (a *churnHealthAnalyzer) Finalize(ctx *AnalysisContext) (interface{}, []HealthIssue, error) {
+	var issues []HealthIssue
 	
-	var additions, deletions int
-	err = cIter.ForEach(func(c *object.Commit) error {
-		if !since.IsZero() && c.Committer.When.Before(since) {
-			return nil
-		}
-		// Use the existing function from the codebase
-		additions_c, deletions_c, _, err := processCommitForSize(c, pathFilters)
-		if err != nil {
-			return nil // Skip commits with errors
-		}
-		additions += additions_c
-		deletions += deletions_c
-		return nil
-	})
+	// Calculate churn percentage
+	ref, err := ctx.Repo.Head()
 	if err != nil {
-		return issues
+		return nil, nil, err
 	}
-	
-	// Calculate churn percentage
-	headCommit, err := repo.CommitObject(ref.Hash())
+	headCommit, err := ctx.Repo.CommitObject(ref.Hash())
 	if err != nil {
-		return issues
+		return nil, nil, err
 	}
 	tree, err := headCommit.Tree()
 	if err != nil {
-		return issues
+		return nil, nil, err
 	}
 	
 	var totalLOC int
 	tree.Files().ForEach(func(f *object.File) error {
-		if !matchesPathFilter(f.Name, pathFilters) {
+		if !matchesPathFilter(f.Name, ctx.PathFilters) {
 			return nil
 		}
 		isBinary, err := f.IsBinary()
@@ -164,8 +171,10 @@ func analyzeChurnHealth(repo *git.Repository, since time.Time, pathFilters []str
 		return nil
 	})
 	
+	result := &ChurnHealthResult{Additions: a.additions, Deletions: a.deletions, TotalLOC: totalLOC}
 	if totalLOC > 0 {
-		churnPercent := float64(additions+deletions) / float64(totalLOC) * 100
+		churnPercent := float64(a.additions+a.deletions) / float64(totalLOC) * 100
+		result.ChurnPercent = churnPercent
 		
 		if churnPercent > float64(churnCautionThreshold) {
 			severity := "Medium"
@@ -180,21 +189,25 @@ func analyzeChurnHealth(repo *git.Repository, since time.Time, pathFilters []str
 				Score:         getSeverityScore(severity),
 				Description:   fmt.Sprintf("High code churn detected: %.1f%%", churnPercent),
 				Recommendation: "Review recent changes for architectural instability or frequent refactoring needs",
-				Details:       fmt.Sprintf("Additions: %d, Deletions: %d, Total LOC: %d", additions, deletions, totalLOC),
+				Details:       fmt.Sprintf("Additions: %d, Deletions: %d, Total LOC: %d", a.additions, a.deletions, totalLOC),
 			})
 		}
 	}
 	
-	return issues
+	return result, issues, nil
 }
 
-// analyzeTestRatioHealth checks test coverage for issues
-func analyzeTestRatioHealth(repo *git.Repository, pathFilters []string) []HealthIssue {
+// testRatioHealthAnalyzer checks test coverage for issues
+type testRatioHealthAnalyzer struct{ snapshotAnalyzer }
+
+func (a *testRatioHealthAnalyzer) Name() string { return "test-ratio" }
+
+func (a *testRatioHealthAnalyzer) Finalize(ctx *AnalysisContext) (interface{}, []HealthIssue, error) {
 	var issues []HealthIssue
 	
-	stats, err := analyzeTestRatio(repo, pathFilters)
+	stats, err := analyzeTestRatio(ctx.Repo, ctx.PathFilters, countModeAll)
 	if err != nil {
-		return issues
+		return nil, nil, err
 	}
 	
 	if stats.TestRatio < testRatioMinimumThreshold {
@@ -214,16 +227,20 @@ func analyzeTestRatioHealth(repo *git.Repository, pathFilters []string) []Health
 		})
 	}
 	
-	return issues
+	return stats, issues, nil
 }
 
-// analyzeBusFactorHealth checks knowledge concentration for issues
-func analyzeBusFactorHealth(repo *git.Repository, since time.Time, pathFilters []string) []HealthIssue {
+// busFactorHealthAnalyzer checks knowledge concentration for issues
+type busFactorHealthAnalyzer struct{ snapshotAnalyzer }
+
+func (a *busFactorHealthAnalyzer) Name() string { return "bus-factor" }
+
+func (a *busFactorHealthAnalyzer) Finalize(ctx *AnalysisContext) (interface{}, []HealthIssue, error) {
 	var issues []HealthIssue
 	
-	analysis, err := analyzeBusFactor(repo, since, pathFilters)
+	analysis, err := analyzeBusFactor(ctx.Repo, ctx.Since, ctx.PathFilters)
 	if err != nil {
-		return issues
+		return nil, nil, err
 	}
 	
 	// Check for high-risk directories
@@ -247,7 +264,7 @@ func analyzeBusFactorHealth(repo *git.Repository, since time.Time, pathFilters [
 		}
 	}
 	
-	return issues
+	return analysis, issues, nil
 }
 
 // getRealProjectAge determines the actual age of the repository from its first commit
@@ -257,7 +274,7 @@ func getRealProjectAge(repo *git.Repository) (time.Duration, error) {
 		return 0, err
 	}
 	
-	cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
 	if err != nil {
 		return 0, err
 	}
@@ -278,20 +295,24 @@ func getRealProjectAge(repo *git.Repository) (time.Duration, error) {
 	return time.Since(firstCommitTime), nil
 }
 
-// analyzeDeadZonesHealth checks for stale code
-func analyzeDeadZonesHealth(repo *git.Repository, since time.Time, pathFilters []string) []HealthIssue {
+// deadZonesHealthAnalyzer checks for stale code
+type deadZonesHealthAnalyzer struct{ snapshotAnalyzer }
+
+func (a *deadZonesHealthAnalyzer) Name() string { return "dead-zones" }
+
+func (a *deadZonesHealthAnalyzer) Finalize(ctx *AnalysisContext) (interface{}, []HealthIssue, error) {
 	var issues []HealthIssue
 	
 	// Skip dead zone analysis for very new projects (less than 3 months old)
 	// This prevents false positives for newly created files
-	projectAge, err := getRealProjectAge(repo)
+	projectAge, err := getRealProjectAge(ctx.Repo)
 	if err == nil && projectAge < newProjectThreshold {
-		return issues // Skip dead zone analysis for new projects
+		return nil, issues, nil // Skip dead zone analysis for new projects
 	}
 	
-	analysis, err := analyzeDeadZones(repo, since, pathFilters)
+	analysis, err := analyzeDeadZones(ctx.Repo, ctx.Since, ctx.PathFilters, countModeAll)
 	if err != nil {
-		return issues
+		return nil, nil, err
 	}
 	
 	if analysis.DeadZoneCount > 0 {
@@ -316,45 +337,52 @@ func analyzeDeadZonesHealth(repo *git.Repository, since time.Time, pathFilters [
 		}
 	}
 	
-	return issues
+	return analysis, issues, nil
 }
 
-// analyzeCommitSizeHealth checks for risky commits
-func analyzeCommitSizeHealth(repo *git.Repository, since time.Time, pathFilters []string) []HealthIssue {
-	var issues []HealthIssue
-	
-	ref, err := repo.Head()
-	if err != nil {
-		return issues
+// CommitSizeHealthResult holds the risky commit counts behind the commit-size health check
+type CommitSizeHealthResult struct {
+	CriticalCommits int
+	HighRiskCommits int
+}
+
+// commitSizeHealthAnalyzer checks for risky commits
+type commitSizeHealthAnalyzer struct {
+	noFlags
+	result CommitSizeHealthResult
+}
+
+func (a *commitSizeHealthAnalyzer) Name() string { return "commit-size" }
+
+func (a *commitSizeHealthAnalyzer) Start(ctx *AnalysisContext) error {
+	a.result = CommitSizeHealthResult{}
+	return nil
+}
+
+func (a *commitSizeHealthAnalyzer) ProcessCommit(ctx *AnalysisContext, c *object.Commit) error {
+	if !includeMergeCommit(c) {
+		return nil
 	}
 	
-	cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	additions, deletions, filesChanged, err := processCommitForSize(c, ctx.PathFilters)
 	if err != nil {
-		return issues
+		return nil
 	}
 	
-	criticalCommits := 0
-	highRiskCommits := 0
+	riskLevel, _ := calculateCommitRisk(additions, deletions, filesChanged)
+	if riskLevel == "Critical" {
+		a.result.CriticalCommits++
+	} else if riskLevel == "High" {
+		a.result.HighRiskCommits++
+	}
 	
-	err = cIter.ForEach(func(c *object.Commit) error {
-		if !since.IsZero() && c.Committer.When.Before(since) {
-			return nil
-		}
-		
-		additions, deletions, filesChanged, err := processCommitForSize(c, pathFilters)
-		if err != nil {
-			return nil
-		}
-		
-		riskLevel, _ := calculateCommitRisk(additions, deletions, filesChanged)
-		if riskLevel == "Critical" {
-			criticalCommits++
-		} else if riskLevel == "High" {
-			highRiskCommits++
-		}
-		
-		return nil
-	})
+	return nil
+}
+
+func (a *commitSizeHealthAnalyzer) Finalize(ctx *AnalysisContext) (interface{}, []HealthIssue, error) {
+	var issues []HealthIssue
+	criticalCommits := a.result.CriticalCommits
+	highRiskCommits := a.result.HighRiskCommits
 	
 	if criticalCommits > 0 || highRiskCommits > 0 {
 		severity := "Medium"
@@ -373,7 +401,74 @@ func analyzeCommitSizeHealth(repo *git.Repository, since time.Time, pathFilters
 		})
 	}
 	
-	return issues
+	result := a.result
+	return &result, issues, nil
+}
+
+// runAnalyzers walks the commits in the time window once, feeding every analyzer,
+// then finalizes each one. An analyzer that fails is reported and left out.
+func runAnalyzers(ctx *AnalysisContext, analyzers []Analyzer) (map[string]interface{}, []HealthIssue, error) {
+	var active []Analyzer
+	for _, a := range analyzers {
+		if err := a.Start(ctx); err != nil {
+			log.Printf("Analyzer %s failed to start: %v", a.Name(), err)
+			continue
+		}
+		active = append(active, a)
+	}
+	
+	ref, err := ctx.Repo.Head()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get HEAD: %v", err)
+	}
+	
+	cIter, err := logCommits(ctx.Repo, &git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	defer cIter.Close()
+	
+	failed := make(map[string]bool)
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if !ctx.Since.IsZero() && c.Committer.When.Before(ctx.Since) {
+			return nil
+		}
+		for _, a := range active {
+			if failed[a.Name()] {
+				continue
+			}
+			if err := a.ProcessCommit(ctx, c); err != nil {
+				log.Printf("Analyzer %s failed on commit %s: %v", a.Name(), c.Hash.String(), err)
+				failed[a.Name()] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error walking commits: %v", err)
+	}
+	
+	results := make(map[string]interface{})
+	var issues []HealthIssue
+	for _, a := range active {
+		if failed[a.Name()] {
+			continue
+		}
+		result, analyzerIssues, err := a.Finalize(ctx)
+		if err != nil {
+			log.Printf("Analyzer %s failed: %v", a.Name(), err)
+			continue
+		}
+		results[a.Name()] = result
+		for _, issue := range analyzerIssues {
+			if issue.Metric == "" {
+				issue.Metric = a.Name()
+			}
+			issues = append(issues, normalizeHealthIssue(issue))
+		}
+	}
+	
+	return results, issues, nil
 }
 
 // generateHealthSummary creates a summary based on the issues found
@@ -478,16 +573,15 @@ func printHealthReport(report *HealthReport) {
 	}
 }
 
-// performHealthCheck runs all health checks and returns a comprehensive report
+// performHealthCheck runs every registered analyzer and returns a comprehensive report
 func performHealthCheck(repo *git.Repository, since time.Time, pathFilters []string) (*HealthReport, error) {
-	var allIssues []HealthIssue
+	ctx := &AnalysisContext{Repo: repo, Since: since, PathFilters: pathFilters}
 	
-	// Run all health checks
-	allIssues = append(allIssues, analyzeChurnHealth(repo, since, pathFilters)...)
-	allIssues = append(allIssues, analyzeTestRatioHealth(repo, pathFilters)...)
-	allIssues = append(allIssues, analyzeBusFactorHealth(repo, since, pathFilters)...)
-	allIssues = append(allIssues, analyzeDeadZonesHealth(repo, since, pathFilters)...)
-	allIssues = append(allIssues, analyzeCommitSizeHealth(repo, since, pathFilters)...)
+	// Run every registered analyzer, built-in and custom
+	results, allIssues, err := runAnalyzers(ctx, RegisteredAnalyzers())
+	if err != nil {
+		return nil, err
+	}
 	
 	// Sort issues by severity score (highest first)
 	sort.Slice(allIssues, func(i, j int) bool {
@@ -528,6 +622,7 @@ func performHealthCheck(repo *git.Repository, since time.Time, pathFilters []str
 		MediumIssues:   mediumCount,
 		LowIssues:      lowCount,
 		Issues:         allIssues,
+		Results:        results,
 	}
 	
 	report.Summary = generateHealthSummary(report)
@@ -552,6 +647,7 @@ The health check covers:
 - Knowledge management (bus factor, ownership)
 - Technical debt (dead zones)
 - Development practices (commit size)
+- Any custom analyzers compiled in with RegisterAnalyzer
 
 Issues are ranked by severity and categorized for easy prioritization.`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -590,4 +686,11 @@ func init() {
 	healthCheckCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
 	healthCheckCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
 	rootCmd.AddCommand(healthCheckCmd)
+	
+	// Built-in analyzers, in report order
+	RegisterAnalyzer(&churnHealthAnalyzer{})
+	RegisterAnalyzer(&testRatioHealthAnalyzer{})
+	RegisterAnalyzer(&busFactorHealthAnalyzer{})
+	RegisterAnalyzer(&deadZonesHealthAnalyzer{})
+	RegisterAnalyzer(&commitSizeHealthAnalyzer{})
 }
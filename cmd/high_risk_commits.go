@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"sort"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/spf13/cobra"
 )
@@ -34,6 +36,7 @@ type HighRiskCommit struct {
 	FilesChanged int
 	Risk         string
 	Reason       string
+	Reverted     bool // a later commit in the window reverted this one
 }
 
 // HighRiskCommitsStats contains analysis statistics
@@ -47,6 +50,9 @@ type HighRiskCommitsStats struct {
 	AverageFiles   float64
 	LargestCommit  HighRiskCommit
 	RiskyCommits   []HighRiskCommit // Only moderate+ risk commits
+	Reverted       int
+	RevertedByRisk map[string]int
+	CommitsByRisk  map[string]int
 }
 
 var highRiskCommitsCmd = &cobra.Command{
@@ -66,7 +72,9 @@ Thresholds:
 - Critical Risk: ≥800 lines changed OR ≥20 files touched
 
 The analysis helps identify commits that may need extra review attention or
-architectural consideration.`,
+architectural consideration. Commits later reverted in the window (see the
+reverts command) are flagged, and the revert rate per risk level shows whether
+the size thresholds actually predict reverts.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repo, err := git.PlainOpen(".")
 		if err != nil {
@@ -121,7 +129,9 @@ func classifyCommitRisk(linesChanged, filesChanged int) (string, string) {
 // calculateHighRiskCommitsStats computes statistics for the commit analysis
 func calculateHighRiskCommitsStats(commits []HighRiskCommit) *HighRiskCommitsStats {
 	stats := &HighRiskCommitsStats{
-		TotalCommits: len(commits),
+		TotalCommits:   len(commits),
+		RevertedByRisk: make(map[string]int),
+		CommitsByRisk:  make(map[string]int),
 	}
 	
 	if len(commits) == 0 {
@@ -149,6 +159,12 @@ func calculateHighRiskCommitsStats(commits []HighRiskCommit) *HighRiskCommitsSta
 			riskyCommits = append(riskyCommits, commit)
 		}
 		
+		stats.CommitsByRisk[commit.Risk]++
+		if commit.Reverted {
+			stats.Reverted++
+			stats.RevertedByRisk[commit.Risk]++
+		}
+		
 		// Accumulate for averages
 		totalLines += commit.LinesChanged
 		totalFiles += commit.FilesChanged
@@ -185,7 +201,7 @@ func analyzeHighRiskCommits(repo *git.Repository, pathFilters []string, lastArg
 	}
 	
 	// Get commits within time window
-	commitIter, err := repo.Log(&git.LogOptions{
+	commitIter, err := logCommits(repo, &git.LogOptions{
 		Since: since,
 	})
 	if err != nil {
@@ -194,6 +210,7 @@ func analyzeHighRiskCommits(repo *git.Repository, pathFilters []string, lastArg
 	defer commitIter.Close()
 	
 	var commits []HighRiskCommit
+	var candidates []*revertCandidate
 	
 	err = commitIter.ForEach(func(commit *object.Commit) error {
 		// Skip commits without author information
@@ -201,8 +218,8 @@ func analyzeHighRiskCommits(repo *git.Repository, pathFilters []string, lastArg
 			return nil
 		}
 		
-		// Skip merge commits for cleaner analysis
-		if commit.NumParents() > 1 {
+		// Merge commits count only when the merge policy includes them
+		if !includeMergeCommit(commit) {
 			return nil
 		}
 		
@@ -216,6 +233,7 @@ func analyzeHighRiskCommits(repo *git.Repository, pathFilters []string, lastArg
 		if filesChanged == 0 {
 			return nil
 		}
+		candidates = append(candidates, &revertCandidate{commit: commit, lines: linesChanged, files: filesChanged})
 		
 		// Classify risk
 		risk, reason := classifyCommitRisk(linesChanged, filesChanged)
@@ -238,10 +256,51 @@ func analyzeHighRiskCommits(repo *git.Repository, pathFilters []string, lastArg
 		return nil, fmt.Errorf("error analyzing commits: %v", err)
 	}
 	
+	// Flag commits that were reverted later in the window
+	fingerprintSameSizeCandidates(candidates, pathFilters)
+	markRevertedCommits(repo, commits, candidates)
+	
 	stats := calculateHighRiskCommitsStats(commits)
 	return stats, nil
 }
 
+// fingerprintSameSizeCandidates fingerprints the diffs of the candidates that
+// could pair up as a commit and its inverse-diff revert. A revert changes as
+// many lines and files as its original, so a commit whose size no other
+// commit in the window shares is never diffed line by line. Reverts named in
+// their message are linked by hash and need no fingerprint.
+func fingerprintSameSizeCandidates(candidates []*revertCandidate, pathFilters []string) {
+	type commitSize struct{ lines, files int }
+	sizes := make(map[commitSize]int)
+	for _, c := range candidates {
+		sizes[commitSize{c.lines, c.files}]++
+	}
+	for _, c := range candidates {
+		if sizes[commitSize{c.lines, c.files}] < 2 || c.commit.NumParents() != 1 {
+			continue
+		}
+		profiled, err := profileRevertCandidate(c.commit, pathFilters)
+		if err != nil {
+			log.Printf("Error fingerprinting commit %s: %v", c.commit.Hash.String(), err)
+			continue
+		}
+		c.signature, c.inverse = profiled.signature, profiled.inverse
+	}
+}
+
+// markRevertedCommits sets Reverted on the commits a later candidate reverted.
+// commits and candidates are in the same (newest first) order.
+func markRevertedCommits(repo *git.Repository, commits []HighRiskCommit, candidates []*revertCandidate) {
+	events, _ := detectReverts(oldestFirstCandidates(candidates), repoCommitResolver(repo))
+	reverted := make(map[plumbing.Hash]bool)
+	for _, e := range events {
+		reverted[e.OriginalHash] = true
+	}
+	for i, c := range candidates {
+		commits[i].Reverted = reverted[c.commit.Hash]
+	}
+}
+
 // calculateCommitChanges computes lines and files changed for a commit
 func calculateCommitChanges(commit *object.Commit, pathFilters []string) (int, int, error) {
 	var linesChanged int
@@ -268,41 +327,17 @@ func calculateCommitChanges(commit *object.Commit, pathFilters []string) (int, i
 		return linesChanged, filesChanged, err
 	}
 	
-	// Regular commit - analyze diff with parent
-	parent, err := commit.Parent(0)
-	if err != nil {
-		return 0, 0, err
-	}
-	
-	parentTree, err := parent.Tree()
+	// Regular commit - analyze diff under the active merge policy
+	changes, err := commitLineChanges(commit, pathFilters)
 	if err != nil {
 		return 0, 0, err
 	}
 	
-	currentTree, err := commit.Tree()
-	if err != nil {
-		return 0, 0, err
+	for _, delta := range changes {
+		linesChanged += delta.Additions + delta.Deletions
 	}
 	
-	// Compute single patch between trees for efficiency (avoids O(N) patch computations)
-	patch, err := parentTree.Patch(currentTree)
-	if err != nil {
-		return 0, 0, err
-	}
-	
-	// Get overall stats from the single patch
-	stats := patch.Stats()
-	fileSet := make(map[string]bool)
-	
-	// Filter stats to only include files matching the path filter
-	for _, fileStat := range stats {
-		if matchesPathFilter(fileStat.Name, pathFilters) {
-			fileSet[fileStat.Name] = true
-			linesChanged += fileStat.Addition + fileStat.Deletion
-		}
-	}
-	
-	filesChanged = len(fileSet)
+	filesChanged = len(changes)
 	return linesChanged, filesChanged, nil
 }
 
@@ -360,6 +395,18 @@ func printHighRiskCommitsStats(stats *HighRiskCommitsStats, limitArg int) {
 	}
 	fmt.Printf("\n")
 	
+	// Do the size-based risk levels predict reverts?
+	if stats.Reverted > 0 {
+		fmt.Printf("Reverted commits: %d (%.1f%%)\n", stats.Reverted, float64(stats.Reverted)/float64(stats.TotalCommits)*100)
+		fmt.Printf("Revert rate by risk level:\n")
+		for _, risk := range []string{"Low", "Moderate", "High", "Critical"} {
+			if total := stats.CommitsByRisk[risk]; total > 0 {
+				fmt.Printf("  %s: %d of %d reverted (%.1f%%)\n", risk, stats.RevertedByRisk[risk], total, float64(stats.RevertedByRisk[risk])/float64(total)*100)
+			}
+		}
+		fmt.Printf("\n")
+	}
+	
 	// Context and research
 	fmt.Printf("Context: Large commits reduce review effectiveness and rollback safety (Kent Beck, Martin Fowler).\n\n")
 	
@@ -383,7 +430,11 @@ func printHighRiskCommitsStats(stats *HighRiskCommitsStats, limitArg int) {
 				break
 			}
 			
-			fmt.Printf("%d. %s — %s\n", i+1, commit.Hash, commit.Risk)
+			reverted := ""
+			if commit.Reverted {
+				reverted = " (reverted)"
+			}
+			fmt.Printf("%d. %s — %s%s\n", i+1, commit.Hash, commit.Risk, reverted)
 			fmt.Printf("   Author: %s\n", commit.Author)
 			fmt.Printf("   Date: %s\n", commit.Date.Format("2006-01-02 15:04"))
 			fmt.Printf("   Changes: %d lines, %d files\n", commit.LinesChanged, commit.FilesChanged)
@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
 )
 
 func TestHighRiskCommitsThresholds(t *testing.T) {
@@ -129,6 +132,25 @@ func TestCalculateHighRiskCommitsStats(t *testing.T) {
 	}
 }
 
+func TestCalculateHighRiskCommitsStatsReverted(t *testing.T) {
+	commits := []HighRiskCommit{
+		{Hash: "abc123", LinesChanged: 50, FilesChanged: 2, Risk: "Low"},
+		{Hash: "def456", LinesChanged: 40, FilesChanged: 1, Risk: "Low", Reverted: true},
+		{Hash: "ghi789", LinesChanged: 500, FilesChanged: 15, Risk: "High", Reverted: true},
+	}
+
+	stats := calculateHighRiskCommitsStats(commits)
+	if stats.Reverted != 2 {
+		t.Errorf("Expected Reverted = 2, got %d", stats.Reverted)
+	}
+	if stats.RevertedByRisk["Low"] != 1 || stats.CommitsByRisk["Low"] != 2 {
+		t.Errorf("Expected 1 of 2 Low commits reverted, got %d of %d", stats.RevertedByRisk["Low"], stats.CommitsByRisk["Low"])
+	}
+	if stats.RevertedByRisk["High"] != 1 || stats.CommitsByRisk["High"] != 1 {
+		t.Errorf("Expected 1 of 1 High commits reverted, got %d of %d", stats.RevertedByRisk["High"], stats.CommitsByRisk["High"])
+	}
+}
+
 func TestHighRiskCommitsEdgeCases(t *testing.T) {
 	// Test empty commits
 	emptyCommits := []HighRiskCommit{}
@@ -158,3 +180,42 @@ func TestHighRiskCommitsEdgeCases(t *testing.T) {
 	}
 }
 
+
+func TestFingerprintSameSizeCandidates(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newTestRepo(t)
+	testRepoCommit(t, repo, map[string]string{"f.txt": "1\n2\n3\n"}, "alice", start)
+	added := testRepoCommit(t, repo, map[string]string{"f.txt": "1\n2\n3\n4\n"}, "alice", start.Add(time.Hour))
+	undone := testRepoCommit(t, repo, map[string]string{"f.txt": "1\n2\n3\n"}, "bob", start.Add(2*time.Hour))
+	other := testRepoCommit(t, repo, map[string]string{"g.txt": "x\ny\n"}, "bob", start.Add(3*time.Hour))
+
+	var candidates []*revertCandidate
+	for _, hash := range []plumbing.Hash{other, undone, added} {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		lines, files, err := calculateCommitChanges(c, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		candidates = append(candidates, &revertCandidate{commit: c, lines: lines, files: files})
+	}
+
+	fingerprintSameSizeCandidates(candidates, nil)
+	if candidates[0].signature != "" {
+		t.Error("Expected the only two-line commit to skip fingerprinting")
+	}
+	if candidates[1].signature == "" || candidates[1].signature != candidates[2].inverse {
+		t.Error("Expected the same-size commits to be fingerprinted as inverses")
+	}
+
+	// The undoing commit doesn't say it's a revert, so only the fingerprints link it
+	stats, err := analyzeHighRiskCommits(repo, nil, "", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Reverted != 1 {
+		t.Errorf("Expected 1 reverted commit, got %d", stats.Reverted)
+	}
+}
@@ -0,0 +1,483 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/spf13/cobra"
+)
+
+// A hotspot is complex code that changes often (Tornhill, "Your Code as a
+// Crime Scene"). Both factors are normalized against the busiest and most
+// complex file in scope, so a file's score runs from 0 to 100 and a score
+// near 100 means it tops both lists. For Go files the complexity factor
+// averages indentation and cyclomatic complexity.
+
+const (
+	// hotspotTrendSamples is how many of a file's most recent changes are
+	// measured for its complexity trend
+	hotspotTrendSamples = 12
+
+	// hotspotTrendThreshold is the relative change in complexity, in percent,
+	// beyond which a trend counts as rising or falling
+	hotspotTrendThreshold = 10.0
+)
+
+const (
+	hotspotTrendRising  = "rising"
+	hotspotTrendFalling = "falling"
+	hotspotTrendStable  = "stable"
+)
+
+// HotspotStats ranks one file by change frequency and complexity at HEAD
+type HotspotStats struct {
+	Path       string
+	Revisions  int // commits touching the file in the window
+	Churn      int // lines added plus deleted in the window
+	Complexity FileComplexity
+	Score      float64
+}
+
+// DirectoryHotspotStats aggregates the hotspots of one directory
+type DirectoryHotspotStats struct {
+	Path       string
+	Files      int
+	Revisions  int
+	LOC        int
+	Complexity int
+	Score      float64 // sum of file scores
+}
+
+// HotspotTrendPoint is a file's complexity as of one commit that changed it
+type HotspotTrendPoint struct {
+	Hash       plumbing.Hash
+	When       time.Time
+	Complexity FileComplexity
+}
+
+// HotspotTrend is the complexity history of one hotspot, oldest first
+type HotspotTrend struct {
+	Path   string
+	Points []HotspotTrendPoint
+}
+
+// hotspotHistory collects change frequency per file while walking commits newest first
+type hotspotHistory struct {
+	revisions map[string]int
+	churn     map[string]int
+	commits   map[string][]*object.Commit // newest first, at most hotspotTrendSamples
+}
+
+func newHotspotHistory() *hotspotHistory {
+	return &hotspotHistory{
+		revisions: make(map[string]int),
+		churn:     make(map[string]int),
+		commits:   make(map[string][]*object.Commit),
+	}
+}
+
+// record adds one commit's per-file churn to the history
+func (h *hotspotHistory) record(c *object.Commit, changes map[string]FileChurnStats) {
+	for path, stats := range changes {
+		h.revisions[path]++
+		h.churn[path] += stats.Additions + stats.Deletions
+		if len(h.commits[path]) < hotspotTrendSamples {
+			h.commits[path] = append(h.commits[path], c)
+		}
+	}
+}
+
+// hotspotComplexity is a file's complexity factor from 0 to 1: indentation
+// complexity against the most indented file in scope, averaged for Go files
+// with cyclomatic complexity against the most complex Go file
+func hotspotComplexity(c FileComplexity, maxIndentation, maxCyclomatic int) float64 {
+	if maxIndentation == 0 {
+		return 0
+	}
+	factor := float64(c.Indentation) / float64(maxIndentation)
+	if c.HasCyclomatic() && maxCyclomatic > 0 {
+		factor = (factor + float64(c.Cyclomatic)/float64(maxCyclomatic)) / 2
+	}
+	return factor
+}
+
+// scoreHotspots ranks files present at HEAD by normalized change frequency
+// times normalized complexity, highest first. Files that did not change in
+// the window are left out.
+func scoreHotspots(revisions, churn map[string]int, complexity map[string]FileComplexity) []HotspotStats {
+	var maxRevisions, maxComplexity, maxCyclomatic int
+	for path, n := range revisions {
+		c, ok := complexity[path]
+		if !ok {
+			continue
+		}
+		if n > maxRevisions {
+			maxRevisions = n
+		}
+		if c.Indentation > maxComplexity {
+			maxComplexity = c.Indentation
+		}
+		if c.Cyclomatic > maxCyclomatic {
+			maxCyclomatic = c.Cyclomatic
+		}
+	}
+
+	var hotspots []HotspotStats
+	for path, n := range revisions {
+		c, ok := complexity[path]
+		if !ok || n == 0 {
+			continue
+		}
+		score := float64(n) / float64(maxRevisions) * hotspotComplexity(c, maxComplexity, maxCyclomatic) * 100
+		hotspots = append(hotspots, HotspotStats{Path: path, Revisions: n, Churn: churn[path], Complexity: c, Score: score})
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+		if hotspots[i].Revisions != hotspots[j].Revisions {
+			return hotspots[i].Revisions > hotspots[j].Revisions
+		}
+		return hotspots[i].Path < hotspots[j].Path
+	})
+	return hotspots
+}
+
+// aggregateDirectoryHotspots sums file hotspots per directory, highest score first
+func aggregateDirectoryHotspots(hotspots []HotspotStats) []DirectoryHotspotStats {
+	dirMap := make(map[string]*DirectoryHotspotStats)
+	for _, h := range hotspots {
+		dir := reportDirectory(h.Path)
+		if dirMap[dir] == nil {
+			dirMap[dir] = &DirectoryHotspotStats{Path: dir}
+		}
+		d := dirMap[dir]
+		d.Files++
+		d.Revisions += h.Revisions
+		d.LOC += h.Complexity.LOC
+		d.Complexity += h.Complexity.Indentation
+		d.Score += h.Score
+	}
+
+	dirs := make([]DirectoryHotspotStats, 0, len(dirMap))
+	for _, d := range dirMap {
+		dirs = append(dirs, *d)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].Score != dirs[j].Score {
+			return dirs[i].Score > dirs[j].Score
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+	return dirs
+}
+
+// headFileComplexities measures every non-binary file in HEAD's tree
+func headFileComplexities(repo *git.Repository, pathFilters []string, countMode string) (map[string]FileComplexity, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD: %v", err)
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD commit: %v", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD tree: %v", err)
+	}
+
+	complexities := make(map[string]FileComplexity)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !matchesPathFilter(f.Name, pathFilters) {
+			return nil
+		}
+		if isBinary, err := f.IsBinary(); err != nil || isBinary {
+			return nil
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return nil
+		}
+		complexities[f.Name] = measureComplexity(f.Name, content, countMode)
+		return nil
+	})
+	return complexities, err
+}
+
+// complexityTrend measures a file at each of the given commits, oldest first.
+// Commits where the file can't be read under this path (before a rename, or
+// where it was deleted) are skipped.
+func complexityTrend(path string, commits []*object.Commit, countMode string) HotspotTrend {
+	trend := HotspotTrend{Path: path}
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		f, err := c.File(path)
+		if err != nil {
+			continue
+		}
+		content, err := f.Contents()
+		if err != nil {
+			continue
+		}
+		trend.Points = append(trend.Points, HotspotTrendPoint{
+			Hash:       c.Hash,
+			When:       c.Committer.When,
+			Complexity: measureComplexity(path, content, countMode),
+		})
+	}
+	return trend
+}
+
+// Change returns the relative change in indentation complexity from the
+// first to the last sample, in percent
+func (t HotspotTrend) Change() float64 {
+	if len(t.Points) < 2 {
+		return 0
+	}
+	first := t.Points[0].Complexity.Indentation
+	last := t.Points[len(t.Points)-1].Complexity.Indentation
+	if first == 0 {
+		if last == 0 {
+			return 0
+		}
+		return 100
+	}
+	return float64(last-first) / float64(first) * 100
+}
+
+// Direction classifies the trend as rising, falling or stable
+func (t HotspotTrend) Direction() string {
+	change := t.Change()
+	switch {
+	case change > hotspotTrendThreshold:
+		return hotspotTrendRising
+	case change < -hotspotTrendThreshold:
+		return hotspotTrendFalling
+	default:
+		return hotspotTrendStable
+	}
+}
+
+// formatComplexitySparkline draws the trend's complexity relative to its peak
+func formatComplexitySparkline(t HotspotTrend) string {
+	peak := 0
+	for _, p := range t.Points {
+		if p.Complexity.Indentation > peak {
+			peak = p.Complexity.Indentation
+		}
+	}
+	spark := make([]byte, 0, len(t.Points))
+	for _, p := range t.Points {
+		level := 0
+		if peak > 0 {
+			level = p.Complexity.Indentation * (len(tuiSparkLevels) - 1) / peak
+			if level == 0 && p.Complexity.Indentation > 0 {
+				level = 1
+			}
+		}
+		spark = append(spark, tuiSparkLevels[level])
+	}
+	return string(spark)
+}
+
+// formatCyclomatic shows cyclomatic complexity, or "-" when it wasn't measured
+func formatCyclomatic(c FileComplexity) string {
+	if !c.HasCyclomatic() {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", c.Cyclomatic, c.MaxFunction)
+}
+
+// printHotspots prints the top file hotspots
+func printHotspots(hotspots []HotspotStats, limit int) {
+	fmt.Printf("\nTop %d hotspots (change frequency × complexity):\n", limit)
+	fmt.Printf("%-50s %7s %7s %7s %10s %10s %6s\n", "File", "Commits", "Churn", "LOC", "Complexity", "Cyclomatic", "Score")
+	for i, h := range hotspots {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-50s %7d %7d %7d %10d %10s %6.1f\n", h.Path, h.Revisions, h.Churn, h.Complexity.LOC, h.Complexity.Indentation, formatCyclomatic(h.Complexity), h.Score)
+	}
+}
+
+// printDirectoryHotspots prints the top directory hotspots
+func printDirectoryHotspots(dirs []DirectoryHotspotStats, limit int) {
+	fmt.Printf("\nTop %d hotspot directories:\n", limit)
+	fmt.Printf("%-50s %6s %7s %7s %10s %6s\n", "Directory", "Files", "Commits", "LOC", "Complexity", "Score")
+	for i, d := range dirs {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-50s %6d %7d %7d %10d %6.1f\n", d.Path, d.Files, d.Revisions, d.LOC, d.Complexity, d.Score)
+	}
+}
+
+// printHotspotTrends prints the complexity trend of the top hotspots
+func printHotspotTrends(trends []HotspotTrend) {
+	fmt.Printf("\nComplexity trend of the top %d hotspots (last %d changes):\n", len(trends), hotspotTrendSamples)
+	fmt.Printf("%-50s %-*s %8s %8s %8s  %s\n", "File", hotspotTrendSamples+2, "Trend", "First", "Now", "Change", "Direction")
+	for _, t := range trends {
+		if len(t.Points) == 0 {
+			continue
+		}
+		first := t.Points[0].Complexity.Indentation
+		last := t.Points[len(t.Points)-1].Complexity.Indentation
+		marker := ""
+		if t.Direction() == hotspotTrendRising {
+			marker = " ⚠️"
+		}
+		fmt.Printf("%-50s [%-*s] %8d %8d %+7.1f%%  %s%s\n", t.Path, hotspotTrendSamples, formatComplexitySparkline(t), first, last, t.Change(), t.Direction(), marker)
+	}
+}
+
+// hotspotsCmd represents the hotspots command
+var hotspotsCmd = &cobra.Command{
+	Use:   "hotspots",
+	Short: "Rank files by change frequency combined with complexity",
+	Long: `Find hotspots: complex code that keeps changing. A high-churn 20-line
+config file is not a hotspot the way a high-churn 3000-line god object is.
+
+Change frequency is the number of commits touching a file in the time window.
+Complexity is measured on the HEAD version of the file:
+- Complexity: indentation complexity, the summed nesting depth of non-blank
+  lines (works for any language; 4 spaces or 1 tab per level)
+- Cyclomatic: for Go files, total/most complex function McCabe complexity
+
+Each factor is normalized against the highest value in scope and multiplied,
+so scores run from 0 to 100. For Go files the complexity factor is the average
+of normalized indentation and normalized cyclomatic complexity (total, against
+the most complex Go file). Directories sum the scores of their files. The
+top hotspots also show their complexity trend over their most recent changes:
+a hotspot whose complexity keeps rising is a refactoring candidate.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse flags
+		lastArg, _ := cmd.Flags().GetString("last")
+		pathFilters, source := getConfigPaths(cmd, "hotspots.paths")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		trendArg, _ := cmd.Flags().GetInt("trend")
+		countMode, err := getCountMode(cmd)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "hotspots", lastArg, pathFilters, source)
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			log.Fatalf("Could not open repository: %v", err)
+		}
+
+		since := time.Time{}
+		if lastArg != "" {
+			cutoff, err := parseDurationArg(lastArg)
+			if err != nil {
+				log.Fatalf("Could not parse --last argument: %v", err)
+			}
+			since = cutoff
+		}
+
+		complexities, err := headFileComplexities(repo, pathFilters, countMode)
+		if err != nil {
+			log.Fatalf("Could not measure file complexity: %v", err)
+		}
+
+		ref, err := repo.Head()
+		if err != nil {
+			log.Fatalf("Could not get HEAD: %v", err)
+		}
+		cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
+		if err != nil {
+			log.Fatalf("Could not get commits: %v", err)
+		}
+
+		history := newHotspotHistory()
+		err = cIter.ForEach(func(c *object.Commit) error {
+			if !since.IsZero() && c.Committer.When.Before(since) {
+				return storer.ErrStop
+			}
+			changes, err := processCommitForFileChurn(c, pathFilters)
+			if err != nil {
+				log.Printf("Error processing commit %s: %v", c.Hash.String(), err)
+				return nil
+			}
+			history.record(c, changes)
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Error walking commits: %v", err)
+		}
+
+		hotspots := scoreHotspots(history.revisions, history.churn, complexities)
+
+		fmt.Printf("Hotspot Analysis\n")
+		fmt.Printf("Time window: %s\n", func() string {
+			if since.IsZero() {
+				return "all time"
+			}
+			return fmt.Sprintf("since %s", since.Format("2006-01-02"))
+		}())
+		if countMode == countModeCode {
+			fmt.Printf("LOC: code lines only (comments and blank lines excluded)\n")
+		}
+		if len(hotspots) == 0 {
+			fmt.Println("\nNo files in scope changed in this window.")
+			return
+		}
+
+		printHotspots(hotspots, limitArg)
+		printDirectoryHotspots(aggregateDirectoryHotspots(hotspots), limitArg)
+
+		if trendArg > 0 {
+			var trends []HotspotTrend
+			for i, h := range hotspots {
+				if i >= trendArg {
+					break
+				}
+				trends = append(trends, complexityTrend(h.Path, history.commits[h.Path], countMode))
+			}
+			printHotspotTrends(trends)
+		}
+
+		fmt.Println()
+		fmt.Println("Complexity is indentation complexity at HEAD; Cyclomatic shows total/most complex function for Go files, and feeds their score.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hotspotsCmd)
+	hotspotsCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
+	hotspotsCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+	hotspotsCmd.Flags().Int("limit", 10, "Number of top files and directories to show")
+	hotspotsCmd.Flags().Int("trend", 5, "Number of top hotspots to show a complexity trend for (0 to disable)")
+	addCountFlag(hotspotsCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import "testing"
+
+func TestScoreHotspots(t *testing.T) {
+	revisions := map[string]int{"big.go": 10, "config.yml": 20, "flat.go": 5, "deleted.go": 30}
+	churn := map[string]int{"big.go": 400, "config.yml": 40, "flat.go": 10}
+	complexity := map[string]FileComplexity{
+		"big.go":     {LOC: 3000, Indentation: 1000},
+		"config.yml": {LOC: 20, Indentation: 10},
+		"flat.go":    {LOC: 500, Indentation: 500},
+		"quiet.go":   {LOC: 800, Indentation: 800},
+	}
+
+	hotspots := scoreHotspots(revisions, churn, complexity)
+	if len(hotspots) != 3 {
+		t.Fatalf("Expected 3 hotspots (deleted and unchanged files excluded), got %+v", hotspots)
+	}
+	if hotspots[0].Path != "big.go" {
+		t.Errorf("Expected big.go to rank first, got %s", hotspots[0].Path)
+	}
+	// 10/20 revisions × 1000/1000 complexity
+	if hotspots[0].Score != 50 {
+		t.Errorf("Expected score 50, got %.1f", hotspots[0].Score)
+	}
+	if hotspots[2].Path != "config.yml" {
+		t.Errorf("Expected the busy but simple config file to rank last, got %s", hotspots[2].Path)
+	}
+}
+
+func TestScoreHotspotsCyclomatic(t *testing.T) {
+	revisions := map[string]int{"branchy.go": 10, "nested.go": 10, "nested.yml": 10}
+	complexity := map[string]FileComplexity{
+		"branchy.go": {Indentation: 500, Cyclomatic: 80},
+		"nested.go":  {Indentation: 500, Cyclomatic: 20},
+		"nested.yml": {Indentation: 500, Cyclomatic: -1},
+	}
+
+	hotspots := scoreHotspots(revisions, nil, complexity)
+	scores := make(map[string]float64)
+	for _, h := range hotspots {
+		scores[h.Path] = h.Score
+	}
+	// Go files average indentation (500/500) with cyclomatic (n/80)
+	if scores["branchy.go"] != 100 || scores["nested.go"] != 62.5 {
+		t.Errorf("Expected Go scores 100 and 62.5, got %v", scores)
+	}
+	// Other languages use indentation alone
+	if scores["nested.yml"] != 100 {
+		t.Errorf("Expected nested.yml to score 100, got %.1f", scores["nested.yml"])
+	}
+}
+
+func TestAggregateDirectoryHotspots(t *testing.T) {
+	hotspots := []HotspotStats{
+		{Path: "cmd/a.go", Revisions: 3, Complexity: FileComplexity{LOC: 100, Indentation: 50}, Score: 40},
+		{Path: "cmd/b.go", Revisions: 2, Complexity: FileComplexity{LOC: 50, Indentation: 20}, Score: 10},
+		{Path: "main.go", Revisions: 1, Complexity: FileComplexity{LOC: 10, Indentation: 2}, Score: 60},
+	}
+
+	dirs := aggregateDirectoryHotspots(hotspots)
+	if len(dirs) != 2 {
+		t.Fatalf("Expected 2 directories, got %d", len(dirs))
+	}
+	if dirs[0].Path != "root/" || dirs[1].Path != "cmd/" {
+		t.Errorf("Expected root/ then cmd/, got %s then %s", dirs[0].Path, dirs[1].Path)
+	}
+	if dirs[1].Files != 2 || dirs[1].Revisions != 5 || dirs[1].Complexity != 70 || dirs[1].Score != 50 {
+		t.Errorf("Unexpected cmd/ aggregate: %+v", dirs[1])
+	}
+}
+
+func TestHotspotTrend(t *testing.T) {
+	point := func(indentation int) HotspotTrendPoint {
+		return HotspotTrendPoint{Complexity: FileComplexity{Indentation: indentation}}
+	}
+
+	tests := []struct {
+		name      string
+		points    []HotspotTrendPoint
+		change    float64
+		direction string
+		spark     string
+	}{
+		{"rising", []HotspotTrendPoint{point(100), point(150), point(200)}, 100, hotspotTrendRising, "-+#"},
+		{"falling", []HotspotTrendPoint{point(200), point(100)}, -50, hotspotTrendFalling, "#-"},
+		{"stable", []HotspotTrendPoint{point(100), point(105)}, 5, hotspotTrendStable, "*#"},
+		{"single sample", []HotspotTrendPoint{point(100)}, 0, hotspotTrendStable, "#"},
+	}
+
+	for _, tt := range tests {
+		trend := HotspotTrend{Path: "a.go", Points: tt.points}
+		if change := trend.Change(); change != tt.change {
+			t.Errorf("%s: Change() = %.1f, want %.1f", tt.name, change, tt.change)
+		}
+		if direction := trend.Direction(); direction != tt.direction {
+			t.Errorf("%s: Direction() = %s, want %s", tt.name, direction, tt.direction)
+		}
+		if spark := formatComplexitySparkline(trend); spark != tt.spark {
+			t.Errorf("%s: sparkline = %q, want %q", tt.name, spark, tt.spark)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Incidents are what time to restore measures. Like deployments they live
+// outside git, so they are read from one of:
+// - tags: tags named <prefix><id>/start and <prefix><id>/end (a tag without
+//   a suffix is a start); annotated tags carry the time they were created
+// - notes: git notes with "incident-start: <id> [time]" and
+//   "incident-end: <id> [time]" lines; the noted commit is where the incident
+//   started or the commit that restored service
+// - file: an incidents export (CSV or JSON) with an id, a start and an end
+//   time, and optionally the sha that restored service
+
+const (
+	incidentSourceTags  = "tags"
+	incidentSourceNotes = "notes"
+	incidentSourceFile  = "file"
+)
+
+const (
+	defaultIncidentPrefix   = "incident/"
+	defaultIncidentNotesRef = "refs/notes/commits"
+)
+
+// Incident is one production incident. A zero End means it is still open.
+type Incident struct {
+	ID          string
+	Start       time.Time
+	End         time.Time
+	RestoreHash plumbing.Hash // commit that restored service, when known
+}
+
+// IncidentConfig says how incidents are found
+type IncidentConfig struct {
+	Source   string
+	Prefix   string
+	NotesRef string
+	File     string
+}
+
+// incidentMarker is one start or end marker read from a source
+type incidentMarker struct {
+	ID   string
+	Time time.Time
+	Hash plumbing.Hash
+	End  bool
+}
+
+// incidentFile field names, matched case-insensitively
+var (
+	incidentIDFields    = []string{"id", "incident", "incident_id", "name"}
+	incidentStartFields = []string{"start", "started_at", "start_time", "opened_at", "detected_at"}
+	incidentEndFields   = []string{"end", "ended_at", "end_time", "resolved_at", "restored_at"}
+)
+
+// addIncidentFlags registers the flags shared by every command that needs incidents
+func addIncidentFlags(cmd *cobra.Command) {
+	cmd.Flags().String("incident-source", "", "How incidents are found: tags, notes or file (default tags, or file with --incidents)")
+	cmd.Flags().String("incident-prefix", "", "Tag prefix marking incidents when --incident-source is tags (default incident/)")
+	cmd.Flags().String("notes-ref", "", "Notes ref holding incident markers when --incident-source is notes (default refs/notes/commits)")
+	cmd.Flags().String("incidents", "", "Incidents file (CSV or JSON with id, start and end) when --incident-source is file")
+}
+
+// getIncidentConfig reads the incident flags, falling back to
+// <configKey>.incident_source, <configKey>.incident_prefix,
+// <configKey>.notes_ref and <configKey>.incidents in the config file, then to
+// the defaults
+func getIncidentConfig(cmd *cobra.Command, configKey string) (IncidentConfig, error) {
+	value := func(flag, key, fallback string) string {
+		if v, _ := cmd.Flags().GetString(flag); v != "" {
+			return v
+		}
+		if v := viper.GetString(configKey + "." + key); v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	cfg := IncidentConfig{
+		Prefix:   value("incident-prefix", "incident_prefix", defaultIncidentPrefix),
+		NotesRef: value("notes-ref", "notes_ref", defaultIncidentNotesRef),
+		File:     value("incidents", "incidents", ""),
+	}
+	defaultSource := incidentSourceTags
+	if cfg.File != "" {
+		defaultSource = incidentSourceFile
+	}
+	cfg.Source = value("incident-source", "incident_source", defaultSource)
+
+	switch cfg.Source {
+	case incidentSourceTags, incidentSourceNotes:
+	case incidentSourceFile:
+		if cfg.File == "" {
+			return cfg, fmt.Errorf("--incidents is required when the incident source is file")
+		}
+	default:
+		return cfg, fmt.Errorf("invalid incident source %q (expected tags, notes or file)", cfg.Source)
+	}
+	return cfg, nil
+}
+
+// Describe summarizes the incident definition for report headers
+func (cfg IncidentConfig) Describe() string {
+	switch cfg.Source {
+	case incidentSourceNotes:
+		return fmt.Sprintf("notes in %s", cfg.NotesRef)
+	case incidentSourceFile:
+		return fmt.Sprintf("incidents file %s", cfg.File)
+	default:
+		return fmt.Sprintf("tags prefixed %s", cfg.Prefix)
+	}
+}
+
+// findIncidents reads incidents from the configured source, keeping those that
+// started at or after since. Incidents are returned oldest first, along with
+// the number of end markers that had no matching start.
+func findIncidents(repo *git.Repository, cfg IncidentConfig, since time.Time) ([]Incident, int, error) {
+	var markers []incidentMarker
+	var err error
+	switch cfg.Source {
+	case incidentSourceNotes:
+		markers, err = noteIncidentMarkers(repo, cfg.NotesRef)
+	case incidentSourceFile:
+		var incidents []Incident
+		incidents, err = loadIncidentFile(repo, cfg.File)
+		markers = incidentFileMarkers(incidents)
+	default:
+		markers, err = tagIncidentMarkers(repo, cfg.Prefix)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	incidents, unmatched := buildIncidents(markers)
+	kept := incidents[:0]
+	for _, inc := range incidents {
+		if since.IsZero() || !inc.Start.Before(since) {
+			kept = append(kept, inc)
+		}
+	}
+	return kept, unmatched, nil
+}
+
+// buildIncidents pairs start and end markers by incident id. An incident
+// starts at its earliest start marker and ends at the first end marker after
+// that; end markers without a start are counted as unmatched.
+func buildIncidents(markers []incidentMarker) ([]Incident, int) {
+	sort.SliceStable(markers, func(i, j int) bool {
+		return markers[i].Time.Before(markers[j].Time)
+	})
+
+	byID := make(map[string]*Incident)
+	var order []string
+	unmatched := 0
+	for _, m := range markers {
+		inc, ok := byID[m.ID]
+		if !m.End {
+			if !ok {
+				byID[m.ID] = &Incident{ID: m.ID, Start: m.Time}
+				order = append(order, m.ID)
+			}
+			continue
+		}
+		if !ok {
+			unmatched++
+			continue
+		}
+		if inc.End.IsZero() {
+			inc.End, inc.RestoreHash = m.Time, m.Hash
+		}
+	}
+
+	incidents := make([]Incident, 0, len(order))
+	for _, id := range order {
+		incidents = append(incidents, *byID[id])
+	}
+	return incidents, unmatched
+}
+
+// parseIncidentTagName splits a tag name into an incident id and whether it
+// marks the end, or returns ok false when the tag isn't an incident marker
+func parseIncidentTagName(name, prefix string) (id string, end bool, ok bool) {
+	if !strings.HasPrefix(name, prefix) {
+		return "", false, false
+	}
+	id = strings.TrimPrefix(name, prefix)
+	if trimmed := strings.TrimSuffix(id, "/end"); trimmed != id {
+		id, end = trimmed, true
+	} else {
+		id = strings.TrimSuffix(id, "/start")
+	}
+	return id, end, id != ""
+}
+
+// tagIncidentMarkers reads incident markers from tags with the incident prefix
+func tagIncidentMarkers(repo *git.Repository, prefix string) ([]incidentMarker, error) {
+	tagRefs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+	defer tagRefs.Close()
+
+	var markers []incidentMarker
+	err = tagRefs.ForEach(func(ref *plumbing.Reference) error {
+		id, end, ok := parseIncidentTagName(ref.Name().Short(), prefix)
+		if !ok {
+			return nil
+		}
+		d, ok := resolveTagDeployment(repo, ref)
+		if !ok {
+			return nil
+		}
+		m := incidentMarker{ID: id, Time: d.Time, End: end}
+		if end {
+			m.Hash = d.Hash
+		}
+		markers = append(markers, m)
+		return nil
+	})
+	return markers, err
+}
+
+// parseIncidentNote reads incident markers from the text of a note attached to
+// commit. Markers without a time take the commit's time.
+func parseIncidentNote(note string, commit *object.Commit) ([]incidentMarker, error) {
+	var markers []incidentMarker
+	scanner := bufio.NewScanner(strings.NewReader(note))
+	for scanner.Scan() {
+		key, rest, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !found {
+			continue
+		}
+		var end bool
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "incident-start":
+		case "incident-end":
+			end = true
+		default:
+			continue
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%s marker without an incident id", key)
+		}
+		m := incidentMarker{ID: fields[0], Time: commit.Committer.When, End: end}
+		if len(fields) > 1 {
+			t, err := parseDeployTime(strings.Join(fields[1:], " "))
+			if err != nil {
+				return nil, fmt.Errorf("incident %s: %v", m.ID, err)
+			}
+			m.Time = t
+		}
+		if end {
+			m.Hash = commit.Hash
+		}
+		markers = append(markers, m)
+	}
+	return markers, scanner.Err()
+}
+
+// noteIncidentMarkers reads incident markers from every note in notesRef. A
+// notes ref is a commit whose tree holds one file per annotated object, named
+// by the object's hash (possibly split into fan-out directories).
+func noteIncidentMarkers(repo *git.Repository, notesRef string) ([]incidentMarker, error) {
+	ref, err := repo.Reference(plumbing.ReferenceName(notesRef), true)
+	if err != nil {
+		return nil, fmt.Errorf("could not find notes ref %s: %v", notesRef, err)
+	}
+	notes, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("could not read notes ref %s: %v", notesRef, err)
+	}
+	tree, err := notes.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("could not read notes ref %s: %v", notesRef, err)
+	}
+
+	var markers []incidentMarker
+	err = tree.Files().ForEach(func(f *object.File) error {
+		name := strings.ReplaceAll(f.Name, "/", "")
+		if !plumbing.IsHash(name) {
+			return nil
+		}
+		commit, err := repo.CommitObject(plumbing.NewHash(name))
+		if err != nil {
+			// Notes on objects other than commits can't be incident markers
+			return nil
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		found, err := parseIncidentNote(content, commit)
+		if err != nil {
+			return fmt.Errorf("note on %s: %v", commit.Hash.String()[:7], err)
+		}
+		markers = append(markers, found...)
+		return nil
+	})
+	return markers, err
+}
+
+// incidentRecord is one row of an incidents file before shas are resolved
+type incidentRecord struct {
+	ID    string
+	Start string
+	End   string
+	SHA   string
+}
+
+// parseIncidentRecord checks and converts one incidents file row
+func parseIncidentRecord(r incidentRecord) (Incident, string, error) {
+	if r.ID == "" || r.Start == "" {
+		return Incident{}, "", fmt.Errorf("expected id and start fields")
+	}
+	inc := Incident{ID: r.ID}
+	var err error
+	if inc.Start, err = parseDeployTime(r.Start); err != nil {
+		return inc, "", err
+	}
+	if r.End != "" {
+		if inc.End, err = parseDeployTime(r.End); err != nil {
+			return inc, "", err
+		}
+		if inc.End.Before(inc.Start) {
+			return inc, "", fmt.Errorf("incident %s ends before it starts", r.ID)
+		}
+	}
+	return inc, r.SHA, nil
+}
+
+// parseIncidentsCSV reads incident records from a CSV file with a header row
+func parseIncidentsCSV(r io.Reader) ([]incidentRecord, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	idCol, startCol := fieldIndex(header, incidentIDFields), fieldIndex(header, incidentStartFields)
+	endCol, shaCol := fieldIndex(header, incidentEndFields), fieldIndex(header, deployLogSHAFields)
+	if idCol < 0 || startCol < 0 {
+		return nil, fmt.Errorf("header must name an id and a start column")
+	}
+	column := func(row []string, i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var records []incidentRecord
+	for _, row := range rows[1:] {
+		records = append(records, incidentRecord{
+			ID:    column(row, idCol),
+			Start: column(row, startCol),
+			End:   column(row, endCol),
+			SHA:   column(row, shaCol),
+		})
+	}
+	return records, nil
+}
+
+// parseIncidentsJSON reads incident records from a JSON array of objects
+func parseIncidentsJSON(data []byte) ([]incidentRecord, error) {
+	objects, err := decodeJSONRecords(data)
+	if err != nil {
+		return nil, err
+	}
+	var records []incidentRecord
+	for _, o := range objects {
+		records = append(records, incidentRecord{
+			ID:    recordField(o, incidentIDFields),
+			Start: recordField(o, incidentStartFields),
+			End:   recordField(o, incidentEndFields),
+			SHA:   recordField(o, deployLogSHAFields),
+		})
+	}
+	return records, nil
+}
+
+// loadIncidentFile reads an incidents file, choosing the format by file
+// extension, and resolves restoring shas against the repository
+func loadIncidentFile(repo *git.Repository, path string) ([]Incident, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read incidents file: %v", err)
+	}
+
+	var records []incidentRecord
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		records, err = parseIncidentsJSON(data)
+	default:
+		records, err = parseIncidentsCSV(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse incidents file %s: %v", path, err)
+	}
+
+	var incidents []Incident
+	for n, r := range records {
+		inc, sha, err := parseIncidentRecord(r)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse incidents file %s: record %d: %v", path, n+1, err)
+		}
+		if sha != "" {
+			if hash, err := repo.ResolveRevision(plumbing.Revision(sha)); err == nil {
+				inc.RestoreHash = *hash
+			}
+		}
+		incidents = append(incidents, inc)
+	}
+	return incidents, nil
+}
+
+// incidentFileMarkers turns file incidents back into markers so every source
+// is paired the same way
+func incidentFileMarkers(incidents []Incident) []incidentMarker {
+	var markers []incidentMarker
+	for _, inc := range incidents {
+		markers = append(markers, incidentMarker{ID: inc.ID, Time: inc.Start})
+		if !inc.End.IsZero() {
+			markers = append(markers, incidentMarker{ID: inc.ID, Time: inc.End, Hash: inc.RestoreHash, End: true})
+		}
+	}
+	return markers
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestParseIncidentTagName(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		end  bool
+		ok   bool
+	}{
+		{"incident/INC-1/start", "INC-1", false, true},
+		{"incident/INC-1/end", "INC-1", true, true},
+		{"incident/INC-2", "INC-2", false, true},
+		{"incident/", "", false, false},
+		{"v1.2.3", "", false, false},
+	}
+
+	for _, tt := range tests {
+		id, end, ok := parseIncidentTagName(tt.name, "incident/")
+		if id != tt.id || end != tt.end || ok != tt.ok {
+			t.Errorf("parseIncidentTagName(%q) = %q, %v, %v, want %q, %v, %v", tt.name, id, end, ok, tt.id, tt.end, tt.ok)
+		}
+	}
+}
+
+func TestParseIncidentNote(t *testing.T) {
+	when := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	commit := &object.Commit{Hash: plumbing.NewHash("aaaa"), Committer: object.Signature{When: when}}
+
+	markers, err := parseIncidentNote("Deployed fine\nincident-start: INC-1 2025-01-01T10:00:00Z\nIncident-End: INC-1\n", commit)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(markers) != 2 {
+		t.Fatalf("Expected 2 markers, got %d", len(markers))
+	}
+	if markers[0].End || markers[0].Time.Hour() != 10 || !markers[0].Hash.IsZero() {
+		t.Errorf("Expected a start marker at 10:00 without a commit, got %+v", markers[0])
+	}
+	// End markers default to the commit's time and name it as the restoring commit
+	if !markers[1].End || !markers[1].Time.Equal(when) || markers[1].Hash != commit.Hash {
+		t.Errorf("Expected an end marker at the commit, got %+v", markers[1])
+	}
+
+	if _, err := parseIncidentNote("incident-start:", commit); err == nil {
+		t.Error("Expected an error for a marker without an id")
+	}
+}
+
+func TestBuildIncidents(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fix := plumbing.NewHash("bbbb")
+	markers := []incidentMarker{
+		{ID: "INC-1", Time: start.Add(3 * time.Hour), Hash: fix, End: true},
+		{ID: "INC-1", Time: start},
+		{ID: "INC-1", Time: start.Add(5 * time.Hour), End: true},
+		{ID: "INC-2", Time: start.Add(time.Hour)},
+		{ID: "INC-3", Time: start.Add(2 * time.Hour), End: true},
+	}
+
+	incidents, unmatched := buildIncidents(markers)
+	if len(incidents) != 2 || unmatched != 1 {
+		t.Fatalf("Expected 2 incidents and 1 unmatched end, got %d and %d", len(incidents), unmatched)
+	}
+	// The first end marker after the start wins
+	if incidents[0].ID != "INC-1" || incidents[0].End.Sub(incidents[0].Start) != 3*time.Hour || incidents[0].RestoreHash != fix {
+		t.Errorf("Unexpected first incident %+v", incidents[0])
+	}
+	if incidents[1].ID != "INC-2" || !incidents[1].End.IsZero() {
+		t.Errorf("Expected INC-2 to be open, got %+v", incidents[1])
+	}
+}
+
+func TestParseIncidentsFiles(t *testing.T) {
+	csvRecords, err := parseIncidentsCSV(strings.NewReader("id,started_at,resolved_at,sha\nINC-1,2025-01-01T10:00:00Z,2025-01-01T11:00:00Z,abc123\nINC-2,2025-01-02,,\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	jsonRecords, err := parseIncidentsJSON([]byte(`[{"id": "INC-1", "start": "2025-01-01T10:00:00Z", "end": "2025-01-01T11:00:00Z", "commit": "abc123"}, {"incident": "INC-2", "opened_at": "2025-01-02", "resolved_at": null}]`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for name, records := range map[string][]incidentRecord{"csv": csvRecords, "json": jsonRecords} {
+		if len(records) != 2 {
+			t.Fatalf("%s: expected 2 records, got %d", name, len(records))
+		}
+		inc, sha, err := parseIncidentRecord(records[0])
+		if err != nil || sha != "abc123" || inc.End.Sub(inc.Start) != time.Hour {
+			t.Errorf("%s: unexpected first incident %+v, %q (%v)", name, inc, sha, err)
+		}
+		if inc, _, err := parseIncidentRecord(records[1]); err != nil || !inc.End.IsZero() {
+			t.Errorf("%s: expected an open second incident, got %+v (%v)", name, inc, err)
+		}
+	}
+
+	if _, err := parseIncidentsCSV(strings.NewReader("sha,timestamp\nabc,2025-01-01\n")); err == nil {
+		t.Error("Expected an error for a header without id and start columns")
+	}
+	if _, _, err := parseIncidentRecord(incidentRecord{ID: "INC-1", Start: "2025-01-02", End: "2025-01-01"}); err == nil {
+		t.Error("Expected an error for an incident ending before it starts")
+	}
+}
@@ -0,0 +1,325 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// A pull request's lead time splits into three stages:
+// - coding: first commit on the branch to last commit
+// - review: last commit to the merge landing on the default branch
+// - release: merge to the first tag containing it
+// Squash merges carry one commit, so their coding stage is empty and review
+// runs from the squashed commit's author time to when it was committed.
+
+const (
+	stageCoding  = "coding"
+	stageReview  = "review"
+	stageRelease = "release"
+)
+
+// Pull request numbers in merge commit messages
+var (
+	// GitHub merge commits: "Merge pull request #1234 from owner/branch"
+	githubMergePattern = regexp.MustCompile(`(?i)^Merge pull request #(\d+)\b`)
+	// Bitbucket merge commits: "Merged in branch (pull request #12)"
+	bitbucketMergePattern = regexp.MustCompile(`(?i)\(pull request #(\d+)\)`)
+	// GitLab merge commits end with "See merge request group/project!1234"
+	gitlabMergePattern = regexp.MustCompile(`(?m)^See merge request \S*!(\d+)\s*$`)
+	// Squash and rebase merges keep the number in the title: "Fix login (#1234)"
+	squashTitlePattern = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+)
+
+// PullRequestLeadTime is one pull request's lead time broken into stages
+type PullRequestLeadTime struct {
+	Number       int
+	Title        string
+	Hash         string // commit that landed it on the default branch
+	Commits      int
+	FirstCommit  time.Time
+	LastCommit   time.Time
+	Merged       time.Time
+	Released     time.Time // zero until a tag contains it
+	CodingHours  float64
+	ReviewHours  float64
+	ReleaseHours float64 // only meaningful once released
+}
+
+// LeadTimeHours returns the time from the first commit to the merge
+func (p PullRequestLeadTime) LeadTimeHours() float64 {
+	return p.Merged.Sub(p.FirstCommit).Hours()
+}
+
+// IsReleased reports whether a tag contains the pull request
+func (p PullRequestLeadTime) IsReleased() bool {
+	return !p.Released.IsZero()
+}
+
+// LeadTimeStageStats summarizes where pull requests spend their time
+type LeadTimeStageStats struct {
+	PullRequests       int
+	Released           int
+	MedianCodingHours  float64
+	MedianReviewHours  float64
+	MedianReleaseHours float64
+	Bottleneck         string // stage with the longest median
+	ReleasedBy         string // what ends the release stage: "tag" (default) or "deploy"
+}
+
+// parsePRNumber finds the pull request number in a merge or squash commit message
+func parsePRNumber(message string) (int, bool) {
+	message = strings.TrimSpace(message)
+	subject := strings.SplitN(message, "\n", 2)[0]
+
+	var match []string
+	for _, candidate := range [][]string{
+		githubMergePattern.FindStringSubmatch(subject),
+		bitbucketMergePattern.FindStringSubmatch(subject),
+		gitlabMergePattern.FindStringSubmatch(message),
+		squashTitlePattern.FindStringSubmatch(subject),
+	} {
+		if candidate != nil {
+			match = candidate
+			break
+		}
+	}
+	if match == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(match[1])
+	return number, err == nil
+}
+
+// pullRequestTitle returns a pull request's title: GitHub merge commits put it
+// on the first body line, everything else in the subject
+func pullRequestTitle(message string) string {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	if githubMergePattern.MatchString(lines[0]) {
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				return line
+			}
+		}
+	}
+	return strings.TrimSpace(squashTitlePattern.ReplaceAllString(lines[0], ""))
+}
+
+// chainPullRequestNumbers maps each position on the index's first-parent
+// chain to the pull request that landed there
+func chainPullRequestNumbers(repo *git.Repository, index *commitIndex) (map[int]int, error) {
+	numbers := make(map[int]int)
+	for pos, hash := range index.chain {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+		if number, ok := parsePRNumber(c.Message); ok {
+			numbers[pos] = number
+		}
+	}
+	return numbers, nil
+}
+
+// pullRequestStages measures one pull request from its landing commit and
+// the branch commits it brought in
+func pullRequestStages(number int, landing *object.Commit, branch []*object.Commit) PullRequestLeadTime {
+	pr := PullRequestLeadTime{
+		Number:  number,
+		Title:   pullRequestTitle(landing.Message),
+		Hash:    landing.Hash.String()[:8],
+		Commits: len(branch),
+		Merged:  landing.Committer.When,
+	}
+	if len(branch) == 0 {
+		// Squash or rebase merge: the landing commit is the whole change
+		pr.Commits = 1
+		pr.FirstCommit, pr.LastCommit = landing.Author.When, landing.Author.When
+	}
+	for _, c := range branch {
+		if pr.FirstCommit.IsZero() || c.Author.When.Before(pr.FirstCommit) {
+			pr.FirstCommit = c.Author.When
+		}
+		if c.Author.When.After(pr.LastCommit) {
+			pr.LastCommit = c.Author.When
+		}
+	}
+	pr.CodingHours = pr.LastCommit.Sub(pr.FirstCommit).Hours()
+	pr.ReviewHours = pr.Merged.Sub(pr.LastCommit).Hours()
+	return pr
+}
+
+// pullRequestLeadTimes measures every pull request merged at or after cutoff
+// that touches pathFilters, newest first
+func pullRequestLeadTimes(repo *git.Repository, index *commitIndex, numbers map[int]int, cutoff time.Time, pathFilters []string) ([]PullRequestLeadTime, error) {
+	var prs []PullRequestLeadTime
+	for pos := len(index.chain) - 1; pos >= 0; pos-- {
+		number, ok := numbers[pos]
+		if !ok {
+			continue
+		}
+		landing, err := repo.CommitObject(index.chain[pos])
+		if err != nil {
+			return nil, err
+		}
+		if !cutoff.IsZero() && landing.Committer.When.Before(cutoff) {
+			continue
+		}
+
+		var branch []*object.Commit
+		if landing.NumParents() > 1 {
+			for _, hash := range index.landedCommits(pos) {
+				c, err := repo.CommitObject(hash)
+				if err != nil {
+					return nil, err
+				}
+				if c.NumParents() <= 1 {
+					branch = append(branch, c)
+				}
+			}
+		}
+
+		if len(pathFilters) > 0 && !pullRequestAffectsPath(landing, branch, pathFilters) {
+			continue
+		}
+
+		pr := pullRequestStages(number, landing, branch)
+		if released, found, err := index.firstTagTime(landing.Hash); err != nil {
+			return nil, err
+		} else if found {
+			pr.Released = released
+			pr.ReleaseHours = released.Sub(pr.Merged).Hours()
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// pullRequestAffectsPath reports whether any of a pull request's commits touch pathFilters
+func pullRequestAffectsPath(landing *object.Commit, branch []*object.Commit, pathFilters []string) bool {
+	commits := branch
+	if len(commits) == 0 {
+		commits = []*object.Commit{landing}
+	}
+	for _, c := range commits {
+		if affects, err := commitAffectsPath(c, pathFilters); err == nil && affects {
+			return true
+		}
+	}
+	return false
+}
+
+// calculateLeadTimeStageStats computes median stage durations across pull requests
+func calculateLeadTimeStageStats(prs []PullRequestLeadTime) LeadTimeStageStats {
+	stats := LeadTimeStageStats{PullRequests: len(prs)}
+	if len(prs) == 0 {
+		return stats
+	}
+
+	var coding, review, release []float64
+	for _, pr := range prs {
+		coding = append(coding, pr.CodingHours)
+		review = append(review, pr.ReviewHours)
+		if pr.IsReleased() {
+			stats.Released++
+			release = append(release, pr.ReleaseHours)
+		}
+	}
+	stats.MedianCodingHours = calculatePercentile(coding, 50)
+	stats.MedianReviewHours = calculatePercentile(review, 50)
+	stats.MedianReleaseHours = calculatePercentile(release, 50)
+
+	stats.Bottleneck = stageCoding
+	longest := stats.MedianCodingHours
+	if stats.MedianReviewHours > longest {
+		stats.Bottleneck, longest = stageReview, stats.MedianReviewHours
+	}
+	if stats.Released > 0 && stats.MedianReleaseHours > longest {
+		stats.Bottleneck = stageRelease
+	}
+	return stats
+}
+
+// slowestPullRequests returns pull requests by lead time, slowest first
+func slowestPullRequests(prs []PullRequestLeadTime) []PullRequestLeadTime {
+	sorted := append([]PullRequestLeadTime{}, prs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LeadTimeHours() > sorted[j].LeadTimeHours()
+	})
+	return sorted
+}
+
+// printLeadTimeStages displays the stage breakdown and, when grouping by pull
+// request, the slowest pull requests
+func printLeadTimeStages(stats *ChangeLeadTimeStats, limit int, byPR bool) {
+	stages := stats.Stages
+	releaseLabel, releaseNoun := "Release (merge → first tag):", "tag"
+	if stages.ReleasedBy == "deploy" {
+		releaseLabel, releaseNoun = "Release (merge → first deploy):", "deployment"
+	}
+	if stages.PullRequests == 0 {
+		if byPR {
+			fmt.Println("No pull requests found: merge commits and squash titles carry no PR numbers.")
+			fmt.Println()
+		}
+		return
+	}
+
+	fmt.Printf("Lead Time Stages (%d pull requests, medians):\n", stages.PullRequests)
+	fmt.Printf("  Coding (first → last commit):   %s\n", formatElapsed(hoursDuration(stages.MedianCodingHours)))
+	fmt.Printf("  Review (last commit → merge):   %s\n", formatElapsed(hoursDuration(stages.MedianReviewHours)))
+	if stages.Released > 0 {
+		fmt.Printf("  %-32s%s (%d of %d released)\n", releaseLabel, formatElapsed(hoursDuration(stages.MedianReleaseHours)), stages.Released, stages.PullRequests)
+	} else {
+		fmt.Printf("  %-32s- (no %s contains these pull requests yet)\n", releaseLabel, releaseNoun)
+	}
+	fmt.Printf("  Longest stage: %s\n", stages.Bottleneck)
+	fmt.Println()
+
+	if !byPR {
+		return
+	}
+	prs := slowestPullRequests(stats.PullRequests)
+	displayLimit := min(len(prs), limit)
+	fmt.Printf("Slowest %d Pull Requests:\n", displayLimit)
+	for _, pr := range prs[:displayLimit] {
+		release := "unreleased"
+		if pr.IsReleased() {
+			release = formatElapsed(hoursDuration(pr.ReleaseHours))
+		}
+		commits := fmt.Sprintf("%d commits", pr.Commits)
+		if pr.Commits == 1 {
+			commits = "1 commit"
+		}
+		fmt.Printf("  #%d %s (%s)\n", pr.Number, pr.Title, commits)
+		fmt.Printf("    Lead time %s: coding %s, review %s, release %s\n", formatElapsed(hoursDuration(pr.LeadTimeHours())), formatElapsed(hoursDuration(pr.CodingHours)), formatElapsed(hoursDuration(pr.ReviewHours)), release)
+	}
+	fmt.Println()
+}
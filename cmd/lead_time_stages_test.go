@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestParsePRNumber(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected int
+		ok       bool
+	}{
+		{"Merge pull request #1234 from owner/feature\n\nAdd login", 1234, true},
+		{"Fix crash on startup (#42)", 42, true},
+		{"Fix crash on startup (#42)\n\n* wip\n* more", 42, true},
+		{"Merge branch 'feature' into 'main'\n\nAdd login\n\nSee merge request group/project!77", 77, true},
+		{"Merged in feature/login (pull request #9)\n\nAdd login", 9, true},
+		{"Refs #12 in the body only\n\nSee #13", 0, false},
+		{"Merge branch 'main' into feature", 0, false},
+	}
+
+	for _, tt := range tests {
+		number, ok := parsePRNumber(tt.message)
+		if number != tt.expected || ok != tt.ok {
+			t.Errorf("parsePRNumber(%q) = %d, %v, want %d, %v", tt.message, number, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestPullRequestTitle(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+	}{
+		{"Merge pull request #12 from bob/feature\n\nAdd feature", "Add feature"},
+		{"Merge pull request #12 from bob/feature", "Merge pull request #12 from bob/feature"},
+		{"Fix thing (#13)\n\nDetails", "Fix thing"},
+	}
+
+	for _, tt := range tests {
+		if result := pullRequestTitle(tt.message); result != tt.expected {
+			t.Errorf("pullRequestTitle(%q) = %q, want %q", tt.message, result, tt.expected)
+		}
+	}
+}
+
+func TestPullRequestStages(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) object.Signature {
+		return object.Signature{When: start.Add(time.Duration(hours) * time.Hour)}
+	}
+
+	merge := &object.Commit{Hash: plumbing.NewHash("aa"), Message: "Merge pull request #7 from bob/x\n\nAdd x", Author: at(30), Committer: at(30)}
+	branch := []*object.Commit{
+		{Author: at(10)},
+		{Author: at(0)},
+		{Author: at(4)},
+	}
+	pr := pullRequestStages(7, merge, branch)
+	if pr.Commits != 3 || pr.CodingHours != 10 || pr.ReviewHours != 20 || pr.LeadTimeHours() != 30 {
+		t.Errorf("Expected 3 commits, 10h coding, 20h review and 30h lead time, got %+v", pr)
+	}
+
+	// A squash merge has no coding stage; review runs from authoring to commit
+	squash := &object.Commit{Hash: plumbing.NewHash("bb"), Message: "Fix y (#8)", Author: at(5), Committer: at(9)}
+	pr = pullRequestStages(8, squash, nil)
+	if pr.Commits != 1 || pr.CodingHours != 0 || pr.ReviewHours != 4 || pr.Title != "Fix y" {
+		t.Errorf("Expected one commit with 4h review, got %+v", pr)
+	}
+}
+
+func TestCalculateLeadTimeStageStats(t *testing.T) {
+	released := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	prs := []PullRequestLeadTime{
+		{CodingHours: 2, ReviewHours: 30, ReleaseHours: 5, Released: released},
+		{CodingHours: 4, ReviewHours: 10},
+		{CodingHours: 6, ReviewHours: 20, ReleaseHours: 15, Released: released},
+	}
+
+	stats := calculateLeadTimeStageStats(prs)
+	if stats.PullRequests != 3 || stats.Released != 2 {
+		t.Errorf("Expected 3 pull requests with 2 released, got %d and %d", stats.PullRequests, stats.Released)
+	}
+	if stats.MedianCodingHours != 4 || stats.MedianReviewHours != 20 || stats.MedianReleaseHours != 10 {
+		t.Errorf("Expected medians 4h, 20h and 10h, got %.1f, %.1f and %.1f", stats.MedianCodingHours, stats.MedianReviewHours, stats.MedianReleaseHours)
+	}
+	if stats.Bottleneck != stageReview {
+		t.Errorf("Expected review as the longest stage, got %s", stats.Bottleneck)
+	}
+
+	if empty := calculateLeadTimeStageStats(nil); empty.PullRequests != 0 || empty.Bottleneck != "" {
+		t.Errorf("Expected empty stats, got %+v", empty)
+	}
+}
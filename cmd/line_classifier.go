@@ -0,0 +1,279 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Line counting modes selectable with --count
+const (
+	countModeCode = "code" // only lines containing code
+	countModeAll  = "all"  // every line, including comments and blanks
+)
+
+// LineCounts holds a file's lines split into code, comment and blank lines.
+// A line holding both code and a trailing comment counts as code.
+type LineCounts struct {
+	Code    int
+	Comment int
+	Blank   int
+}
+
+// Total returns the number of lines of any kind
+func (lc LineCounts) Total() int {
+	return lc.Code + lc.Comment + lc.Blank
+}
+
+// blockComment describes a multi-line comment delimiter pair
+type blockComment struct {
+	start     string
+	end       string
+	lineStart bool // start delimiter only counts at the beginning of a line (Ruby =begin)
+}
+
+// commentSyntax describes how comments and strings are written in a language
+type commentSyntax struct {
+	line   []string
+	blocks []blockComment
+	quotes string // characters that open and close string literals
+}
+
+var (
+	cStyleSyntax = commentSyntax{
+		line:   []string{"//"},
+		blocks: []blockComment{{start: "/*", end: "*/"}},
+		quotes: `"'`,
+	}
+	goSyntax = commentSyntax{
+		line:   []string{"//"},
+		blocks: []blockComment{{start: "/*", end: "*/"}},
+		quotes: "\"'`",
+	}
+	jsSyntax = commentSyntax{
+		line:   []string{"//"},
+		blocks: []blockComment{{start: "/*", end: "*/"}},
+		quotes: "\"'`",
+	}
+	// Rust lifetimes ('a) look like unterminated char literals, so only " opens a string
+	rustSyntax = commentSyntax{
+		line:   []string{"//"},
+		blocks: []blockComment{{start: "/*", end: "*/"}},
+		quotes: `"`,
+	}
+	pythonSyntax = commentSyntax{
+		line: []string{"#"},
+		// Docstrings are string literals, but they document rather than execute
+		blocks: []blockComment{{start: `"""`, end: `"""`}, {start: "'''", end: "'''"}},
+		quotes: `"'`,
+	}
+	rubySyntax = commentSyntax{
+		line:   []string{"#"},
+		blocks: []blockComment{{start: "=begin", end: "=end", lineStart: true}},
+		quotes: `"'`,
+	}
+	phpSyntax = commentSyntax{
+		line:   []string{"//", "#"},
+		blocks: []blockComment{{start: "/*", end: "*/"}},
+		quotes: `"'`,
+	}
+)
+
+// languageSyntaxes maps the source extensions recognized by classifyFileType to their comment syntax
+var languageSyntaxes = map[string]commentSyntax{
+	".go":    goSyntax,
+	".js":    jsSyntax,
+	".jsx":   jsSyntax,
+	".ts":    jsSyntax,
+	".tsx":   jsSyntax,
+	".py":    pythonSyntax,
+	".rb":    rubySyntax,
+	".java":  cStyleSyntax,
+	".cs":    cStyleSyntax,
+	".c":     cStyleSyntax,
+	".h":     cStyleSyntax,
+	".cpp":   cStyleSyntax,
+	".rs":    rustSyntax,
+	".kt":    cStyleSyntax,
+	".swift": cStyleSyntax,
+	".php":   phpSyntax,
+	".scala": cStyleSyntax,
+	".dart":  cStyleSyntax,
+}
+
+// validateCountMode checks a --count value
+func validateCountMode(mode string) error {
+	switch mode {
+	case countModeCode, countModeAll:
+		return nil
+	default:
+		return fmt.Errorf("invalid --count value %q (expected code or all)", mode)
+	}
+}
+
+// addCountFlag registers the --count flag shared by the LOC-based commands
+func addCountFlag(cmd *cobra.Command) {
+	cmd.Flags().String("count", countModeAll, "Which lines count toward LOC: code (excludes comments and blank lines) or all")
+}
+
+// getCountMode reads and validates the --count flag
+func getCountMode(cmd *cobra.Command) (string, error) {
+	mode, _ := cmd.Flags().GetString("count")
+	if mode == "" {
+		mode = countModeAll
+	}
+	if err := validateCountMode(mode); err != nil {
+		return "", err
+	}
+	return mode, nil
+}
+
+// classifyLines splits a file's content into code, comment and blank lines.
+// Files in languages without known comment syntax only separate blank lines.
+func classifyLines(filePath, content string) LineCounts {
+	var counts LineCounts
+	if content == "" {
+		return counts
+	}
+
+	syntax, known := languageSyntaxes[strings.ToLower(filepath.Ext(filePath))]
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	var openBlock *blockComment
+
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if isEmptyLine(line) {
+			counts.Blank++
+			continue
+		}
+		if !known {
+			counts.Code++
+			continue
+		}
+
+		hasCode, hasComment := scanLine(line, syntax, &openBlock)
+		switch {
+		case hasCode:
+			counts.Code++
+		case hasComment:
+			counts.Comment++
+		default:
+			counts.Blank++
+		}
+	}
+
+	return counts
+}
+
+// scanLine reports whether a line holds code and/or comment text. openBlock
+// carries an unterminated block comment from one line to the next.
+func scanLine(line string, syntax commentSyntax, openBlock **blockComment) (bool, bool) {
+	hasCode, hasComment := false, false
+	var quote byte
+
+	for i := 0; i < len(line); {
+		if *openBlock != nil {
+			hasComment = true
+			if strings.HasPrefix(line[i:], (*openBlock).end) {
+				i += len((*openBlock).end)
+				*openBlock = nil
+				continue
+			}
+			i++
+			continue
+		}
+
+		ch := line[i]
+		if quote != 0 {
+			if ch == '\\' {
+				i += 2
+				continue
+			}
+			if ch == quote {
+				quote = 0
+			}
+			i++
+			continue
+		}
+
+		if ch == ' ' || ch == '\t' {
+			i++
+			continue
+		}
+
+		rest := line[i:]
+		if hasAnyPrefix(rest, syntax.line) {
+			hasComment = true
+			break
+		}
+
+		if block := matchBlockStart(rest, syntax.blocks, strings.TrimSpace(line[:i]) == ""); block != nil {
+			hasComment = true
+			*openBlock = block
+			i += len(block.start)
+			continue
+		}
+
+		if strings.IndexByte(syntax.quotes, ch) >= 0 {
+			quote = ch
+		}
+		hasCode = true
+		i++
+	}
+
+	return hasCode, hasComment
+}
+
+// matchBlockStart returns the block comment starting at s, if any
+func matchBlockStart(s string, blocks []blockComment, atLineStart bool) *blockComment {
+	for i := range blocks {
+		if blocks[i].lineStart && !atLineStart {
+			continue
+		}
+		if strings.HasPrefix(s, blocks[i].start) {
+			return &blocks[i]
+		}
+	}
+	return nil
+}
+
+// hasAnyPrefix reports whether s starts with any of the prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// countFileLines counts a file's lines according to the --count mode
+func countFileLines(filePath, content, mode string) int {
+	if mode == countModeCode {
+		return classifyLines(filePath, content).Code
+	}
+	return countLines(content)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestClassifyLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		expected LineCounts
+	}{
+		{"empty file", "main.go", "", LineCounts{}},
+		{
+			"go with license header",
+			"main.go",
+			"/*\nCopyright\n*/\npackage main\n\n// main runs\nfunc main() {}\n",
+			LineCounts{Code: 2, Comment: 4, Blank: 1},
+		},
+		{
+			"trailing comment counts as code",
+			"main.go",
+			"x := 1 // one\n",
+			LineCounts{Code: 1},
+		},
+		{
+			"comment markers inside strings",
+			"main.go",
+			"s := \"// not a comment\"\nu := `/* nor this`\n",
+			LineCounts{Code: 2},
+		},
+		{
+			"escaped quote in string",
+			"app.js",
+			"let s = \"a \\\" // b\";\n// real\n",
+			LineCounts{Code: 1, Comment: 1},
+		},
+		{
+			"code after block comment close",
+			"Main.java",
+			"/* start\n end */ int x;\n",
+			LineCounts{Code: 1, Comment: 1},
+		},
+		{
+			"python docstring and hash",
+			"app.py",
+			"\"\"\"Module doc.\n\nMore.\n\"\"\"\n# comment\nimport os\n",
+			LineCounts{Code: 1, Comment: 4, Blank: 1},
+		},
+		{
+			"ruby begin/end block",
+			"app.rb",
+			"=begin\ndocs\n=end\nputs 'hi' # greet\n",
+			LineCounts{Code: 1, Comment: 3},
+		},
+		{
+			"php hash comments",
+			"index.php",
+			"<?php\n# legacy\n// modern\necho 1;\n",
+			LineCounts{Code: 2, Comment: 2},
+		},
+		{
+			"rust lifetimes are not strings",
+			"lib.rs",
+			"fn f<'a>(x: &'a str) {}\n// doc\n",
+			LineCounts{Code: 1, Comment: 1},
+		},
+		{
+			"unknown language only separates blanks",
+			"README.md",
+			"# Title\n\ntext\n",
+			LineCounts{Code: 2, Blank: 1},
+		},
+		{
+			"crlf line endings",
+			"main.c",
+			"int x;\r\n\r\n// c\r\n",
+			LineCounts{Code: 1, Comment: 1, Blank: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := classifyLines(tt.path, tt.content)
+			if result != tt.expected {
+				t.Errorf("classifyLines(%q) = %+v, want %+v", tt.path, result, tt.expected)
+			}
+			if result.Total() != countLines(tt.content) {
+				t.Errorf("Total() = %d, want countLines = %d", result.Total(), countLines(tt.content))
+			}
+		})
+	}
+}
+
+func TestCountFileLines(t *testing.T) {
+	content := "// header\n\npackage main\n"
+
+	if got := countFileLines("main.go", content, countModeAll); got != 3 {
+		t.Errorf("countFileLines(all) = %d, want 3", got)
+	}
+	if got := countFileLines("main.go", content, countModeCode); got != 1 {
+		t.Errorf("countFileLines(code) = %d, want 1", got)
+	}
+}
+
+func TestValidateCountMode(t *testing.T) {
+	tests := []struct {
+		mode        string
+		expectError bool
+	}{
+		{countModeCode, false},
+		{countModeAll, false},
+		{"comments", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		err := validateCountMode(tt.mode)
+		if (err != nil) != tt.expectError {
+			t.Errorf("validateCountMode(%q) error = %v, expectError %v", tt.mode, err, tt.expectError)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// Merge commit handling is decided in this file and nowhere else. Every
+// history-walking analysis asks these helpers which parents to diff a commit
+// against, so a merge means the same thing to churn, survival, bus-factor and
+// the rest:
+//
+//	--merges skip          merge commits contribute no changes; the commits they
+//	                       bring in are counted individually (default)
+//	--merges first-parent  a merge contributes its diff against its first
+//	                       parent, i.e. everything it brought into the mainline
+//	--merges all           a merge is diffed against every parent and line
+//	                       counts are divided across parents (see
+//	                       applyMergeCommitAdjustment)
+//
+// The --first-parent walk mode follows only the first parent of each commit,
+// so every merged branch shows up as a single unit of change. It implies
+// --merges first-parent unless --merges is given explicitly.
+const (
+	mergePolicySkip        = "skip"
+	mergePolicyFirstParent = "first-parent"
+	mergePolicyAll         = "all"
+)
+
+var (
+	mergePolicyArg string
+	firstParentArg bool
+)
+
+// lineDelta holds the lines added and deleted in a single file.
+type lineDelta struct {
+	Additions int
+	Deletions int
+}
+
+// resolveMergePolicy validates a --merges value and fills in the default for the walk mode
+func resolveMergePolicy(policy string, firstParent bool) (string, error) {
+	switch policy {
+	case "":
+		if firstParent {
+			return mergePolicyFirstParent, nil
+		}
+		return mergePolicySkip, nil
+	case mergePolicySkip, mergePolicyFirstParent, mergePolicyAll:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid --merges value %q (expected skip, first-parent or all)", policy)
+	}
+}
+
+// activeMergePolicy returns the merge policy selected by the global flags
+func activeMergePolicy() string {
+	policy, err := resolveMergePolicy(mergePolicyArg, firstParentArg)
+	if err != nil {
+		return mergePolicySkip
+	}
+	return policy
+}
+
+// describeMergeHandling returns a one-line description of the active walk mode and merge policy
+func describeMergeHandling() string {
+	walk := "all commits"
+	if firstParentArg {
+		walk = "first-parent only"
+	}
+	return fmt.Sprintf("%s, merges: %s", walk, activeMergePolicy())
+}
+
+// includeMergeCommit reports whether a commit takes part in commit-counting analyses.
+// Only merge commits are ever excluded, and only under the "skip" policy.
+func includeMergeCommit(c *object.Commit) bool {
+	return c.NumParents() <= 1 || activeMergePolicy() != mergePolicySkip
+}
+
+// mergeDiffParents returns the parents a commit should be diffed against under
+// the active merge policy. Root commits and skipped merges yield no parents.
+func mergeDiffParents(c *object.Commit) ([]*object.Commit, error) {
+	if c.NumParents() == 0 {
+		return nil, nil
+	}
+
+	policy := activeMergePolicy()
+	if c.NumParents() == 1 || policy == mergePolicyFirstParent {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		return []*object.Commit{parent}, nil
+	}
+
+	if policy == mergePolicySkip {
+		return nil, nil
+	}
+
+	var parents []*object.Commit
+	iter := c.Parents()
+	defer iter.Close()
+	err := iter.ForEach(func(parent *object.Commit) error {
+		parents = append(parents, parent)
+		return nil
+	})
+	return parents, err
+}
+
+// commitPatches returns one patch per parent the commit is diffed against.
+// Root commits yield no patches; callers decide how to treat them.
+func commitPatches(c *object.Commit) ([]*object.Patch, error) {
+	parents, err := mergeDiffParents(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var patches []*object.Patch
+	for _, parent := range parents {
+		patch, err := parent.Patch(c)
+		if err != nil {
+			log.Printf("failed to generate patch between parent %s and commit %s: %v", parent.Hash.String(), c.Hash.String(), err)
+			continue
+		}
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// commitRenameChanges returns one rename-aware set of tree changes per parent
+// the merge policy diffs the commit against; a root commit is diffed against
+// the empty tree, so it creates every file.
+func commitRenameChanges(c *object.Commit) ([]object.Changes, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var bases []*object.Tree
+	if c.NumParents() == 0 {
+		bases = append(bases, nil)
+	} else {
+		parents, err := mergeDiffParents(c)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			parentTree, err := parent.Tree()
+			if err != nil {
+				return nil, err
+			}
+			bases = append(bases, parentTree)
+		}
+	}
+
+	var sets []object.Changes
+	for _, base := range bases {
+		changes, err := object.DiffTreeWithOptions(context.Background(), base, tree, object.DefaultDiffTreeOptions)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, changes)
+	}
+	return sets, nil
+}
+
+// commitRenamePatches returns one rename-aware patch per parent the commit is
+// diffed against; a root commit is diffed against the empty tree. Unlike
+// commitPatches, a moved file shows up as a single file patch from its old
+// path to its new one, so line-tracking analyses can follow it.
+func commitRenamePatches(c *object.Commit) ([]*object.Patch, error) {
+	sets, err := commitRenameChanges(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var patches []*object.Patch
+	for _, changes := range sets {
+		// Submodule entries have no blob to diff
+		var files object.Changes
+		for _, change := range changes {
+			if change.From.TreeEntry.Mode != filemode.Submodule && change.To.TreeEntry.Mode != filemode.Submodule {
+				files = append(files, change)
+			}
+		}
+		patch, err := files.Patch()
+		if err != nil {
+			return nil, fmt.Errorf("could not diff %s: %v", c.Hash.String(), err)
+		}
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// commitLineChanges returns per-file line changes for a commit under the active
+// merge policy. When a merge is diffed against several parents the counts are
+// divided across them with applyMergeCommitAdjustment.
+func commitLineChanges(c *object.Commit, pathFilters []string) (map[string]lineDelta, error) {
+	patches, err := commitPatches(c)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]lineDelta)
+	for _, patch := range patches {
+		for _, stat := range patch.Stats() {
+			if !matchesPathFilter(stat.Name, pathFilters) {
+				continue
+			}
+			delta := changes[stat.Name]
+			delta.Additions += stat.Addition
+			delta.Deletions += stat.Deletion
+			changes[stat.Name] = delta
+		}
+	}
+
+	if len(patches) > 1 {
+		for name, delta := range changes {
+			delta.Additions, delta.Deletions = applyMergeCommitAdjustment(delta.Additions, delta.Deletions, len(patches))
+			changes[name] = delta
+		}
+	}
+
+	return changes, nil
+}
+
+// commitChangedPaths returns the paths a commit touched under the active merge
+// policy, each listed once. Root commits touch every file in their tree.
+// Deleted files are only included when includeDeletes is set.
+func commitChangedPaths(c *object.Commit, pathFilters []string, includeDeletes bool) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	if c.NumParents() == 0 {
+		err = tree.Files().ForEach(func(f *object.File) error {
+			if matchesPathFilter(f.Name, pathFilters) {
+				paths = append(paths, f.Name)
+			}
+			return nil
+		})
+		return paths, err
+	}
+
+	parents, err := mergeDiffParents(c)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, parent := range parents {
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+		changes, err := parentTree.Diff(tree)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			path := change.To.Name
+			if path == "" {
+				if !includeDeletes {
+					continue
+				}
+				path = change.From.Name
+			}
+			if seen[path] || !matchesPathFilter(path, pathFilters) {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// logCommits returns a commit iterator honoring the --first-parent walk mode.
+// Without it this is exactly repo.Log.
+func logCommits(repo *git.Repository, opts *git.LogOptions) (object.CommitIter, error) {
+	if !firstParentArg {
+		return repo.Log(opts)
+	}
+
+	from := opts.From
+	if from.IsZero() {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, err
+		}
+		from = head.Hash()
+	}
+
+	start, err := repo.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+
+	return &firstParentIter{next: start, since: opts.Since, until: opts.Until}, nil
+}
+
+// firstParentIter walks a commit's first-parent chain, newest first. Since and
+// Until filter commits by committer time, like the corresponding LogOptions.
+type firstParentIter struct {
+	next  *object.Commit
+	since *time.Time
+	until *time.Time
+}
+
+// Next returns the next commit on the first-parent chain
+func (it *firstParentIter) Next() (*object.Commit, error) {
+	for it.next != nil {
+		c := it.next
+		it.next = nil
+		if c.NumParents() > 0 {
+			parent, err := c.Parent(0)
+			if err != nil && err != plumbing.ErrObjectNotFound {
+				return nil, err
+			}
+			// A missing parent means a shallow clone; the walk simply ends there
+			it.next = parent
+		}
+
+		if it.since != nil && c.Committer.When.Before(*it.since) {
+			continue
+		}
+		if it.until != nil && c.Committer.When.After(*it.until) {
+			continue
+		}
+		return c, nil
+	}
+	return nil, io.EOF
+}
+
+// ForEach calls cb for each remaining commit, stopping cleanly on storer.ErrStop
+func (it *firstParentIter) ForEach(cb func(*object.Commit) error) error {
+	for {
+		c, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := cb(c); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Close releases the iterator
+func (it *firstParentIter) Close() {
+	it.next = nil
+}
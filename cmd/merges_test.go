@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestResolveMergePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		firstParent bool
+		expected    string
+		expectError bool
+	}{
+		{"default walk", "", false, mergePolicySkip, false},
+		{"first-parent walk", "", true, mergePolicyFirstParent, false},
+		{"explicit skip", "skip", false, mergePolicySkip, false},
+		{"explicit all", "all", false, mergePolicyAll, false},
+		{"explicit policy wins over walk default", "skip", true, mergePolicySkip, false},
+		{"invalid policy", "octopus", false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolveMergePolicy(tt.policy, tt.firstParent)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for policy %q, got none", tt.policy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("resolveMergePolicy(%q, %v) = %q, want %q", tt.policy, tt.firstParent, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIncludeMergeCommit(t *testing.T) {
+	defer func(policy string, firstParent bool) {
+		mergePolicyArg, firstParentArg = policy, firstParent
+	}(mergePolicyArg, firstParentArg)
+
+	regular := &object.Commit{ParentHashes: []plumbing.Hash{plumbing.ZeroHash}}
+	merge := &object.Commit{ParentHashes: []plumbing.Hash{plumbing.ZeroHash, plumbing.ZeroHash}}
+
+	tests := []struct {
+		policy        string
+		firstParent   bool
+		expectRegular bool
+		expectMerge   bool
+	}{
+		{"", false, true, false},
+		{"", true, true, true},
+		{"skip", false, true, false},
+		{"first-parent", false, true, true},
+		{"all", false, true, true},
+	}
+
+	for _, tt := range tests {
+		mergePolicyArg, firstParentArg = tt.policy, tt.firstParent
+		if got := includeMergeCommit(regular); got != tt.expectRegular {
+			t.Errorf("policy %q (first-parent %v): includeMergeCommit(regular) = %v, want %v", tt.policy, tt.firstParent, got, tt.expectRegular)
+		}
+		if got := includeMergeCommit(merge); got != tt.expectMerge {
+			t.Errorf("policy %q (first-parent %v): includeMergeCommit(merge) = %v, want %v", tt.policy, tt.firstParent, got, tt.expectMerge)
+		}
+	}
+}
+
+func TestDescribeMergeHandling(t *testing.T) {
+	defer func(policy string, firstParent bool) {
+		mergePolicyArg, firstParentArg = policy, firstParent
+	}(mergePolicyArg, firstParentArg)
+
+	mergePolicyArg, firstParentArg = "", false
+	if got := describeMergeHandling(); got != "all commits, merges: skip" {
+		t.Errorf("Unexpected description %q", got)
+	}
+
+	mergePolicyArg, firstParentArg = "", true
+	if got := describeMergeHandling(); got != "first-parent only, merges: first-parent" {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
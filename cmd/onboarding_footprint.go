@@ -113,7 +113,7 @@ func analyzeOnboardingFootprint(repo *git.Repository, pathFilters []string, last
 	
 	// Single-pass analysis: find first commits AND gather commit data efficiently
 	// This prevents memory issues from loading full history twice
-	commitIter, err := repo.Log(&git.LogOptions{
+	commitIter, err := logCommits(repo, &git.LogOptions{
 		// No Since filter - we need full history to find true first commits
 	})
 	if err != nil {
@@ -134,8 +134,8 @@ func analyzeOnboardingFootprint(repo *git.Repository, pathFilters []string, last
 		author := commit.Author.Email
 		commitTime := commit.Author.When
 		
-		// Skip merge commits for cleaner analysis
-		if commit.NumParents() > 1 {
+		// Merge commits count only when the merge policy includes them
+		if !includeMergeCommit(commit) {
 			return nil
 		}
 		
@@ -144,59 +144,10 @@ func analyzeOnboardingFootprint(repo *git.Repository, pathFilters []string, last
 			authorTrueFirstCommit[author] = commitTime
 		}
 		
-		// Get files changed in this commit
-		var filesChanged []string
-		
-		if commit.NumParents() == 0 {
-			// Initial commit - treat as adding all files
-			tree, err := commit.Tree()
-			if err != nil {
-				return err
-			}
-			
-			err = tree.Files().ForEach(func(file *object.File) error {
-				if matchesPathFilter(file.Name, pathFilters) {
-					filesChanged = append(filesChanged, file.Name)
-				}
-				return nil
-			})
-			if err != nil {
-				return err
-			}
-		} else {
-			// Regular commit - analyze diff with parent
-			parent, err := commit.Parent(0)
-			if err != nil {
-				return err
-			}
-			
-			parentTree, err := parent.Tree()
-			if err != nil {
-				return err
-			}
-			
-			currentTree, err := commit.Tree()
-			if err != nil {
-				return err
-			}
-			
-			changes, err := parentTree.Diff(currentTree)
-			if err != nil {
-				return err
-			}
-			
-			for _, change := range changes {
-				var filePath string
-				if change.To.Name != "" {
-					filePath = change.To.Name
-				} else if change.From.Name != "" {
-					filePath = change.From.Name
-				}
-				
-				if filePath != "" && matchesPathFilter(filePath, pathFilters) {
-					filesChanged = append(filesChanged, filePath)
-				}
-			}
+		// Get files changed in this commit (including deletions)
+		filesChanged, err := commitChangedPaths(commit, pathFilters, true)
+		if err != nil {
+			return err
 		}
 		
 		// Store commit info for all authors during single pass
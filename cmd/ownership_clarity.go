@@ -171,7 +171,7 @@ func analyzeOwnershipClarity(repo *git.Repository, pathFilters []string, lastArg
 // analyzeFileOwnership analyzes ownership for individual files using efficient log options
 func analyzeFileOwnership(repo *git.Repository, pathFilters []string, since *time.Time) ([]FileOwnership, error) {
 	// Use efficient log options with early path filtering
-	commitIter, err := repo.Log(&git.LogOptions{
+	commitIter, err := logCommits(repo, &git.LogOptions{
 		Since: since,
 	})
 	if err != nil {
@@ -189,8 +189,8 @@ func analyzeFileOwnership(repo *git.Repository, pathFilters []string, since *tim
 		}
 		author := commit.Author.Email
 		
-		// Skip merge commits for performance (they often don't represent meaningful ownership)
-		if commit.NumParents() > 1 {
+		// Merge commits count only when the merge policy includes them
+		if !includeMergeCommit(commit) {
 			return nil
 		}
 		
@@ -0,0 +1,497 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+const (
+	revertMethodMessage = "message"
+	revertMethodInverse = "inverse diff"
+)
+
+// revertMessagePattern matches the message git revert writes
+var revertMessagePattern = regexp.MustCompile(`(?i)this reverts commit ([0-9a-f]{7,40})`)
+
+// RevertEvent links a revert commit to the commit it undid
+type RevertEvent struct {
+	Revert       *object.Commit
+	OriginalHash plumbing.Hash
+	Original     *object.Commit // nil when the original could not be loaded
+	Method       string
+}
+
+// TimeToRevert returns how long the original commit lived before it was reverted
+func (e RevertEvent) TimeToRevert() time.Duration {
+	if e.Original == nil {
+		return 0
+	}
+	return e.Revert.Committer.When.Sub(e.Original.Committer.When)
+}
+
+// RevertRate is the share of one group's commits that were later reverted
+type RevertRate struct {
+	Group    string
+	Commits  int
+	Reverted int
+	Rate     float64
+}
+
+// RevertAnalysis is the result of a reverts run
+type RevertAnalysis struct {
+	Commits            int
+	Reverts            []RevertEvent
+	Unlinked           int // revert messages naming a commit that could not be found
+	MedianTimeToRevert time.Duration
+	ByAuthor           []RevertRate
+	ByDirectory        []RevertRate
+	BySize             []RevertRate
+}
+
+// revertCandidate is one commit with what revert detection needs to know about it
+type revertCandidate struct {
+	commit    *object.Commit
+	author    string
+	lines     int
+	files     int
+	risk      string
+	dirs      []string
+	signature string // exact line changes; empty for merges, root commits and binary-only changes
+	inverse   string // the signature of a commit undoing exactly these changes
+}
+
+// signatureLine is one added or deleted line in a commit's diff
+type signatureLine struct {
+	Path  string
+	Added bool
+	Text  string
+}
+
+// lineChangeSignature fingerprints a set of line changes independently of
+// their order. With inverse set, additions and deletions swap, so the inverse
+// signature of a commit equals the signature of its exact revert.
+func lineChangeSignature(changes []signatureLine, inverse bool) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	entries := make([]string, 0, len(changes))
+	for _, c := range changes {
+		op := "-"
+		if c.Added != inverse {
+			op = "+"
+		}
+		entries = append(entries, c.Path+"\x00"+op+"\x00"+c.Text)
+	}
+	sort.Strings(entries)
+	return hashLine(strings.Join(entries, "\n"))
+}
+
+// revertedHashFromMessage returns the (possibly abbreviated) hash a revert message names
+func revertedHashFromMessage(message string) (string, bool) {
+	m := revertMessagePattern.FindStringSubmatch(message)
+	if m == nil {
+		return "", false
+	}
+	return strings.ToLower(m[1]), true
+}
+
+// profileRevertCandidate measures a commit's size within the path filters and
+// fingerprints its full diff. Sizes match calculateCommitChanges.
+func profileRevertCandidate(c *object.Commit, pathFilters []string) (*revertCandidate, error) {
+	cand := &revertCandidate{commit: c, author: normalizeAuthorName(c.Author.Name, c.Author.Email)}
+
+	if c.NumParents() != 1 {
+		lines, files, err := calculateCommitChanges(c, pathFilters)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := commitChangedPaths(c, pathFilters, true)
+		if err != nil {
+			return nil, err
+		}
+		cand.lines, cand.files = lines, files
+		cand.dirs = revertCandidateDirs(paths)
+		cand.risk, _ = classifyCommitRisk(lines, files)
+		return cand, nil
+	}
+
+	parent, err := c.Parent(0)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := parent.Patch(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, stat := range patch.Stats() {
+		if !matchesPathFilter(stat.Name, pathFilters) {
+			continue
+		}
+		cand.lines += stat.Addition + stat.Deletion
+		cand.files++
+		paths = append(paths, stat.Name)
+	}
+	cand.dirs = revertCandidateDirs(paths)
+	cand.risk, _ = classifyCommitRisk(cand.lines, cand.files)
+
+	var changes []signatureLine
+	for _, fp := range patch.FilePatches() {
+		if fp.IsBinary() {
+			continue
+		}
+		from, to := fp.Files()
+		path := ""
+		if to != nil {
+			path = to.Path()
+		} else if from != nil {
+			path = from.Path()
+		}
+		for _, chunk := range fp.Chunks() {
+			if chunk.Type() == diff.Equal {
+				continue
+			}
+			for _, l := range splitSurvivalLines(chunk.Content()) {
+				changes = append(changes, signatureLine{Path: path, Added: chunk.Type() == diff.Add, Text: l})
+			}
+		}
+	}
+	cand.signature = lineChangeSignature(changes, false)
+	cand.inverse = lineChangeSignature(changes, true)
+	return cand, nil
+}
+
+// revertCandidateDirs returns the distinct directories of a commit's paths
+func revertCandidateDirs(paths []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, p := range paths {
+		dir := reportDirectory(p)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// detectReverts links reverts to their originals. Candidates must be ordered
+// oldest first. A revert message naming a commit wins; otherwise a commit
+// whose diff is the exact inverse of an earlier, not yet reverted commit's is
+// taken as its revert. resolve looks up commits named by abbreviated hashes
+// outside the candidates; it returns nil when the commit can't be found.
+func detectReverts(candidates []*revertCandidate, resolve func(prefix string) *object.Commit) ([]RevertEvent, int) {
+	var events []RevertEvent
+	unlinked := 0
+	bySignature := make(map[string][]*object.Commit)
+	reverted := make(map[plumbing.Hash]bool)
+
+	findCandidate := func(prefix string) *object.Commit {
+		for _, cand := range candidates {
+			if strings.HasPrefix(cand.commit.Hash.String(), prefix) {
+				return cand.commit
+			}
+		}
+		return nil
+	}
+
+	for _, cand := range candidates {
+		c := cand.commit
+		if prefix, ok := revertedHashFromMessage(c.Message); ok {
+			original := findCandidate(prefix)
+			if original == nil && resolve != nil {
+				original = resolve(prefix)
+			}
+			if original == nil || original.Hash == c.Hash {
+				unlinked++
+			} else {
+				events = append(events, RevertEvent{Revert: c, OriginalHash: original.Hash, Original: original, Method: revertMethodMessage})
+				reverted[original.Hash] = true
+			}
+		} else if cand.inverse != "" {
+			originals := bySignature[cand.inverse]
+			for i := len(originals) - 1; i >= 0; i-- {
+				if original := originals[i]; !reverted[original.Hash] {
+					events = append(events, RevertEvent{Revert: c, OriginalHash: original.Hash, Original: original, Method: revertMethodInverse})
+					reverted[original.Hash] = true
+					break
+				}
+			}
+		}
+		if cand.signature != "" {
+			bySignature[cand.signature] = append(bySignature[cand.signature], c)
+		}
+	}
+	return events, unlinked
+}
+
+// revertRates computes the revert rate of each group, highest rate first
+func revertRates(candidates []*revertCandidate, reverted map[plumbing.Hash]bool, groups func(*revertCandidate) []string) []RevertRate {
+	rates := make(map[string]*RevertRate)
+	for _, cand := range candidates {
+		for _, g := range groups(cand) {
+			if rates[g] == nil {
+				rates[g] = &RevertRate{Group: g}
+			}
+			rates[g].Commits++
+			if reverted[cand.commit.Hash] {
+				rates[g].Reverted++
+			}
+		}
+	}
+
+	result := make([]RevertRate, 0, len(rates))
+	for _, r := range rates {
+		r.Rate = float64(r.Reverted) / float64(r.Commits) * 100
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Rate != result[j].Rate {
+			return result[i].Rate > result[j].Rate
+		}
+		if result[i].Commits != result[j].Commits {
+			return result[i].Commits > result[j].Commits
+		}
+		return result[i].Group < result[j].Group
+	})
+	return result
+}
+
+// collectRevertCandidates profiles the window's commits, oldest first
+func collectRevertCandidates(repo *git.Repository, since time.Time, pathFilters []string) ([]*revertCandidate, error) {
+	var opts git.LogOptions
+	if !since.IsZero() {
+		opts.Since = &since
+	}
+	cIter, err := logCommits(repo, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	defer cIter.Close()
+
+	var candidates []*revertCandidate
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if !includeMergeCommit(c) {
+			return nil
+		}
+		cand, err := profileRevertCandidate(c, pathFilters)
+		if err != nil {
+			log.Printf("Error processing commit %s: %v", c.Hash.String(), err)
+			return nil
+		}
+		if cand.files == 0 {
+			return nil
+		}
+		candidates = append(candidates, cand)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking commits: %v", err)
+	}
+
+	return oldestFirstCandidates(candidates), nil
+}
+
+// oldestFirstCandidates reverses log order (newest first) and sorts by commit
+// time; reversing first keeps commits made in the same second in history order
+func oldestFirstCandidates(candidates []*revertCandidate) []*revertCandidate {
+	ordered := make([]*revertCandidate, len(candidates))
+	for i, c := range candidates {
+		ordered[len(candidates)-1-i] = c
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].commit.Committer.When.Before(ordered[j].commit.Committer.When)
+	})
+	return ordered
+}
+
+// repoCommitResolver looks up commits by abbreviated hash
+func repoCommitResolver(repo *git.Repository) func(prefix string) *object.Commit {
+	return func(prefix string) *object.Commit {
+		hash, err := repo.ResolveRevision(plumbing.Revision(prefix))
+		if err != nil {
+			return nil
+		}
+		c, err := repo.CommitObject(*hash)
+		if err != nil {
+			return nil
+		}
+		return c
+	}
+}
+
+// analyzeReverts detects reverts in the window and breaks revert rates down
+// by author, directory and size
+func analyzeReverts(repo *git.Repository, since time.Time, pathFilters []string) (*RevertAnalysis, error) {
+	candidates, err := collectRevertCandidates(repo, since, pathFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	events, unlinked := detectReverts(candidates, repoCommitResolver(repo))
+	reverted := make(map[plumbing.Hash]bool)
+	var times []float64
+	for _, e := range events {
+		reverted[e.OriginalHash] = true
+		if e.Original != nil {
+			times = append(times, e.TimeToRevert().Hours())
+		}
+	}
+
+	analysis := &RevertAnalysis{
+		Commits:  len(candidates),
+		Reverts:  events,
+		Unlinked: unlinked,
+		ByAuthor: revertRates(candidates, reverted, func(c *revertCandidate) []string {
+			return []string{c.author}
+		}),
+		ByDirectory: revertRates(candidates, reverted, func(c *revertCandidate) []string {
+			return c.dirs
+		}),
+		BySize: revertRates(candidates, reverted, func(c *revertCandidate) []string {
+			return []string{c.risk}
+		}),
+	}
+	if len(times) > 0 {
+		analysis.MedianTimeToRevert = time.Duration(calculatePercentile(times, 50) * float64(time.Hour))
+	}
+	return analysis, nil
+}
+
+// printRevertRates prints one revert rate breakdown
+func printRevertRates(title, column string, rates []RevertRate, limit int) {
+	fmt.Printf("\n%s:\n", title)
+	fmt.Printf("%-40s %8s %9s %7s\n", column, "Commits", "Reverted", "Rate")
+	for i, r := range rates {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-40s %8d %9d %6.1f%%\n", r.Group, r.Commits, r.Reverted, r.Rate)
+	}
+}
+
+// printRevertAnalysis prints the detected reverts and revert rates
+func printRevertAnalysis(analysis *RevertAnalysis, limit int) {
+	fmt.Printf("Revert Analysis\n")
+	fmt.Printf("Commits analyzed: %d\n", analysis.Commits)
+	rate := 0.0
+	if analysis.Commits > 0 {
+		rate = float64(len(analysis.Reverts)) / float64(analysis.Commits) * 100
+	}
+	fmt.Printf("Reverts: %d (%.1f%% of commits)\n", len(analysis.Reverts), rate)
+	if analysis.Unlinked > 0 {
+		fmt.Printf("Revert messages naming a commit that could not be found: %d\n", analysis.Unlinked)
+	}
+	if len(analysis.Reverts) == 0 {
+		fmt.Println("\nNo reverts found.")
+		return
+	}
+	fmt.Printf("Median time to revert: %s\n", formatElapsed(analysis.MedianTimeToRevert))
+
+	fmt.Printf("\nMost recent reverts (showing %d):\n", min(len(analysis.Reverts), limit))
+	fmt.Printf("%-8s %-8s %-10s %-12s %-25s  %s\n", "Revert", "Original", "Date", "Time", "Author", "Original message")
+	for i := len(analysis.Reverts) - 1; i >= 0 && len(analysis.Reverts)-1-i < limit; i-- {
+		e := analysis.Reverts[i]
+		elapsed, author, subject := "-", "-", ""
+		if e.Original != nil {
+			elapsed = formatElapsed(e.TimeToRevert())
+			author = normalizeAuthorName(e.Original.Author.Name, e.Original.Author.Email)
+			subject = trimMessage(e.Original.Message)
+		}
+		if e.Method == revertMethodInverse {
+			elapsed += "*"
+		}
+		fmt.Printf("%-8s %-8s %-10s %-12s %-25s  %s\n", e.Revert.Hash.String()[:7], e.OriginalHash.String()[:7], e.Revert.Committer.When.Format("2006-01-02"), elapsed, author, subject)
+	}
+
+	printRevertRates("Revert rate by risk level (commit size)", "Risk", analysis.BySize, limit)
+	printRevertRates("Revert rate by author", "Author", analysis.ByAuthor, limit)
+	printRevertRates("Revert rate by directory", "Directory", analysis.ByDirectory, limit)
+
+	fmt.Println()
+	fmt.Println("* detected as an exact inverse diff rather than from the revert message.")
+	fmt.Println("Risk levels use the high-risk-commits thresholds; a rising revert rate with size supports them.")
+}
+
+// revertsCmd represents the reverts command
+var revertsCmd = &cobra.Command{
+	Use:   "reverts",
+	Short: "Detect reverted commits and measure revert rates",
+	Long: `Find out how often changes get undone.
+
+A commit is a revert when its message contains git's "This reverts commit
+<hash>" line, or when its diff is the exact inverse of an earlier commit's.
+Each revert is linked to the commit it undid, and the time between the two is
+reported. Revert rates are broken down by the original commit's author, its
+directories and its size bucket (the high-risk-commits risk levels), so you
+can check whether big commits really are reverted more often.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse flags
+		lastArg, _ := cmd.Flags().GetString("last")
+		pathFilters, source := getConfigPaths(cmd, "reverts.paths")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+
+		// Print configuration scope
+		printCommandScope(cmd, "reverts", lastArg, pathFilters, source)
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			log.Fatalf("Could not open repository: %v", err)
+		}
+
+		since := time.Time{}
+		if lastArg != "" {
+			cutoff, err := parseDurationArg(lastArg)
+			if err != nil {
+				log.Fatalf("Could not parse --last argument: %v", err)
+			}
+			since = cutoff
+		}
+
+		analysis, err := analyzeReverts(repo, since, pathFilters)
+		if err != nil {
+			log.Fatalf("Error analyzing reverts: %v", err)
+		}
+
+		printRevertAnalysis(analysis, limitArg)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(revertsCmd)
+	revertsCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
+	revertsCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+	revertsCmd.Flags().Int("limit", 10, "Number of reverts and groups to show")
+}
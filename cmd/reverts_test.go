@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func testRevertCommit(hash, message string, when time.Time) *object.Commit {
+	return &object.Commit{
+		Hash:      plumbing.NewHash(hash),
+		Message:   message,
+		Committer: object.Signature{When: when},
+	}
+}
+
+func TestRevertedHashFromMessage(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected string
+		ok       bool
+	}{
+		{"Revert \"Add cache\"\n\nThis reverts commit 0123456789ABCDEF0123456789abcdef01234567.", "0123456789abcdef0123456789abcdef01234567", true},
+		{"Revert abc\n\nthis reverts commit abc1234", "abc1234", true},
+		{"Revert the cache change", "", false},
+		{"This reverts commit xyz", "", false},
+	}
+
+	for _, tt := range tests {
+		hash, ok := revertedHashFromMessage(tt.message)
+		if hash != tt.expected || ok != tt.ok {
+			t.Errorf("revertedHashFromMessage(%q) = %q, %v, want %q, %v", tt.message, hash, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestLineChangeSignature(t *testing.T) {
+	change := []signatureLine{
+		{Path: "a.go", Added: true, Text: "x := 1\n"},
+		{Path: "a.go", Added: false, Text: "x := 0\n"},
+	}
+	// The revert lists the same lines, in a different order, with the operations swapped
+	revert := []signatureLine{
+		{Path: "a.go", Added: false, Text: "x := 1\n"},
+		{Path: "a.go", Added: true, Text: "x := 0\n"},
+	}
+
+	if lineChangeSignature(change, true) != lineChangeSignature(revert, false) {
+		t.Error("Expected a change's inverse signature to equal its revert's signature")
+	}
+	if lineChangeSignature(change, false) == lineChangeSignature(revert, false) {
+		t.Error("Expected a change and its revert to have different signatures")
+	}
+	if lineChangeSignature(nil, false) != "" {
+		t.Error("Expected an empty signature for no changes")
+	}
+}
+
+func TestDetectReverts(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	original := testRevertCommit("1111111111111111111111111111111111111111", "Add cache", start)
+	messageRevert := testRevertCommit("2222222222222222222222222222222222222222", "Revert \"Add cache\"\n\nThis reverts commit 1111111.", start.Add(2*time.Hour))
+	feature := testRevertCommit("3333333333333333333333333333333333333333", "Add flag", start.Add(3*time.Hour))
+	inverseRevert := testRevertCommit("4444444444444444444444444444444444444444", "Remove flag again", start.Add(24*time.Hour))
+	unknown := testRevertCommit("5555555555555555555555555555555555555555", "This reverts commit abcdef0.", start.Add(25*time.Hour))
+	older := testRevertCommit("6666666666666666666666666666666666666666", "Old change", start.AddDate(-1, 0, 0))
+	olderRevert := testRevertCommit("7777777777777777777777777777777777777777", "This reverts commit 6666666.", start.Add(26*time.Hour))
+
+	candidates := []*revertCandidate{
+		{commit: original, signature: "s1", inverse: "i1"},
+		{commit: messageRevert, signature: "i1", inverse: "s1"},
+		{commit: feature, signature: "s2", inverse: "i2"},
+		{commit: inverseRevert, signature: "i2", inverse: "s2"},
+		{commit: unknown},
+		{commit: olderRevert},
+	}
+	resolve := func(prefix string) *object.Commit {
+		if prefix == "6666666" {
+			return older
+		}
+		return nil
+	}
+
+	events, unlinked := detectReverts(candidates, resolve)
+	if unlinked != 1 {
+		t.Errorf("Expected 1 unlinked revert, got %d", unlinked)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 reverts, got %+v", events)
+	}
+	if events[0].Original != original || events[0].Method != revertMethodMessage {
+		t.Errorf("Expected the message revert to link to the original, got %+v", events[0])
+	}
+	if events[0].TimeToRevert() != 2*time.Hour {
+		t.Errorf("Expected 2h to revert, got %v", events[0].TimeToRevert())
+	}
+	if events[1].Original != feature || events[1].Method != revertMethodInverse {
+		t.Errorf("Expected the inverse diff to link to the feature, got %+v", events[1])
+	}
+	if events[2].Original != older {
+		t.Errorf("Expected the revert of an older commit to be resolved, got %+v", events[2])
+	}
+}
+
+func TestDetectRevertsLinksEachOriginalOnce(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	a := testRevertCommit("1111111111111111111111111111111111111111", "Add line", start)
+	b := testRevertCommit("2222222222222222222222222222222222222222", "Remove line", start.Add(time.Hour))
+	c := testRevertCommit("3333333333333333333333333333333333333333", "Add line back", start.Add(2*time.Hour))
+
+	// b undoes a, then c undoes b
+	events, _ := detectReverts([]*revertCandidate{
+		{commit: a, signature: "add", inverse: "remove"},
+		{commit: b, signature: "remove", inverse: "add"},
+		{commit: c, signature: "add", inverse: "remove"},
+	}, nil)
+	if len(events) != 2 || events[0].Original != a || events[1].Original != b {
+		t.Errorf("Expected b to revert a and c to revert b, got %+v", events)
+	}
+}
+
+func TestRevertRates(t *testing.T) {
+	a := testRevertCommit("1111111111111111111111111111111111111111", "", time.Time{})
+	b := testRevertCommit("2222222222222222222222222222222222222222", "", time.Time{})
+	c := testRevertCommit("3333333333333333333333333333333333333333", "", time.Time{})
+	candidates := []*revertCandidate{
+		{commit: a, author: "alice", dirs: []string{"src/", "lib/"}},
+		{commit: b, author: "alice", dirs: []string{"src/"}},
+		{commit: c, author: "bob", dirs: []string{"lib/"}},
+	}
+	reverted := map[plumbing.Hash]bool{a.Hash: true}
+
+	byAuthor := revertRates(candidates, reverted, func(c *revertCandidate) []string { return []string{c.author} })
+	if byAuthor[0].Group != "alice" || byAuthor[0].Commits != 2 || byAuthor[0].Rate != 50 {
+		t.Errorf("Expected alice at 50%%, got %+v", byAuthor[0])
+	}
+
+	byDir := revertRates(candidates, reverted, func(c *revertCandidate) []string { return c.dirs })
+	if len(byDir) != 2 || byDir[0].Reverted != 1 || byDir[1].Reverted != 1 {
+		t.Errorf("Expected the reverted commit to count in both directories, got %+v", byDir)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{30 * time.Minute, "30m"},
+		{5 * time.Hour, "5.0h"},
+		{72 * time.Hour, "3.0d"},
+	}
+
+	for _, tt := range tests {
+		if result := formatElapsed(tt.d); result != tt.expected {
+			t.Errorf("formatElapsed(%v) = %q, want %q", tt.d, result, tt.expected)
+		}
+	}
+}
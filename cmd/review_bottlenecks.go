@@ -0,0 +1,717 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Time to first review thresholds in hours. Google's code review guidelines
+// ask reviewers to respond within one business day at most; SmartBear's Cisco
+// study found review quality drops once changes sit waiting for days.
+const (
+	fastFirstReviewThreshold    = 4.0  // <4 hours
+	healthyFirstReviewThreshold = 24.0 // within one business day
+	slowFirstReviewThreshold    = 72.0 // within three days
+	// Three days or more is a bottleneck
+)
+
+// overloadedReviewerFactor flags reviewers who review this many times as many
+// pull requests as the other reviewers do on average
+const overloadedReviewerFactor = 2.0
+
+// Pull request states
+const (
+	prStateOpen   = "open"
+	prStateMerged = "merged"
+	prStateClosed = "closed"
+)
+
+// PullRequestReviewEvent is one review submitted on a pull request
+type PullRequestReviewEvent struct {
+	Reviewer string
+	State    string // "APPROVED", "CHANGES_REQUESTED", "COMMENTED", ...
+	Time     time.Time
+}
+
+// PullRequestReview is a pull request from an export, joined with git history
+type PullRequestReview struct {
+	Number      int
+	Title       string
+	Author      string
+	State       string // "open", "merged" or "closed"
+	Opened      time.Time
+	FirstReview time.Time // zero when nobody reviewed
+	Approved    time.Time // first approval, zero when not approved
+	Merged      time.Time
+	MergeHash   plumbing.Hash
+	MergeRef    string   // merge commit as exported, when not a full hash
+	Landed      bool     // merge commit found on the default branch
+	Requested   []string // reviewers asked to review
+	Reviews     []PullRequestReviewEvent
+}
+
+// IsReviewed reports whether anyone other than the author reviewed the pull request
+func (pr PullRequestReview) IsReviewed() bool {
+	if !pr.FirstReview.IsZero() {
+		return true
+	}
+	for _, r := range pr.Reviews {
+		if r.Reviewer != pr.Author {
+			return true
+		}
+	}
+	return false
+}
+
+// FirstReviewHours returns the hours from opening to the first review
+func (pr PullRequestReview) FirstReviewHours() float64 {
+	return pr.FirstReview.Sub(pr.Opened).Hours()
+}
+
+// WaitingOn names what an open pull request is waiting for
+func (pr PullRequestReview) WaitingOn() string {
+	switch {
+	case !pr.IsReviewed():
+		return "first review"
+	case pr.Approved.IsZero():
+		return "approval"
+	default:
+		return "merge"
+	}
+}
+
+// ReviewerLoad summarizes the reviews one person gave
+type ReviewerLoad struct {
+	Reviewer            string
+	Reviews             int // reviews submitted
+	PullRequests        int // distinct pull requests reviewed
+	Approvals           int
+	Requested           int     // pull requests they were asked to review
+	Pending             int     // open pull requests still waiting on their review
+	MedianResponseHours float64 // opening to their first review
+	Share               float64 // share of all reviewed pull requests
+	Overloaded          bool
+}
+
+// ReviewBottleneckStats summarizes how long pull requests wait for review
+type ReviewBottleneckStats struct {
+	PullRequests           int
+	Merged                 int
+	Open                   int
+	Closed                 int
+	Reviewed               int
+	MergedUnreviewed       int // merged without a review from anyone but the author
+	NotLanded              int // merged, but the merge commit is not on the default branch
+	MedianFirstReviewHours float64
+	P95FirstReviewHours    float64
+	MedianApprovalHours    float64             // opening to first approval
+	MedianMergeHours       float64             // opening to merge
+	Classification         string              // "Fast", "Healthy", "Slow", "Bottleneck", "Unknown"
+	Reviewers              []ReviewerLoad      // most pull requests reviewed first
+	Stuck                  []PullRequestReview // open pull requests, oldest first
+	SlowestFirstReview     []PullRequestReview // reviewed pull requests, longest wait first
+}
+
+// classifyFirstReviewTime classifies the median time to first review
+func classifyFirstReviewTime(hours float64) string {
+	if hours < fastFirstReviewThreshold {
+		return "Fast"
+	} else if hours < healthyFirstReviewThreshold {
+		return "Healthy"
+	} else if hours < slowFirstReviewThreshold {
+		return "Slow"
+	}
+	return "Bottleneck"
+}
+
+// recordValue returns the first of the named fields present in a record,
+// matching names case-insensitively
+func recordValue(record map[string]interface{}, names []string) interface{} {
+	for _, name := range names {
+		for key, v := range record {
+			if strings.EqualFold(key, name) && v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// nestedField reads a value that exports store either directly or as an
+// object, such as GitHub's "user": {"login": ...} or the GitHub CLI's
+// "mergeCommit": {"oid": ...}
+func nestedField(v interface{}, names []string) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}:
+		if s := recordField(value, names); s != "" {
+			return s
+		}
+		// GitLab nests approvers one level deeper: {"user": {"username": ...}}
+		return nestedField(recordValue(value, []string{"user", "author"}), names)
+	default:
+		return strings.TrimSpace(fmt.Sprint(value))
+	}
+}
+
+var identityFields = []string{"login", "username", "email", "name"}
+
+// recordIdentity reads a person from the first of the named fields
+func recordIdentity(record map[string]interface{}, names []string) string {
+	return nestedField(recordValue(record, names), identityFields)
+}
+
+// recordTime parses the first of the named timestamp fields, returning the
+// zero time when none is set
+func recordTime(record map[string]interface{}, names []string) (time.Time, error) {
+	value := recordField(record, names)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return parseDeployTime(value)
+}
+
+// recordList returns the objects in the first of the named list fields
+func recordList(record map[string]interface{}, names []string) []interface{} {
+	list, _ := recordValue(record, names).([]interface{})
+	return list
+}
+
+// parsePullRequestRecord reads one pull request in GitHub REST, GitHub CLI or
+// GitLab schema
+func parsePullRequestRecord(record map[string]interface{}) (PullRequestReview, error) {
+	var pr PullRequestReview
+	var err error
+
+	number := recordField(record, []string{"number", "iid"})
+	if number == "" {
+		return pr, fmt.Errorf("missing number")
+	}
+	if _, err := fmt.Sscan(number, &pr.Number); err != nil {
+		return pr, fmt.Errorf("invalid number %q", number)
+	}
+	pr.Title = recordField(record, []string{"title"})
+	pr.Author = recordIdentity(record, []string{"user", "author"})
+
+	if pr.Opened, err = recordTime(record, []string{"created_at", "createdAt", "opened_at", "openedAt"}); err != nil {
+		return pr, err
+	}
+	if pr.Opened.IsZero() {
+		return pr, fmt.Errorf("missing created_at")
+	}
+	if pr.Merged, err = recordTime(record, []string{"merged_at", "mergedAt"}); err != nil {
+		return pr, err
+	}
+	closed, err := recordTime(record, []string{"closed_at", "closedAt"})
+	if err != nil {
+		return pr, err
+	}
+
+	state := strings.ToLower(recordField(record, []string{"state"}))
+	switch {
+	case !pr.Merged.IsZero() || state == prStateMerged:
+		pr.State = prStateMerged
+	case !closed.IsZero() || state == prStateClosed:
+		pr.State = prStateClosed
+	default:
+		pr.State = prStateOpen
+	}
+
+	// GitHub fills merge_commit_sha with a test merge for unmerged pull requests
+	if pr.State == prStateMerged {
+		sha := nestedField(recordValue(record, []string{"merge_commit_sha", "mergeCommit", "merge_commit", "squash_commit_sha"}), []string{"oid", "sha"})
+		if plumbing.IsHash(sha) {
+			pr.MergeHash = plumbing.NewHash(sha)
+		} else {
+			// Abbreviated shas are resolved against the repository when joined
+			pr.MergeRef = sha
+		}
+	}
+
+	requested := make(map[string]bool)
+	for _, item := range recordList(record, []string{"requested_reviewers", "reviewRequests", "reviewers"}) {
+		if reviewer := nestedField(item, identityFields); reviewer != "" && !requested[reviewer] {
+			requested[reviewer] = true
+			pr.Requested = append(pr.Requested, reviewer)
+		}
+	}
+
+	for _, item := range recordList(record, []string{"reviews"}) {
+		review, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		event := PullRequestReviewEvent{
+			Reviewer: recordIdentity(review, []string{"user", "author"}),
+			State:    strings.ToUpper(recordField(review, []string{"state"})),
+		}
+		if event.Time, err = recordTime(review, []string{"submitted_at", "submittedAt", "created_at", "createdAt"}); err != nil {
+			return pr, err
+		}
+		// Pending reviews are drafts nobody else can see yet
+		if event.Reviewer == "" || event.State == "PENDING" {
+			continue
+		}
+		pr.Reviews = append(pr.Reviews, event)
+	}
+
+	// GitLab reports approvals separately from review comments
+	for _, item := range recordList(record, []string{"approved_by", "approvals"}) {
+		event := PullRequestReviewEvent{Reviewer: nestedField(item, identityFields), State: "APPROVED"}
+		if approval, ok := item.(map[string]interface{}); ok {
+			if event.Time, err = recordTime(approval, []string{"approved_at", "approvedAt", "created_at", "createdAt"}); err != nil {
+				return pr, err
+			}
+		}
+		if event.Reviewer != "" {
+			pr.Reviews = append(pr.Reviews, event)
+		}
+	}
+
+	firstReview, err := recordTime(record, []string{"first_review_at", "firstReviewAt"})
+	if err != nil {
+		return pr, err
+	}
+	approved, err := recordTime(record, []string{"approved_at", "approvedAt"})
+	if err != nil {
+		return pr, err
+	}
+	pr.FirstReview, pr.Approved = firstReview, approved
+	for _, r := range pr.Reviews {
+		if r.Reviewer == pr.Author || r.Time.IsZero() {
+			continue
+		}
+		if pr.FirstReview.IsZero() || r.Time.Before(pr.FirstReview) {
+			pr.FirstReview = r.Time
+		}
+		if r.State == "APPROVED" && (pr.Approved.IsZero() || r.Time.Before(pr.Approved)) {
+			pr.Approved = r.Time
+		}
+	}
+	return pr, nil
+}
+
+// parsePullRequestExport reads a JSON array of pull requests exported from
+// GitHub or GitLab
+func parsePullRequestExport(data []byte) ([]PullRequestReview, error) {
+	records, err := decodeJSONRecords(data)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]PullRequestReview, 0, len(records))
+	for i, record := range records {
+		pr, err := parsePullRequestRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", i+1, err)
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// joinPullRequestHistory matches merged pull requests to their merge commits
+// on the default branch, taking the merge time from git when the export has
+// none. With path filters, only pull requests whose merge commit (or the
+// branch it merged) touches the paths are kept.
+func joinPullRequestHistory(repo *git.Repository, index *commitIndex, prs []PullRequestReview, pathFilters []string) ([]PullRequestReview, error) {
+	var joined []PullRequestReview
+	for _, pr := range prs {
+		var landing *object.Commit
+		if pr.MergeHash.IsZero() && pr.MergeRef != "" {
+			if hash, err := repo.ResolveRevision(plumbing.Revision(pr.MergeRef)); err == nil {
+				pr.MergeHash = *hash
+			}
+		}
+		if !pr.MergeHash.IsZero() {
+			if _, ok := index.landedTime(pr.MergeHash); ok {
+				c, err := repo.CommitObject(pr.MergeHash)
+				if err != nil {
+					return nil, err
+				}
+				landing, pr.Landed = c, true
+				if pr.Merged.IsZero() {
+					pr.Merged = c.Committer.When
+				}
+			}
+		}
+
+		if len(pathFilters) > 0 {
+			if landing == nil {
+				continue
+			}
+			var branch []*object.Commit
+			if pos, ok := index.position[landing.Hash]; ok && landing.NumParents() > 1 {
+				for _, hash := range index.landedCommits(pos) {
+					c, err := repo.CommitObject(hash)
+					if err != nil {
+						return nil, err
+					}
+					if c.NumParents() <= 1 {
+						branch = append(branch, c)
+					}
+				}
+			}
+			if !pullRequestAffectsPath(landing, branch, pathFilters) {
+				continue
+			}
+		}
+		joined = append(joined, pr)
+	}
+	return joined, nil
+}
+
+// calculateReviewerLoad summarizes each reviewer's reviews, most pull requests
+// reviewed first
+func calculateReviewerLoad(prs []PullRequestReview) []ReviewerLoad {
+	loads := make(map[string]*ReviewerLoad)
+	responses := make(map[string][]float64)
+	load := func(reviewer string) *ReviewerLoad {
+		if loads[reviewer] == nil {
+			loads[reviewer] = &ReviewerLoad{Reviewer: reviewer}
+		}
+		return loads[reviewer]
+	}
+
+	totalReviewed := 0
+	for _, pr := range prs {
+		first := make(map[string]time.Time)
+		reviewed := make(map[string]bool)
+		for _, r := range pr.Reviews {
+			if r.Reviewer == pr.Author {
+				continue
+			}
+			l := load(r.Reviewer)
+			l.Reviews++
+			if r.State == "APPROVED" {
+				l.Approvals++
+			}
+			if !reviewed[r.Reviewer] {
+				reviewed[r.Reviewer] = true
+				l.PullRequests++
+				totalReviewed++
+			}
+			if !r.Time.IsZero() && (first[r.Reviewer].IsZero() || r.Time.Before(first[r.Reviewer])) {
+				first[r.Reviewer] = r.Time
+			}
+		}
+		for reviewer, t := range first {
+			responses[reviewer] = append(responses[reviewer], t.Sub(pr.Opened).Hours())
+		}
+
+		for _, reviewer := range pr.Requested {
+			if reviewer == pr.Author {
+				continue
+			}
+			l := load(reviewer)
+			l.Requested++
+			if pr.State == prStateOpen && !reviewed[reviewer] {
+				l.Pending++
+			}
+		}
+		// Anyone who reviewed was effectively asked to
+		for reviewer := range reviewed {
+			if !slices.Contains(pr.Requested, reviewer) {
+				load(reviewer).Requested++
+			}
+		}
+	}
+
+	var result []ReviewerLoad
+	for reviewer, l := range loads {
+		l.MedianResponseHours = calculatePercentile(responses[reviewer], 50)
+		if totalReviewed > 0 {
+			l.Share = float64(l.PullRequests) / float64(totalReviewed)
+		}
+		result = append(result, *l)
+	}
+
+	active := 0
+	for _, l := range result {
+		if l.PullRequests > 0 {
+			active++
+		}
+	}
+	if active > 1 {
+		for i := range result {
+			others := float64(totalReviewed-result[i].PullRequests) / float64(active-1)
+			result[i].Overloaded = float64(result[i].PullRequests) >= overloadedReviewerFactor*others
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PullRequests != result[j].PullRequests {
+			return result[i].PullRequests > result[j].PullRequests
+		}
+		if result[i].Pending != result[j].Pending {
+			return result[i].Pending > result[j].Pending
+		}
+		return result[i].Reviewer < result[j].Reviewer
+	})
+	return result
+}
+
+// calculateReviewBottleneckStats measures review wait times and reviewer load
+func calculateReviewBottleneckStats(prs []PullRequestReview) *ReviewBottleneckStats {
+	stats := &ReviewBottleneckStats{PullRequests: len(prs), Classification: "Unknown"}
+
+	var firstReview, approval, merge []float64
+	for _, pr := range prs {
+		switch pr.State {
+		case prStateMerged:
+			stats.Merged++
+			if !pr.Landed {
+				stats.NotLanded++
+			}
+			if !pr.IsReviewed() {
+				stats.MergedUnreviewed++
+			}
+			if !pr.Merged.IsZero() {
+				merge = append(merge, pr.Merged.Sub(pr.Opened).Hours())
+			}
+		case prStateOpen:
+			stats.Open++
+			stats.Stuck = append(stats.Stuck, pr)
+		default:
+			stats.Closed++
+		}
+
+		if pr.IsReviewed() {
+			stats.Reviewed++
+		}
+		if !pr.FirstReview.IsZero() {
+			firstReview = append(firstReview, pr.FirstReviewHours())
+			stats.SlowestFirstReview = append(stats.SlowestFirstReview, pr)
+		}
+		if !pr.Approved.IsZero() {
+			approval = append(approval, pr.Approved.Sub(pr.Opened).Hours())
+		}
+	}
+
+	if len(firstReview) > 0 {
+		stats.MedianFirstReviewHours = calculatePercentile(firstReview, 50)
+		stats.P95FirstReviewHours = calculatePercentile(firstReview, 95)
+		stats.Classification = classifyFirstReviewTime(stats.MedianFirstReviewHours)
+	}
+	stats.MedianApprovalHours = calculatePercentile(approval, 50)
+	stats.MedianMergeHours = calculatePercentile(merge, 50)
+	stats.Reviewers = calculateReviewerLoad(prs)
+
+	sort.SliceStable(stats.Stuck, func(i, j int) bool {
+		return stats.Stuck[i].Opened.Before(stats.Stuck[j].Opened)
+	})
+	sort.SliceStable(stats.SlowestFirstReview, func(i, j int) bool {
+		return stats.SlowestFirstReview[i].FirstReviewHours() > stats.SlowestFirstReview[j].FirstReviewHours()
+	})
+	return stats
+}
+
+// printReviewBottleneckStats displays the review bottleneck analysis
+func printReviewBottleneckStats(stats *ReviewBottleneckStats, now time.Time, limit int) {
+	fmt.Println("Review Bottlenecks Analysis")
+	fmt.Println()
+
+	if stats.PullRequests == 0 {
+		fmt.Println("No pull requests in the export match this scope.")
+		return
+	}
+
+	fmt.Printf("Pull requests: %d (%d merged, %d open, %d closed)\n", stats.PullRequests, stats.Merged, stats.Open, stats.Closed)
+	fmt.Printf("Reviewed: %d (%.1f%%)\n", stats.Reviewed, float64(stats.Reviewed)/float64(stats.PullRequests)*100)
+	if stats.MergedUnreviewed > 0 {
+		fmt.Printf("Merged without review: %d\n", stats.MergedUnreviewed)
+	}
+	if stats.NotLanded > 0 {
+		fmt.Printf("Merged but not on the default branch: %d (merge commit missing from history)\n", stats.NotLanded)
+	}
+	fmt.Println()
+
+	if len(stats.SlowestFirstReview) > 0 {
+		fmt.Println("Time to First Review:")
+		fmt.Printf("  Median: %s\n", formatElapsed(hoursDuration(stats.MedianFirstReviewHours)))
+		fmt.Printf("  95th percentile: %s\n", formatElapsed(hoursDuration(stats.P95FirstReviewHours)))
+		fmt.Printf("  Classification: %s\n", stats.Classification)
+		if stats.MedianApprovalHours > 0 {
+			fmt.Printf("Median time to approval: %s\n", formatElapsed(hoursDuration(stats.MedianApprovalHours)))
+		}
+	}
+	if stats.MedianMergeHours > 0 {
+		fmt.Printf("Median time to merge: %s\n", formatElapsed(hoursDuration(stats.MedianMergeHours)))
+	}
+	fmt.Println()
+
+	if len(stats.Reviewers) > 0 {
+		fmt.Printf("Review Load (top %d of %d reviewers):\n", min(len(stats.Reviewers), limit), len(stats.Reviewers))
+		for i, l := range stats.Reviewers {
+			if i >= limit {
+				break
+			}
+			marker := ""
+			if l.Overloaded {
+				marker = " ⚠ overloaded"
+			}
+			response := "-"
+			if l.PullRequests > 0 && l.MedianResponseHours > 0 {
+				response = formatElapsed(hoursDuration(l.MedianResponseHours))
+			}
+			fmt.Printf("  %-20s %3d PRs (%4.1f%%), %3d approvals, %3d pending, median response %s%s\n", l.Reviewer, l.PullRequests, l.Share*100, l.Approvals, l.Pending, response, marker)
+		}
+		fmt.Println()
+	}
+
+	if len(stats.Stuck) > 0 {
+		fmt.Printf("Stuck Longest (%d open):\n", len(stats.Stuck))
+		for i, pr := range stats.Stuck {
+			if i >= limit {
+				break
+			}
+			waiting := pr.WaitingOn()
+			if waiting == "first review" && len(pr.Requested) > 0 {
+				waiting += " from " + strings.Join(pr.Requested, ", ")
+			}
+			fmt.Printf("  #%d %s (%s)\n", pr.Number, pr.Title, pr.Author)
+			fmt.Printf("    Open %s, waiting on %s\n", formatElapsed(now.Sub(pr.Opened)), waiting)
+		}
+		fmt.Println()
+	}
+
+	if len(stats.SlowestFirstReview) > 0 {
+		fmt.Printf("Slowest to First Review (showing %d):\n", min(len(stats.SlowestFirstReview), limit))
+		for i, pr := range stats.SlowestFirstReview {
+			if i >= limit {
+				break
+			}
+			fmt.Printf("  #%d %s: %s\n", pr.Number, pr.Title, formatElapsed(hoursDuration(pr.FirstReviewHours())))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("Benchmarks: first review within 4 hours is Fast, within a business day Healthy, within 3 days Slow, longer is a Bottleneck.")
+	for _, l := range stats.Reviewers {
+		if l.Overloaded {
+			fmt.Println("Recommendation: spread reviews beyond the overloaded reviewers (CODEOWNERS groups, review rotation).")
+			break
+		}
+	}
+}
+
+// reviewBottlenecksCmd represents the review-bottlenecks command
+var reviewBottlenecksCmd = &cobra.Command{
+	Use:   "review-bottlenecks",
+	Short: "Analyze review wait times and reviewer load from a pull request export",
+	Long: `Analyze how long pull requests wait for review, using a JSON export of
+pull requests instead of calling the GitHub or GitLab API.
+
+The export (--export) is a JSON array of pull requests in GitHub REST, GitHub
+CLI (gh pr list --json) or GitLab merge request schema. Each pull request
+needs a number and an opening time; reviews, approvals, requested reviewers,
+the merge time and the merge commit are used when present.
+
+Merged pull requests are joined with git history by merge commit hash, so
+--path keeps only pull requests whose changes touch the given paths.
+
+Reports time to first review (median and p95), time to approval and merge,
+review load per reviewer, the open pull requests stuck longest and those
+that waited longest for a first review.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lastArg, _ := cmd.Flags().GetString("last")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		exportArg, _ := cmd.Flags().GetString("export")
+		if exportArg == "" {
+			exportArg = viper.GetString("review-bottlenecks.export")
+		}
+		if exportArg == "" {
+			return fmt.Errorf("--export is required: a JSON export of pull requests")
+		}
+
+		pathFilters, source := getConfigPaths(cmd, "review-bottlenecks.paths")
+
+		// Print configuration scope
+		printCommandScope(cmd, "review-bottlenecks", lastArg, pathFilters, source)
+
+		data, err := os.ReadFile(exportArg)
+		if err != nil {
+			return fmt.Errorf("could not read pull request export: %v", err)
+		}
+		prs, err := parsePullRequestExport(data)
+		if err != nil {
+			return fmt.Errorf("could not parse pull request export %s: %v", exportArg, err)
+		}
+
+		if lastArg != "" {
+			since, err := parseDurationArg(lastArg)
+			if err != nil {
+				return fmt.Errorf("invalid time window '%s': %v", lastArg, err)
+			}
+			var recent []PullRequestReview
+			for _, pr := range prs {
+				if pr.State == prStateOpen || !pr.Opened.Before(since) {
+					recent = append(recent, pr)
+				}
+			}
+			prs = recent
+		}
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			return fmt.Errorf("could not open repository: %v", err)
+		}
+		defaultBranch, err := getDefaultBranch(repo)
+		if err != nil {
+			return fmt.Errorf("failed to get default branch: %v", err)
+		}
+		index, err := buildCommitIndex(repo, defaultBranch.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to index commits: %v", err)
+		}
+		prs, err = joinPullRequestHistory(repo, index, prs, pathFilters)
+		if err != nil {
+			return fmt.Errorf("failed to join pull requests with history: %v", err)
+		}
+
+		now := time.Now()
+		stats := calculateReviewBottleneckStats(prs)
+		printReviewBottleneckStats(stats, now, limitArg)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewBottlenecksCmd)
+	reviewBottlenecksCmd.Flags().String("export", "", "JSON export of pull requests (GitHub or GitLab schema)")
+	reviewBottlenecksCmd.Flags().String("last", "", "Only include pull requests opened in this window, plus all open ones (e.g., 30d, 6m, 1y)")
+	reviewBottlenecksCmd.Flags().Int("limit", 10, "Number of reviewers and pull requests to show")
+	reviewBottlenecksCmd.Flags().StringSlice("path", []string{}, "Only include pull requests whose merge commit touches these paths (can be specified multiple times)")
+}
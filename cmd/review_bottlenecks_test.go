@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// githubExport is a trimmed GitHub REST export: pulls with their reviews
+const githubExport = `[
+  {
+    "number": 12, "title": "Add feature", "state": "closed",
+    "user": {"login": "bob"},
+    "created_at": "2025-01-01T09:00:00Z", "merged_at": "2025-01-03T09:00:00Z",
+    "merge_commit_sha": "61a0c6d6e1e3a5b7f0c2d4e6f8a0b2c4d6e8f0a2",
+    "requested_reviewers": [{"login": "dave"}],
+    "reviews": [
+      {"user": {"login": "bob"}, "state": "COMMENTED", "submitted_at": "2025-01-01T10:00:00Z"},
+      {"user": {"login": "alice"}, "state": "COMMENTED", "submitted_at": "2025-01-02T09:00:00Z"},
+      {"user": {"login": "alice"}, "state": "APPROVED", "submitted_at": "2025-01-02T15:00:00Z"},
+      {"user": {"login": "carol"}, "state": "PENDING"}
+    ]
+  },
+  {
+    "number": 14, "title": "Refactor", "state": "open",
+    "user": {"login": "bob"}, "created_at": "2025-01-05T09:00:00Z",
+    "merge_commit_sha": "0000000000000000000000000000000000000001",
+    "requested_reviewers": [{"login": "dave"}]
+  }
+]`
+
+// ghExport is the output of gh pr list --json number,title,author,state,createdAt,mergedAt,mergeCommit,reviews,reviewRequests
+const ghExport = `[
+  {
+    "number": 7, "title": "Tune cache", "state": "MERGED",
+    "author": {"login": "erin"},
+    "createdAt": "2025-02-01T08:00:00Z", "mergedAt": "2025-02-01T12:00:00Z",
+    "mergeCommit": {"oid": "45e84810c2d4e6f8a0b2c4d6e8f0a2b4c6d8e0f1"},
+    "reviewRequests": [{"login": "frank"}],
+    "reviews": [{"author": {"login": "frank"}, "state": "APPROVED", "submittedAt": "2025-02-01T10:00:00Z"}]
+  }
+]`
+
+// gitlabExport is a GitLab merge request export with approvals merged in
+const gitlabExport = `[
+  {
+    "iid": 31, "title": "Drop legacy endpoint", "state": "merged",
+    "author": {"username": "gina"},
+    "created_at": "2025-03-01T08:00:00.000Z", "merged_at": "2025-03-04T08:00:00.000Z",
+    "merge_commit_sha": null, "squash_commit_sha": "45e84810c2d4e6f8a0b2c4d6e8f0a2b4c6d8e0f1",
+    "reviewers": [{"username": "hank"}],
+    "approved_by": [{"user": {"username": "hank"}}]
+  },
+  {
+    "iid": 32, "title": "Abandoned", "state": "closed",
+    "author": {"username": "gina"},
+    "created_at": "2025-03-02T08:00:00Z", "closed_at": "2025-03-03T08:00:00Z"
+  }
+]`
+
+func TestClassifyFirstReviewTime(t *testing.T) {
+	tests := []struct {
+		hours    float64
+		expected string
+	}{
+		{1, "Fast"},
+		{4, "Healthy"},
+		{23, "Healthy"},
+		{24, "Slow"},
+		{71, "Slow"},
+		{72, "Bottleneck"},
+	}
+
+	for _, tt := range tests {
+		if result := classifyFirstReviewTime(tt.hours); result != tt.expected {
+			t.Errorf("classifyFirstReviewTime(%.0f) = %s, want %s", tt.hours, result, tt.expected)
+		}
+	}
+}
+
+func TestParsePullRequestExportGitHub(t *testing.T) {
+	prs, err := parsePullRequestExport([]byte(githubExport))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(prs))
+	}
+
+	merged := prs[0]
+	if merged.State != prStateMerged || merged.Author != "bob" || merged.MergeHash.IsZero() {
+		t.Errorf("Unexpected merged pull request %+v", merged)
+	}
+	// The author's own comment and the pending review don't count
+	if len(merged.Reviews) != 3 || merged.FirstReviewHours() != 24 {
+		t.Errorf("Expected alice's first review after 24h, got %v (%d reviews)", merged.FirstReviewHours(), len(merged.Reviews))
+	}
+	if merged.Approved.Sub(merged.Opened) != 30*time.Hour {
+		t.Errorf("Expected approval after 30h, got %v", merged.Approved.Sub(merged.Opened))
+	}
+
+	open := prs[1]
+	if open.State != prStateOpen || !open.MergeHash.IsZero() {
+		t.Errorf("Expected an open pull request without a merge commit, got %+v", open)
+	}
+	if open.WaitingOn() != "first review" || len(open.Requested) != 1 {
+		t.Errorf("Expected the open pull request to wait on dave, got %+v", open)
+	}
+}
+
+func TestParsePullRequestExportGitHubCLI(t *testing.T) {
+	prs, err := parsePullRequestExport([]byte(ghExport))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pr := prs[0]
+	if pr.State != prStateMerged || pr.Author != "erin" || pr.MergeHash.String() != "45e84810c2d4e6f8a0b2c4d6e8f0a2b4c6d8e0f1" {
+		t.Errorf("Unexpected pull request %+v", pr)
+	}
+	if pr.FirstReviewHours() != 2 || pr.Approved.IsZero() {
+		t.Errorf("Expected an approving first review after 2h, got %+v", pr)
+	}
+}
+
+func TestParsePullRequestExportGitLab(t *testing.T) {
+	prs, err := parsePullRequestExport([]byte(gitlabExport))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mr := prs[0]
+	if mr.Number != 31 || mr.State != prStateMerged || mr.MergeHash.IsZero() {
+		t.Errorf("Expected merge request 31 merged by its squash commit, got %+v", mr)
+	}
+	// Approvals without timestamps count as reviews but give no wait time
+	if !mr.IsReviewed() || !mr.FirstReview.IsZero() {
+		t.Errorf("Expected an untimed review, got %+v", mr)
+	}
+	if prs[1].State != prStateClosed {
+		t.Errorf("Expected merge request 32 closed, got %s", prs[1].State)
+	}
+}
+
+func TestParsePullRequestExportErrors(t *testing.T) {
+	tests := []string{
+		`[{"title": "no number", "created_at": "2025-01-01T00:00:00Z"}]`,
+		`[{"number": 1}]`,
+		`[{"number": 1, "created_at": "soon"}]`,
+		`{"number": 1}`,
+	}
+
+	for _, data := range tests {
+		if _, err := parsePullRequestExport([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestJoinPullRequestHistoryMergeRefs(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newTestRepo(t)
+	testRepoCommit(t, repo, map[string]string{"main.go": "package main\n"}, "alice", start)
+	head := testRepoCommit(t, repo, map[string]string{"main.go": "package main\n\nfunc main() {}\n"}, "bob", start.Add(time.Hour))
+
+	export := fmt.Sprintf(`[
+  {"number": 1, "state": "merged", "created_at": "2025-01-01T00:00:00Z", "merge_commit_sha": %q},
+  {"number": 2, "state": "merged", "created_at": "2025-01-01T00:00:00Z", "merge_commit_sha": "not-a-sha"}
+]`, head.String()[:7])
+	prs, err := parsePullRequestExport([]byte(export))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Neither value is a full hash, so neither is guessed at parse time
+	for _, pr := range prs {
+		if !pr.MergeHash.IsZero() || pr.MergeRef == "" {
+			t.Errorf("Expected pull request %d to keep its merge ref unresolved, got %+v", pr.Number, pr)
+		}
+	}
+
+	index, err := buildCommitIndex(repo, head)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	joined, err := joinPullRequestHistory(repo, index, prs, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if joined[0].MergeHash != head || !joined[0].Landed || !joined[0].Merged.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected the abbreviated sha to resolve to HEAD, got %+v", joined[0])
+	}
+	if !joined[1].MergeHash.IsZero() || joined[1].Landed {
+		t.Errorf("Expected the malformed sha not to join, got %+v", joined[1])
+	}
+}
+
+func TestCalculateReviewerLoad(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	hour := func(n int) time.Time { return start.Add(time.Duration(n) * time.Hour) }
+	review := func(reviewer string, h int) PullRequestReviewEvent {
+		return PullRequestReviewEvent{Reviewer: reviewer, State: "APPROVED", Time: hour(h)}
+	}
+	prs := []PullRequestReview{
+		{Author: "bob", State: prStateMerged, Opened: hour(0), Reviews: []PullRequestReviewEvent{review("alice", 2)}},
+		{Author: "bob", State: prStateMerged, Opened: hour(0), Reviews: []PullRequestReviewEvent{review("alice", 4), review("alice", 5)}},
+		{Author: "carol", State: prStateMerged, Opened: hour(0), Reviews: []PullRequestReviewEvent{review("alice", 6)}},
+		{Author: "alice", State: prStateMerged, Opened: hour(0), Reviews: []PullRequestReviewEvent{review("dave", 8)}},
+		{Author: "alice", State: prStateOpen, Opened: hour(0), Requested: []string{"dave", "erin"}},
+	}
+
+	loads := calculateReviewerLoad(prs)
+	if len(loads) != 3 {
+		t.Fatalf("Expected 3 reviewers, got %+v", loads)
+	}
+	alice := loads[0]
+	if alice.Reviewer != "alice" || alice.PullRequests != 3 || alice.Reviews != 4 || alice.MedianResponseHours != 4 {
+		t.Errorf("Unexpected load for alice: %+v", alice)
+	}
+	// alice reviews three pull requests for every one dave reviews
+	if !alice.Overloaded || alice.Share != 0.75 {
+		t.Errorf("Expected alice to be overloaded at 75%%, got %+v", alice)
+	}
+	dave := loads[1]
+	if dave.Reviewer != "dave" || dave.Pending != 1 || dave.Requested != 2 || dave.Overloaded {
+		t.Errorf("Unexpected load for dave: %+v", dave)
+	}
+	if erin := loads[2]; erin.PullRequests != 0 || erin.Pending != 1 {
+		t.Errorf("Unexpected load for erin: %+v", erin)
+	}
+}
+
+func TestCalculateReviewBottleneckStats(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	hour := func(n int) time.Time { return start.Add(time.Duration(n) * time.Hour) }
+	prs := []PullRequestReview{
+		{Number: 1, Author: "bob", State: prStateMerged, Landed: true, Opened: hour(0), FirstReview: hour(2), Approved: hour(3), Merged: hour(4)},
+		{Number: 2, Author: "bob", State: prStateMerged, Opened: hour(0), FirstReview: hour(50), Merged: hour(60)},
+		{Number: 3, Author: "bob", State: prStateMerged, Landed: true, Opened: hour(0), Merged: hour(1)},
+		{Number: 4, Author: "bob", State: prStateOpen, Opened: hour(10)},
+		{Number: 5, Author: "bob", State: prStateOpen, Opened: hour(5)},
+		{Number: 6, Author: "bob", State: prStateClosed, Opened: hour(5)},
+	}
+
+	stats := calculateReviewBottleneckStats(prs)
+	if stats.Merged != 3 || stats.Open != 2 || stats.Closed != 1 || stats.Reviewed != 2 {
+		t.Errorf("Unexpected counts %+v", stats)
+	}
+	if stats.MergedUnreviewed != 1 || stats.NotLanded != 1 {
+		t.Errorf("Expected one unreviewed and one unlanded merge, got %d and %d", stats.MergedUnreviewed, stats.NotLanded)
+	}
+	if stats.MedianFirstReviewHours != 26 || stats.Classification != "Slow" {
+		t.Errorf("Expected a Slow 26h median first review, got %.1fh %s", stats.MedianFirstReviewHours, stats.Classification)
+	}
+	if stats.MedianMergeHours != 4 {
+		t.Errorf("Expected a 4h median time to merge, got %.1f", stats.MedianMergeHours)
+	}
+	if len(stats.Stuck) != 2 || stats.Stuck[0].Number != 5 {
+		t.Errorf("Expected #5 stuck longest, got %+v", stats.Stuck)
+	}
+	if stats.SlowestFirstReview[0].Number != 2 {
+		t.Errorf("Expected #2 slowest to first review, got #%d", stats.SlowestFirstReview[0].Number)
+	}
+}
+
+func TestCalculateReviewBottleneckStatsEmpty(t *testing.T) {
+	stats := calculateReviewBottleneckStats(nil)
+	if stats.PullRequests != 0 || stats.Classification != "Unknown" {
+		t.Errorf("Unexpected stats for no pull requests: %+v", stats)
+	}
+}
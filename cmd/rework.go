@@ -0,0 +1,418 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	diff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+const defaultReworkDays = 21
+
+const reworkBenchmarkContext = "Some rework is normal iteration; sustained high rework points to unclear requirements, rushed reviews or thrash."
+
+// ReworkStats counts lines added and lines reworked for one file, directory or author.
+// A line is reworked when it is modified or deleted within the rework window of being written.
+type ReworkStats struct {
+	Name          string
+	Added         int
+	Reworked      int
+	ReworkPercent float64
+}
+
+// ReworkReport summarizes rework across the analysis window
+type ReworkReport struct {
+	Days          int
+	Added         int
+	Reworked      int
+	Maturing      int // lines written less than Days ago, which may still be reworked
+	ReworkPercent float64
+	Files         []ReworkStats
+	Directories   []ReworkStats
+	Authors       []ReworkStats
+}
+
+// reworkLine is a tracked line added in the analysis window
+type reworkLine struct {
+	Path    string // path the line was added under
+	Author  string
+	AddedAt time.Time
+}
+
+// reworkTracker follows lines from the commit that adds them to the commit
+// that modifies or deletes them. Lines are matched by their current path and
+// content hash (see makeKey), and follow their file across renames.
+type reworkTracker struct {
+	window   time.Duration
+	now      time.Time
+	lines    map[string]map[string][]reworkLine // current path -> line hash -> lines, oldest first
+	files    map[string]*ReworkStats
+	dirs     map[string]*ReworkStats
+	authors  map[string]*ReworkStats
+	maturing int
+}
+
+// newReworkTracker returns a tracker that counts changes within days of a line being written
+func newReworkTracker(days int, now time.Time) *reworkTracker {
+	return &reworkTracker{
+		window:  time.Duration(days) * 24 * time.Hour,
+		now:     now,
+		lines:   make(map[string]map[string][]reworkLine),
+		files:   make(map[string]*ReworkStats),
+		dirs:    make(map[string]*ReworkStats),
+		authors: make(map[string]*ReworkStats),
+	}
+}
+
+// statsFor returns the counters for a tracked line's file, directory and author
+func (t *reworkTracker) statsFor(line reworkLine) []*ReworkStats {
+	groups := []struct {
+		m    map[string]*ReworkStats
+		name string
+	}{
+		{t.files, line.Path},
+		{t.dirs, reportDirectory(line.Path)},
+		{t.authors, line.Author},
+	}
+
+	stats := make([]*ReworkStats, 0, len(groups))
+	for _, g := range groups {
+		s, ok := g.m[g.name]
+		if !ok {
+			s = &ReworkStats{Name: g.name}
+			g.m[g.name] = s
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// add starts tracking a line written at when
+func (t *reworkTracker) add(path, content, author string, when time.Time) {
+	line := reworkLine{Path: path, Author: author, AddedAt: when}
+	if t.lines[path] == nil {
+		t.lines[path] = make(map[string][]reworkLine)
+	}
+	hash := hashLine(content)
+	t.lines[path][hash] = append(t.lines[path][hash], line)
+
+	for _, s := range t.statsFor(line) {
+		s.Added++
+	}
+	if t.now.Sub(when) < t.window {
+		t.maturing++
+	}
+}
+
+// remove records a line being modified or deleted at when. Identical lines in
+// the same file are indistinguishable, so the oldest one is taken; lines that
+// were never tracked (written before the window) are ignored.
+func (t *reworkTracker) remove(path, content string, when time.Time) {
+	hash := hashLine(content)
+	tracked := t.lines[path][hash]
+	if len(tracked) == 0 {
+		return
+	}
+
+	line := tracked[0]
+	if len(tracked) == 1 {
+		delete(t.lines[path], hash)
+	} else {
+		t.lines[path][hash] = tracked[1:]
+	}
+
+	if when.Sub(line.AddedAt) <= t.window {
+		for _, s := range t.statsFor(line) {
+			s.Reworked++
+		}
+	}
+}
+
+// rename moves the tracked lines of a file to its new path
+func (t *reworkTracker) rename(from, to string) {
+	moved, ok := t.lines[from]
+	if !ok {
+		return
+	}
+	delete(t.lines, from)
+	if existing, ok := t.lines[to]; ok {
+		for hash, lines := range moved {
+			existing[hash] = append(existing[hash], lines...)
+		}
+		return
+	}
+	t.lines[to] = moved
+}
+
+// processCommit applies one commit's rename-aware diffs to the tracker.
+// Removals are applied before additions, so a modified line counts as
+// reworked and its new version starts a fresh rework window.
+func (t *reworkTracker) processCommit(c *object.Commit, pathFilters []string) error {
+	patches, err := commitRenamePatches(c)
+	if err != nil {
+		return err
+	}
+
+	type lineRef struct{ path, content string }
+	removed := make(map[lineRef]int)
+	added := make(map[lineRef]int)
+	for _, patch := range patches {
+		for _, fp := range patch.FilePatches() {
+			if fp.IsBinary() {
+				continue
+			}
+			from, to := fp.Files()
+			if from != nil && to != nil && from.Path() != to.Path() {
+				t.rename(from.Path(), to.Path())
+			}
+			for _, chunk := range fp.Chunks() {
+				switch chunk.Type() {
+				case diff.Delete:
+					// Renames were applied above, so removals use the new path when there is one
+					path := from.Path()
+					if to != nil {
+						path = to.Path()
+					}
+					for _, l := range strings.Split(chunk.Content(), "\n") {
+						if !isEmptyLine(l) {
+							removed[lineRef{path, l}]++
+						}
+					}
+				case diff.Add:
+					if !matchesPathFilter(to.Path(), pathFilters) {
+						continue
+					}
+					for _, l := range strings.Split(chunk.Content(), "\n") {
+						if !isEmptyLine(l) {
+							added[lineRef{to.Path(), l}]++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// A merge diffed against several parents divides its counts across them (see merges.go)
+	when := c.Committer.When
+	for ref, count := range removed {
+		_, adjusted := applyMergeCommitAdjustment(0, count, len(patches))
+		for i := 0; i < adjusted; i++ {
+			t.remove(ref.path, ref.content, when)
+		}
+	}
+	author := normalizeAuthorName(c.Author.Name, c.Author.Email)
+	for ref, count := range added {
+		adjusted, _ := applyMergeCommitAdjustment(count, 0, len(patches))
+		for i := 0; i < adjusted; i++ {
+			t.add(ref.path, ref.content, author, when)
+		}
+	}
+	return nil
+}
+
+// sortedReworkStats returns the stats ordered by reworked lines, then rework percentage
+func sortedReworkStats(m map[string]*ReworkStats) []ReworkStats {
+	stats := make([]ReworkStats, 0, len(m))
+	for _, s := range m {
+		s.ReworkPercent = calculateReworkPercent(s.Reworked, s.Added)
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Reworked != stats[j].Reworked {
+			return stats[i].Reworked > stats[j].Reworked
+		}
+		if stats[i].ReworkPercent != stats[j].ReworkPercent {
+			return stats[i].ReworkPercent > stats[j].ReworkPercent
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// calculateReworkPercent returns reworked lines as a percentage of added lines
+func calculateReworkPercent(reworked, added int) float64 {
+	if added == 0 {
+		return 0
+	}
+	return float64(reworked) / float64(added) * 100
+}
+
+// report summarizes what the tracker has seen
+func (t *reworkTracker) report() *ReworkReport {
+	report := &ReworkReport{
+		Days:        int(t.window / (24 * time.Hour)),
+		Maturing:    t.maturing,
+		Files:       sortedReworkStats(t.files),
+		Directories: sortedReworkStats(t.dirs),
+		Authors:     sortedReworkStats(t.authors),
+	}
+	for _, s := range report.Authors {
+		report.Added += s.Added
+		report.Reworked += s.Reworked
+	}
+	report.ReworkPercent = calculateReworkPercent(report.Reworked, report.Added)
+	return report
+}
+
+// analyzeRework replays the window's commits oldest first and tracks every line they add
+func analyzeRework(repo *git.Repository, since time.Time, pathFilters []string, days int) (*ReworkReport, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD: %v", err)
+	}
+
+	cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	defer cIter.Close()
+
+	var commits []*object.Commit
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if !since.IsZero() && c.Committer.When.Before(since) {
+			return nil
+		}
+		if includeMergeCommit(c) {
+			commits = append(commits, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking commits: %v", err)
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Committer.When.Before(commits[j].Committer.When)
+	})
+
+	tracker := newReworkTracker(days, time.Now())
+	for _, c := range commits {
+		if err := tracker.processCommit(c, pathFilters); err != nil {
+			log.Printf("failed to diff commit %s: %v", c.Hash.String(), err)
+		}
+	}
+	return tracker.report(), nil
+}
+
+// printReworkTable prints one breakdown table
+func printReworkTable(title, column string, stats []ReworkStats, limit int) {
+	fmt.Printf("\nTop %d %s by rework:\n", limit, title)
+	fmt.Printf("%-50s %8s %9s %8s\n", column, "Added", "Reworked", "Rework %")
+	fmt.Printf("%-50s %8s %9s %8s\n", strings.Repeat("-", 50), "-----", "--------", "--------")
+
+	shown := 0
+	for _, s := range stats {
+		if shown >= limit || s.Reworked == 0 {
+			break
+		}
+		fmt.Printf("%-50s %8d %9d %7.1f%%\n", s.Name, s.Added, s.Reworked, s.ReworkPercent)
+		shown++
+	}
+	if shown == 0 {
+		fmt.Println("(no rework)")
+	}
+}
+
+// printReworkReport prints the summary and the file, directory and author breakdowns
+func printReworkReport(report *ReworkReport, limit int) {
+	if report.Added == 0 {
+		fmt.Println("No lines added in the specified window.")
+		return
+	}
+
+	fmt.Printf("Rework (lines modified or deleted within %d days of being written):\n", report.Days)
+	fmt.Printf("- Lines added: %d\n", report.Added)
+	fmt.Printf("- Reworked: %d (%.1f%%)\n", report.Reworked, report.ReworkPercent)
+	if report.Maturing > 0 {
+		fmt.Printf("- Still maturing: %d lines written in the last %d days may yet be reworked\n", report.Maturing, report.Days)
+	}
+
+	printReworkTable("files", "File", report.Files, limit)
+	printReworkTable("directories", "Directory", report.Directories, limit)
+	printReworkTable("authors", "Author", report.Authors, limit)
+
+	fmt.Println()
+	fmt.Println("Context:", reworkBenchmarkContext)
+}
+
+// reworkCmd represents the rework command
+var reworkCmd = &cobra.Command{
+	Use:   "rework",
+	Short: "Measure code rewritten shortly after being written",
+	Long: `Track every line added in the time window and report how many were
+modified or deleted within N days (default 21) of being written.
+
+Raw churn can't tell healthy refactoring of old code from thrash on new code;
+rework only counts changes to fresh lines. Results are broken down by file,
+directory and the author who wrote the line. Lines follow their file across
+renames; identical lines in one file are matched oldest first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Parse flags
+		lastArg, _ := cmd.Flags().GetString("last")
+		pathFilters, source := getConfigPaths(cmd, "rework.paths")
+		daysArg, _ := cmd.Flags().GetInt("days")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		if daysArg <= 0 {
+			log.Fatalf("--days must be positive, got %d", daysArg)
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "rework", lastArg, pathFilters, source)
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			log.Fatalf("Could not open repository: %v", err)
+		}
+
+		since := time.Time{}
+		if lastArg != "" {
+			cutoff, err := parseDurationArg(lastArg)
+			if err != nil {
+				log.Fatalf("Could not parse --last argument: %v", err)
+			}
+			since = cutoff
+		}
+
+		report, err := analyzeRework(repo, since, pathFilters, daysArg)
+		if err != nil {
+			log.Fatalf("Error analyzing rework: %v", err)
+		}
+
+		printReworkReport(report, limitArg)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reworkCmd)
+	reworkCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
+	reworkCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+	reworkCmd.Flags().Int("days", defaultReworkDays, "Days after a line is written within which a change counts as rework")
+	reworkCmd.Flags().Int("limit", 10, "Number of top files, directories and authors to show")
+}
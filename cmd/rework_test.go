@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReworkTracker(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+
+	tracker := newReworkTracker(21, day(100))
+	tracker.add("src/a.go", "x := 1", "alice", day(0))
+	tracker.add("src/a.go", "y := 2", "alice", day(0))
+	tracker.add("src/a.go", "y := 2", "bob", day(15))
+	tracker.add("lib/b.go", "z := 3", "bob", day(0))
+
+	// Within the window: reworked
+	tracker.remove("src/a.go", "x := 1", day(10))
+	// Identical lines are taken oldest first; alice's copy is 25 days old
+	tracker.remove("src/a.go", "y := 2", day(25))
+	// Lines that were never tracked are ignored
+	tracker.remove("src/a.go", "untracked", day(1))
+	// Renamed files keep their lines
+	tracker.rename("lib/b.go", "pkg/b.go")
+	tracker.remove("pkg/b.go", "z := 3", day(21))
+
+	report := tracker.report()
+	if report.Added != 4 || report.Reworked != 2 {
+		t.Fatalf("Expected 4 added and 2 reworked, got %d and %d", report.Added, report.Reworked)
+	}
+	if report.ReworkPercent != 50 {
+		t.Errorf("Expected 50%% rework, got %.1f%%", report.ReworkPercent)
+	}
+
+	authors := make(map[string]ReworkStats)
+	for _, s := range report.Authors {
+		authors[s.Name] = s
+	}
+	if authors["alice"].Reworked != 1 || authors["bob"].Reworked != 1 {
+		t.Errorf("Expected one reworked line each for alice and bob, got %+v", authors)
+	}
+
+	// Stats stay with the path a line was written under
+	files := make(map[string]ReworkStats)
+	for _, s := range report.Files {
+		files[s.Name] = s
+	}
+	if files["lib/b.go"].Reworked != 1 {
+		t.Errorf("Expected the renamed line to count under lib/b.go, got %+v", files)
+	}
+	dirs := make(map[string]ReworkStats)
+	for _, s := range report.Directories {
+		dirs[s.Name] = s
+	}
+	if dirs["src/"].Added != 3 || dirs["src/"].Reworked != 1 {
+		t.Errorf("Expected src/ with 3 added and 1 reworked, got %+v", dirs["src/"])
+	}
+
+	// bob's remaining copy of "y := 2" is still tracked
+	if remaining := len(tracker.lines["src/a.go"][hashLine("y := 2")]); remaining != 1 {
+		t.Errorf("Expected one remaining tracked line, got %d", remaining)
+	}
+}
+
+func TestReworkTrackerMaturing(t *testing.T) {
+	now := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	tracker := newReworkTracker(21, now)
+	tracker.add("a.go", "old", "alice", now.AddDate(0, 0, -30))
+	tracker.add("a.go", "new", "alice", now.AddDate(0, 0, -5))
+
+	if report := tracker.report(); report.Maturing != 1 {
+		t.Errorf("Expected 1 maturing line, got %d", report.Maturing)
+	}
+}
@@ -41,6 +41,11 @@ to help you understand code evolution, identify risks, and optimize team workflo
 
 Analyze churn patterns, code survival rates, and other engineering metrics
 to make data-driven decisions about your codebase health.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Reject an unknown --merges value before any analysis starts
+		_, err := resolveMergePolicy(mergePolicyArg, firstParentArg)
+		return err
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -60,6 +65,8 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gitallica.yaml)")
+	rootCmd.PersistentFlags().StringVar(&mergePolicyArg, "merges", "", "How merge commits contribute changes: skip, first-parent or all (default skip, first-parent with --first-parent)")
+	rootCmd.PersistentFlags().BoolVar(&firstParentArg, "first-parent", false, "Follow only the first parent of each commit, counting each merged branch as one change")
 }
 
 // initConfig reads in config file with proper hierarchy:
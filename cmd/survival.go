@@ -36,31 +36,121 @@ const lineKeySeparator = "\x00"
 const researchNote = "Large-scale study of 3.3 billion code-line lifetimes shows median lifespan of ~2.4 years (Spinellis et al.)."
 
 func makeKey(filename, line string) string {
+	return filename + lineKeySeparator + hashLine(line)
+}
+
+// hashLine returns the SHA-256 of a line's content, the content half of makeKey
+func hashLine(line string) string {
 	sum := sha256.Sum256([]byte(line))
-	return filename + lineKeySeparator + hex.EncodeToString(sum[:])
+	return hex.EncodeToString(sum[:])
+}
+
+
+const (
+	survivalMethodBlame = "blame"
+	survivalMethodHash  = "hash"
+)
+
+// SurvivalLine is one line added in the analysis window, followed to HEAD
+type SurvivalLine struct {
+	Path      string // path the line was added under
+	Author    string
+	AddedAt   time.Time
+	RemovedAt time.Time // zero for lines still present at HEAD
+	RemovedBy string    // normalized author of the removing commit
+	Survived  bool
 }
 
+// SurvivalResult holds the outcome of a survival analysis. Lines is only
+// filled in by the blame method, which knows when each line was removed.
+type SurvivalResult struct {
+	Method   string
+	Added    int
+	Survived int
+	Percent  float64
+	Lines    []SurvivalLine
+}
 
-func printSurvivalStats(totalAdded, survived int, percent float64) {
-	fmt.Printf("Survival rate:\n")
-	fmt.Printf("  Lines added:    %d\n", totalAdded)
-	fmt.Printf("  Still present:  %d\n", survived)
-	fmt.Printf("  Survival rate:  %.2f%%\n", percent)
+// medianRemovedLifetime returns the median time removed lines lived, and how many there were
+func medianRemovedLifetime(lines []SurvivalLine) (time.Duration, int) {
+	var lifetimes []float64
+	for _, l := range lines {
+		if !l.Survived && !l.RemovedAt.IsZero() {
+			lifetimes = append(lifetimes, float64(l.RemovedAt.Sub(l.AddedAt)))
+		}
+	}
+	if len(lifetimes) == 0 {
+		return 0, 0
+	}
+	return time.Duration(calculatePercentile(lifetimes, 50)), len(lifetimes)
+}
+
+func printSurvivalStats(result *SurvivalResult) {
+	fmt.Printf("Survival rate (%s method):\n", result.Method)
+	fmt.Printf("  Lines added:    %d\n", result.Added)
+	fmt.Printf("  Still present:  %d\n", result.Survived)
+	fmt.Printf("  Survival rate:  %.2f%%\n", result.Percent)
+	if median, removed := medianRemovedLifetime(result.Lines); removed > 0 {
+		fmt.Printf("  Removed lines:  %d, median lifetime %.1f days\n", removed, median.Hours()/24)
+	}
 	fmt.Println(researchNote)
 	fmt.Println()
 }
 
+// printSurvivalMethodComparison shows how far the hash method is from the blame method
+func printSurvivalMethodComparison(blame, hash *SurvivalResult) {
+	fmt.Printf("Hash method (--method hash): %.2f%% (%d of %d lines), %+.2f points from blame\n",
+		hash.Percent, hash.Survived, hash.Added, hash.Percent-blame.Percent)
+	fmt.Println("The hash method counts moved and reformatted lines as removed and conflates identical lines in a file.")
+}
+
 // survivalCmd represents the survival command
 var survivalCmd = &cobra.Command{
 	Use:   "survival",
 	Short: "Analyze code survival rate",
 	Long: `Check how many lines survive over time compared to how many were added. 
-Helps spot unstable areas where code gets rewritten too frequently.`,
+Helps spot unstable areas where code gets rewritten too frequently.
+
+Methods:
+- blame (default): follows each added line through every later commit, across
+  renames, moves between files and re-indentation, and records when it was
+  actually removed. The hash method's result is shown alongside for comparison.
+- hash: matches added lines against HEAD by (file, content hash). Faster, but a
+  moved or reformatted line counts as removed and identical lines in a file
+  are conflated.
+
+--curves adds Kaplan–Meier survival curves for lines grouped by the month
+they were written, with each cohort's half-life (median lifetime), and the
+half-life of each directory. Lines still present are treated as censored, so
+recent cohorts aren't mistaken for immortal code.
+
+--by author|directory|extension breaks survival down by group, lowest
+survival first, flagging groups well below the overall rate. Authors are
+normalized the same way bus-factor normalizes them.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse flags
 		lastArg, _ := cmd.Flags().GetString("last")
 		pathFilters, source := getConfigPaths(cmd, "survival.paths")
 		debugArg, _ := cmd.Flags().GetBool("debug")
+		methodArg, _ := cmd.Flags().GetString("method")
+		if methodArg != survivalMethodBlame && methodArg != survivalMethodHash {
+			log.Fatalf("Invalid --method value %q (expected blame or hash)", methodArg)
+		}
+		curvesArg, _ := cmd.Flags().GetString("curves")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+		if err := validateSurvivalCurvesFormat(curvesArg); err != nil {
+			log.Fatalf("%v", err)
+		}
+		if curvesArg != "" && methodArg != survivalMethodBlame {
+			log.Fatalf("--curves needs removal times, which only --method blame records")
+		}
+		byArg, _ := cmd.Flags().GetString("by")
+		if err := validateSurvivalBy(byArg); err != nil {
+			log.Fatalf("%v", err)
+		}
+		if byArg != "" && methodArg != survivalMethodBlame {
+			log.Fatalf("--by needs per-line authors, which only --method blame records")
+		}
 		
 		// Print configuration scope
 		printCommandScope(cmd, "survival", lastArg, pathFilters, source)
@@ -88,65 +178,136 @@ Helps spot unstable areas where code gets rewritten too frequently.`,
 			log.Fatalf("Failed to get HEAD commit: %v", err)
 		}
 
-		// Map to track added lines: key = file + hash(line content), value = occurrence count
-		added := make(map[string]int)
-
-		// Iterate commits, collect all added lines after cutoff
-		commitsIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+		var result *SurvivalResult
+		if methodArg == survivalMethodHash {
+			result, err = analyzeSurvivalByHash(repo, headCommit, cutoff, pathFilters, debugArg)
+		} else {
+			result, err = analyzeSurvivalByBlame(repo, headCommit, cutoff, pathFilters)
+		}
 		if err != nil {
-			log.Fatalf("Failed to iterate commits: %v", err)
+			log.Fatalf("Survival analysis failed: %v", err)
+		}
+		if result.Added == 0 {
+			fmt.Println("No lines added in the specified window.")
+			return
+		}
+
+		if curvesArg == survivalCurvesJSON {
+			if err := printSurvivalCurvesJSON(buildSurvivalCurves(result.Lines, time.Now())); err != nil {
+				log.Fatalf("Could not encode survival curves: %v", err)
+			}
+			return
 		}
-		defer commitsIter.Close()
 
-		for {
-			commit, err := commitsIter.Next()
+		printSurvivalStats(result)
+
+		// Show what the fast method would have reported, and why it differs
+		if methodArg == survivalMethodBlame {
+			hashResult, err := analyzeSurvivalByHash(repo, headCommit, cutoff, pathFilters, debugArg)
 			if err != nil {
-				break
+				log.Fatalf("Survival analysis failed: %v", err)
+			}
+			printSurvivalMethodComparison(result, hashResult)
+		}
+
+		if byArg != "" {
+			stats := groupSurvivalStats(result.Lines, byArg, time.Now())
+			if byArg == survivalByAuthor {
+				firstCommits, err := authorFirstCommits(repo, headCommit)
+				if err != nil {
+					log.Fatalf("Could not find first commits: %v", err)
+				}
+				for i := range stats {
+					stats[i].FirstCommit = firstCommits[stats[i].Group]
+				}
 			}
-			commitTime := commit.Committer.When
+			fmt.Println()
+			printSurvivalGroups(stats, byArg, limitArg)
+		}
+
+		if curvesArg == survivalCurvesText {
+			fmt.Println()
+			printSurvivalCurves(buildSurvivalCurves(result.Lines, time.Now()), limitArg)
+		}
+	},
+}
+
+func init() {
+	survivalCmd.Flags().String("last", "", "Time window to consider (e.g. 7d, 2m, 1y)")
+	survivalCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+	survivalCmd.Flags().Bool("debug", false, "Enable debug logging for survival analysis")
+	survivalCmd.Flags().String("method", survivalMethodBlame, "How lines are followed to HEAD: blame (accurate) or hash (fast)")
+	survivalCmd.Flags().String("curves", "", "Show Kaplan–Meier survival curves by monthly cohort and directory: text or json")
+	survivalCmd.Flags().String("by", "", "Break survival down by author, directory or extension")
+	survivalCmd.Flags().Int("limit", 10, "Number of groups to show in breakdowns")
+	rootCmd.AddCommand(survivalCmd)
+}
+
+// analyzeSurvivalByHash counts lines added since cutoff and matches them by
+// (file, content hash) against HEAD. It is fast, but a line that moves to
+// another file or is reformatted counts as removed, and identical lines in one
+// file are interchangeable.
+func analyzeSurvivalByHash(repo *git.Repository, headCommit *object.Commit, cutoff time.Time, pathFilters []string, debugArg bool) (*SurvivalResult, error) {
+	// Map to track added lines: key = file + hash(line content), value = occurrence count
+	added := make(map[string]int)
+
+	// Iterate commits, collect all added lines after cutoff
+	commitsIter, err := logCommits(repo, &git.LogOptions{From: headCommit.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate commits: %v", err)
+	}
+	defer commitsIter.Close()
+
+	for {
+		commit, err := commitsIter.Next()
+		if err != nil {
+			break
+		}
+		commitTime := commit.Committer.When
+		if debugArg {
+			log.Printf("[survival] Commit %s at %v, parents: %d", commit.Hash.String(), commitTime, commit.NumParents())
+		}
+		if !cutoff.IsZero() && commitTime.Before(cutoff) {
 			if debugArg {
-				log.Printf("[survival] Commit %s at %v, parents: %d", commit.Hash.String(), commitTime, commit.NumParents())
+				log.Printf("[survival] Skipping commit %s: before cutoff", commit.Hash.String())
 			}
-			if !cutoff.IsZero() && commitTime.Before(cutoff) {
-				if debugArg {
-					log.Printf("[survival] Skipping commit %s: before cutoff", commit.Hash.String())
-				}
+			continue
+		}
+		if !includeMergeCommit(commit) {
+			if debugArg {
+				log.Printf("[survival] Skipping commit %s: merge commit", commit.Hash.String())
+			}
+			continue
+		}
+		var patches []*object.Patch
+		if commit.NumParents() > 0 {
+			if debugArg {
+				log.Printf("[survival] Generating patches for commit %s (merge policy %s)", commit.Hash.String(), activeMergePolicy())
+			}
+			patches, err = commitPatches(commit)
+			if err != nil {
 				continue
 			}
-			if commit.NumParents() > 1 {
-				if debugArg {
-					log.Printf("[survival] Skipping commit %s: merge commit", commit.Hash.String())
-				}
+		} else {
+			// Initial commit, diff with empty tree
+			if debugArg {
+				log.Printf("[survival] Generating patch for initial commit %s", commit.Hash.String())
+			}
+			emptyTree := &object.Tree{}
+			t, err := commit.Tree()
+			if err != nil {
 				continue
 			}
-			var patch *object.Patch
-			if commit.NumParents() == 1 {
-				parent, err := commit.Parent(0)
-				if err != nil {
-					continue
-				}
-				if debugArg {
-					log.Printf("[survival] Generating patch for commit %s vs parent %s", commit.Hash.String(), parent.Hash.String())
-				}
-				patch, err = parent.Patch(commit)
-				if err != nil {
-					continue
-				}
-			} else {
-				// Initial commit, diff with empty tree
-				if debugArg {
-					log.Printf("[survival] Generating patch for initial commit %s", commit.Hash.String())
-				}
-				emptyTree := &object.Tree{}
-				t, err := commit.Tree()
-				if err != nil {
-					continue
-				}
-				patch, err = emptyTree.Patch(t)
-				if err != nil {
-					continue
-				}
+			patch, err := emptyTree.Patch(t)
+			if err != nil {
+				continue
 			}
+			patches = []*object.Patch{patch}
+		}
+		// Collect this commit's added lines first; a merge diffed against
+		// several parents divides its counts across them (see merges.go)
+		commitAdded := make(map[string]int)
+		for _, patch := range patches {
 			for _, fileStat := range patch.FilePatches() {
 				from, to := fileStat.Files()
 				var filename string
@@ -188,7 +349,7 @@ Helps spot unstable areas where code gets rewritten too frequently.`,
 								continue
 							}
 							key := makeKey(filename, l)
-							added[key]++
+							commitAdded[key]++
 							if debugArg {
 								log.Printf("[survival] Added line: %q", strings.TrimSpace(l))
 							}
@@ -197,78 +358,74 @@ Helps spot unstable areas where code gets rewritten too frequently.`,
 				}
 			}
 		}
-
-		// Sum counts so duplicates are accounted for accurately
-		totalAdded := 0
-		for _, c := range added {
-			totalAdded += c
-		}
-		if debugArg {
-			log.Printf("[survival] Total added lines tracked (counted): %d", totalAdded)
-		}
-		if totalAdded == 0 {
-			fmt.Println("No lines added in the specified window.")
-			return
+		for key, count := range commitAdded {
+			adjusted, _ := applyMergeCommitAdjustment(count, 0, len(patches))
+			added[key] += adjusted
 		}
+	}
 
-		// Now, walk HEAD tree, check which lines survived
-		survived := 0
-		headTree, err := headCommit.Tree()
+	// Sum counts so duplicates are accounted for accurately
+	totalAdded := 0
+	for _, c := range added {
+		totalAdded += c
+	}
+	if debugArg {
+		log.Printf("[survival] Total added lines tracked (counted): %d", totalAdded)
+	}
+	if totalAdded == 0 {
+		return &SurvivalResult{Method: survivalMethodHash}, nil
+	}
+
+	// Now, walk HEAD tree, check which lines survived
+	survived := 0
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD tree: %v", err)
+	}
+	err = headTree.Files().ForEach(func(f *object.File) error {
+		if !matchesPathFilter(f.Name, pathFilters) {
+			return nil
+		}
+		// Only text files
+		content, err := f.Contents()
 		if err != nil {
-			log.Fatalf("Failed to get HEAD tree: %v", err)
+			return nil
 		}
-		err = headTree.Files().ForEach(func(f *object.File) error {
-			if !matchesPathFilter(f.Name, pathFilters) {
-				return nil
-			}
-			// Only text files
-			content, err := f.Contents()
-			if err != nil {
-				return nil
+		lines := strings.Split(content, "\n")
+		for _, l := range lines {
+			if isEmptyLine(l) {
+				continue
 			}
-			lines := strings.Split(content, "\n")
-			for _, l := range lines {
-				if isEmptyLine(l) {
-					continue
-				}
-				key := makeKey(f.Name, l)
-				if count, ok := added[key]; ok {
-					survived++
-					if count == 1 {
-						delete(added, key)
-					} else {
-						added[key] = count - 1
-					}
+			key := makeKey(f.Name, l)
+			if count, ok := added[key]; ok {
+				survived++
+				if count == 1 {
+					delete(added, key)
+				} else {
+					added[key] = count - 1
 				}
 			}
-			return nil
-		})
-		if err != nil {
-			log.Fatalf("Failed to walk HEAD tree: %v", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk HEAD tree: %v", err)
+	}
 
-		// Sanity check: the number of surviving lines must never exceed the number of lines added.
-		if survived > totalAdded {
-			log.Fatalf("[survival][error] Surviving lines (%d) exceed lines added (%d). This indicates a counting bug or data inconsistency.", survived, totalAdded)
-		}
-		percent := float64(survived) / float64(totalAdded) * 100
-		// A negative percentage would indicate a serious bug (should be mathematically impossible here).
-		if percent < 0 {
-			log.Fatalf("[survival][error] Negative survival rate (%.2f%%). This indicates a counting bug or data inconsistency.", percent)
-		}
+	// Sanity check: the number of surviving lines must never exceed the number of lines added.
+	if survived > totalAdded {
+		return nil, fmt.Errorf("surviving lines (%d) exceed lines added (%d); this indicates a counting bug or data inconsistency", survived, totalAdded)
+	}
+	percent := float64(survived) / float64(totalAdded) * 100
+	// A negative percentage would indicate a serious bug (should be mathematically impossible here).
+	if percent < 0 {
+		return nil, fmt.Errorf("negative survival rate (%.2f%%); this indicates a counting bug or data inconsistency", percent)
+	}
 
-		if debugArg {
-			log.Printf("[survival] Introduced: %d, Surviving: %d, Rate: %.2f%%",
-				totalAdded, survived, percent)
-		}
+	if debugArg {
+		log.Printf("[survival] Introduced: %d, Surviving: %d, Rate: %.2f%%",
+			totalAdded, survived, percent)
+	}
 
-		printSurvivalStats(totalAdded, survived, percent)
-	},
+	return &SurvivalResult{Method: survivalMethodHash, Added: totalAdded, Survived: survived, Percent: percent}, nil
 }
-
-func init() {
-	survivalCmd.Flags().String("last", "", "Time window to consider (e.g. 7d, 2m, 1y)")
-	survivalCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
-	survivalCmd.Flags().Bool("debug", false, "Enable debug logging for survival analysis")
-	rootCmd.AddCommand(survivalCmd)
-}
\ No newline at end of file
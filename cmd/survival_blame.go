@@ -0,0 +1,478 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	linediff "github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// The blame method follows every line added in the window through each later
+// commit, the way git blame does, instead of matching content against HEAD:
+//
+//   - Lines are tracked by position, so identical lines in one file are distinct
+//   - Files are followed across renames
+//   - A line deleted in one place and added back in the same commit (moved to
+//     another file, or only re-indented) keeps its identity
+//   - Merges take each line from whichever parent already had it
+//
+// Each commit's file states are derived from its parents', so branches are
+// tracked independently until they merge.
+
+// trackedLine is a line followed by the blame method
+type trackedLine struct {
+	SurvivalLine
+	normalized string // whitespace-normalized content, for matching moves
+}
+
+// lineState maps each tracked file in one commit to its lines in order.
+// A nil entry is a line that is not tracked (written before the window,
+// blank, or outside the path filters). Files missing from the map have no
+// tracked lines at all.
+type lineState map[string][]*trackedLine
+
+// entries returns a file's lines, or lineCount untracked lines when the file isn't tracked
+func (s lineState) entries(path string, lineCount int) []*trackedLine {
+	if e, ok := s[path]; ok && len(e) == lineCount {
+		return e
+	}
+	return make([]*trackedLine, lineCount)
+}
+
+// normalizeSurvivalLine collapses whitespace so a re-indented line still matches
+func normalizeSurvivalLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// splitSurvivalLines splits content into lines the way the line differ does,
+// keeping each line's newline
+func splitSurvivalLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// mapSurvivalLines returns, for each line of newContent, the index of the line
+// of oldContent it is unchanged from, or -1 for an inserted line
+func mapSurvivalLines(oldContent, newContent string) []int {
+	newFromOld := make([]int, len(splitSurvivalLines(newContent)))
+	for i := range newFromOld {
+		newFromOld[i] = -1
+	}
+
+	oi, ni := 0, 0
+	for _, d := range linediff.Do(oldContent, newContent) {
+		n := len(splitSurvivalLines(d.Text))
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			for j := 0; j < n; j++ {
+				newFromOld[ni+j] = oi + j
+			}
+			oi += n
+			ni += n
+		case diffmatchpatch.DiffDelete:
+			oi += n
+		case diffmatchpatch.DiffInsert:
+			ni += n
+		}
+	}
+	return newFromOld
+}
+
+// blameSurvivalTracker replays commits oldest first, keeping the line state of
+// each commit until all of its children have been processed
+type blameSurvivalTracker struct {
+	pathFilters []string
+	states      map[plumbing.Hash]lineState
+	children    map[plumbing.Hash]int
+	lines       []*trackedLine
+}
+
+// survivalParentDiff is a commit's rename-aware diff against one parent
+type survivalParentDiff struct {
+	hash    plumbing.Hash
+	state   lineState
+	byPath  map[string]*object.Change // changes keyed by their new path
+	deleted []*object.Change
+}
+
+// survivalParents returns the parents a commit's lines can come from. In
+// --first-parent mode a merge is a single change against its first parent.
+func survivalParents(c *object.Commit) []*object.Commit {
+	var parents []*object.Commit
+	for i := 0; i < c.NumParents(); i++ {
+		if i > 0 && firstParentArg {
+			break
+		}
+		parent, err := c.Parent(i)
+		if err != nil {
+			// Shallow clones miss parents; lines from there are untracked
+			continue
+		}
+		parents = append(parents, parent)
+	}
+	return parents
+}
+
+// diffAgainstParents diffs a commit's tree against each parent, or against the empty tree for a root commit
+func (t *blameSurvivalTracker) diffAgainstParents(c *object.Commit, tree *object.Tree) ([]survivalParentDiff, error) {
+	parents := survivalParents(c)
+	bases := []*object.Tree{nil}
+	diffs := []survivalParentDiff{{}}
+	if len(parents) > 0 {
+		bases, diffs = nil, nil
+		for _, parent := range parents {
+			parentTree, err := parent.Tree()
+			if err != nil {
+				return nil, err
+			}
+			bases = append(bases, parentTree)
+			diffs = append(diffs, survivalParentDiff{hash: parent.Hash, state: t.states[parent.Hash]})
+		}
+	}
+
+	for i, base := range bases {
+		changes, err := object.DiffTreeWithOptions(context.Background(), base, tree, object.DefaultDiffTreeOptions)
+		if err != nil {
+			return nil, err
+		}
+		diffs[i].byPath = make(map[string]*object.Change)
+		for _, change := range changes {
+			if change.To.Name == "" {
+				diffs[i].deleted = append(diffs[i].deleted, change)
+			} else {
+				diffs[i].byPath[change.To.Name] = change
+			}
+		}
+	}
+	return diffs, nil
+}
+
+// process applies one commit, whose parents must already have been processed
+func (t *blameSurvivalTracker) process(c *object.Commit) error {
+	tree, err := c.Tree()
+	if err != nil {
+		return err
+	}
+	diffs, err := t.diffAgainstParents(c, tree)
+	if err != nil {
+		return err
+	}
+
+	var changedPaths []string
+	changed := make(map[string]*object.Change)
+	for _, d := range diffs {
+		for path, change := range d.byPath {
+			if _, ok := changed[path]; !ok {
+				changedPaths = append(changedPaths, path)
+				changed[path] = change
+			}
+		}
+	}
+	sort.Strings(changedPaths)
+
+	// Start from the first parent's files, then drop those it deleted or renamed away
+	child := make(lineState, len(diffs[0].state))
+	for path, e := range diffs[0].state {
+		child[path] = e
+	}
+	for _, change := range diffs[0].deleted {
+		delete(child, change.From.Name)
+	}
+	for _, change := range diffs[0].byPath {
+		if from := change.From.Name; from != "" && from != change.To.Name && changed[from] == nil {
+			delete(child, from)
+		}
+	}
+
+	type pendingLine struct {
+		path    string
+		index   int
+		content string
+	}
+	var pending []pendingLine
+	var dropped []*trackedLine
+	used := make(map[*trackedLine]bool)
+
+	for _, path := range changedPaths {
+		_, to, err := changed[path].Files()
+		if err != nil {
+			return err
+		}
+		if to == nil {
+			continue
+		}
+		content := ""
+		if isBinary, err := to.IsBinary(); err == nil && !isBinary {
+			content, err = to.Contents()
+			if err != nil {
+				return err
+			}
+		}
+		newLines := splitSurvivalLines(content)
+		entries := make([]*trackedLine, len(newLines))
+		assigned := make([]bool, len(newLines))
+
+		// Each line comes from the first parent that has it unchanged
+		for _, d := range diffs {
+			change, ok := d.byPath[path]
+			if !ok {
+				// Identical to this parent's version
+				old := d.state.entries(path, len(newLines))
+				for i := range entries {
+					if !assigned[i] {
+						entries[i], assigned[i] = old[i], true
+					}
+				}
+				continue
+			}
+
+			from, _, err := change.Files()
+			if err != nil {
+				return err
+			}
+			if from == nil {
+				continue
+			}
+			oldContent := ""
+			if isBinary, err := from.IsBinary(); err == nil && !isBinary {
+				oldContent, err = from.Contents()
+				if err != nil {
+					return err
+				}
+			}
+			old := d.state.entries(change.From.Name, len(splitSurvivalLines(oldContent)))
+			kept := make([]bool, len(old))
+			for i, o := range mapSurvivalLines(oldContent, content) {
+				if o < 0 {
+					continue
+				}
+				kept[o] = true
+				if !assigned[i] {
+					entries[i], assigned[i] = old[o], true
+				}
+			}
+			for o, e := range old {
+				if !kept[o] && e != nil {
+					dropped = append(dropped, e)
+				}
+			}
+		}
+
+		for i, e := range entries {
+			if !assigned[i] {
+				pending = append(pending, pendingLine{path: path, index: i, content: newLines[i]})
+			} else if e != nil {
+				used[e] = true
+			}
+		}
+		if len(entries) == 0 {
+			delete(child, path)
+		} else {
+			child[path] = entries
+		}
+	}
+
+	for _, d := range diffs {
+		for _, change := range d.deleted {
+			for _, e := range d.state[change.From.Name] {
+				if e != nil {
+					dropped = append(dropped, e)
+				}
+			}
+		}
+	}
+
+	// Lines removed by this commit can reappear elsewhere in it (moves, re-indents)
+	pool := make(map[string][]*trackedLine)
+	seen := make(map[*trackedLine]bool)
+	for _, e := range dropped {
+		if used[e] || seen[e] {
+			continue
+		}
+		seen[e] = true
+		pool[e.normalized] = append(pool[e.normalized], e)
+	}
+
+	countNew := includeMergeCommit(c)
+	author := normalizeAuthorName(c.Author.Name, c.Author.Email)
+	for _, p := range pending {
+		normalized := normalizeSurvivalLine(p.content)
+		if normalized == "" {
+			continue
+		}
+		var e *trackedLine
+		if moved := pool[normalized]; len(moved) > 0 {
+			e, pool[normalized] = moved[0], moved[1:]
+		} else if countNew && matchesPathFilter(p.path, t.pathFilters) {
+			e = &trackedLine{
+				SurvivalLine: SurvivalLine{Path: p.path, Author: author, AddedAt: c.Committer.When},
+				normalized:   normalized,
+			}
+			t.lines = append(t.lines, e)
+		}
+		child[p.path][p.index] = e
+	}
+
+	// A line can leave several branches; it was removed where it first disappeared
+	for _, removed := range pool {
+		for _, e := range removed {
+			if e.RemovedAt.IsZero() || c.Committer.When.Before(e.RemovedAt) {
+				e.RemovedAt = c.Committer.When
+				e.RemovedBy = author
+			}
+		}
+	}
+
+	t.states[c.Hash] = child
+	for _, d := range diffs {
+		if _, ok := t.children[d.hash]; !ok {
+			continue
+		}
+		t.children[d.hash]--
+		if t.children[d.hash] == 0 {
+			delete(t.states, d.hash)
+		}
+	}
+	return nil
+}
+
+// orderSurvivalCommits sorts commits so parents come before their children,
+// otherwise oldest first
+func orderSurvivalCommits(commits []*object.Commit) []*object.Commit {
+	byHash := make(map[plumbing.Hash]*object.Commit, len(commits))
+	for _, c := range commits {
+		byHash[c.Hash] = c
+	}
+	sorted := make([]*object.Commit, len(commits))
+	copy(sorted, commits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Committer.When.Before(sorted[j].Committer.When)
+	})
+
+	ordered := make([]*object.Commit, 0, len(commits))
+	emitted := make(map[plumbing.Hash]bool, len(commits))
+	for _, c := range sorted {
+		stack := []*object.Commit{c}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if emitted[top.Hash] {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			ready := true
+			for _, parentHash := range top.ParentHashes {
+				if parent, ok := byHash[parentHash]; ok && !emitted[parentHash] {
+					stack = append(stack, parent)
+					ready = false
+				}
+			}
+			if ready {
+				emitted[top.Hash] = true
+				ordered = append(ordered, top)
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return ordered
+}
+
+// analyzeSurvivalByBlame follows every line added since cutoff to HEAD and
+// records when each removed line was removed
+func analyzeSurvivalByBlame(repo *git.Repository, headCommit *object.Commit, cutoff time.Time, pathFilters []string) (*SurvivalResult, error) {
+	cIter, err := logCommits(repo, &git.LogOptions{From: headCommit.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate commits: %v", err)
+	}
+	defer cIter.Close()
+
+	var commits []*object.Commit
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if cutoff.IsZero() || !c.Committer.When.Before(cutoff) {
+			commits = append(commits, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate commits: %v", err)
+	}
+
+	tracker := &blameSurvivalTracker{
+		pathFilters: pathFilters,
+		states:      make(map[plumbing.Hash]lineState),
+		children:    make(map[plumbing.Hash]int),
+	}
+	inWindow := make(map[plumbing.Hash]bool, len(commits))
+	for _, c := range commits {
+		inWindow[c.Hash] = true
+	}
+	for _, c := range commits {
+		for _, parent := range survivalParents(c) {
+			if inWindow[parent.Hash] {
+				tracker.children[parent.Hash]++
+			}
+		}
+	}
+	// HEAD's state is the result, so it is never released
+	tracker.children[headCommit.Hash]++
+
+	for _, c := range orderSurvivalCommits(commits) {
+		if err := tracker.process(c); err != nil {
+			return nil, fmt.Errorf("failed to follow lines through %s: %v", c.Hash.String(), err)
+		}
+	}
+
+	alive := make(map[*trackedLine]bool)
+	for _, entries := range tracker.states[headCommit.Hash] {
+		for _, e := range entries {
+			if e != nil {
+				alive[e] = true
+			}
+		}
+	}
+
+	result := &SurvivalResult{Method: survivalMethodBlame}
+	for _, e := range tracker.lines {
+		e.Survived = alive[e]
+		if e.Survived {
+			e.RemovedAt, e.RemovedBy = time.Time{}, ""
+			result.Survived++
+		}
+		result.Lines = append(result.Lines, e.SurvivalLine)
+	}
+	result.Added = len(tracker.lines)
+	if result.Added > 0 {
+		result.Percent = float64(result.Survived) / float64(result.Added) * 100
+	}
+	return result, nil
+}
@@ -0,0 +1,298 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Survival curves use the Kaplan–Meier estimator: a line that is still present
+// is "censored" (we only know it has lived at least until now), so curves stay
+// honest for recent cohorts instead of treating young code as immortal.
+
+const (
+	survivalCurvesText = "text"
+	survivalCurvesJSON = "json"
+
+	// daysPerMonth converts lifetimes in days to months for display
+	daysPerMonth = 365.25 / 12
+
+	// survivalChartMonths is the age range drawn in text charts
+	survivalChartMonths = 24
+
+	// researchMedianLifespanDays is the median line lifespan from researchNote (~2.4 years)
+	researchMedianLifespanDays = 2.4 * 365.25
+)
+
+// survivalCheckpointMonths are the ages shown as columns in the cohort table
+var survivalCheckpointMonths = []int{3, 6, 12, 24}
+
+// SurvivalPoint is one step of a Kaplan–Meier curve
+type SurvivalPoint struct {
+	Days     int     `json:"days"`
+	Survival float64 `json:"survival"`
+	AtRisk   int     `json:"at_risk"`
+	Removed  int     `json:"removed"`
+}
+
+// SurvivalCurve is the Kaplan–Meier curve for one group of lines. MedianDays
+// is the half-life: the age at which half the lines are estimated to be gone.
+type SurvivalCurve struct {
+	Group         string          `json:"group"`
+	Lines         int             `json:"lines"`
+	Removed       int             `json:"removed"`
+	MedianReached bool            `json:"median_reached"`
+	MedianDays    int             `json:"median_days,omitempty"`
+	FollowUpDays  int             `json:"follow_up_days"`
+	Points        []SurvivalPoint `json:"points"`
+}
+
+// SurvivalCurves holds the overall curve plus per-cohort and per-directory curves
+type SurvivalCurves struct {
+	Overall     SurvivalCurve   `json:"overall"`
+	Cohorts     []SurvivalCurve `json:"cohorts"`
+	Directories []SurvivalCurve `json:"directories"`
+}
+
+// validateSurvivalCurvesFormat checks a --curves value
+func validateSurvivalCurvesFormat(format string) error {
+	switch format {
+	case "", survivalCurvesText, survivalCurvesJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid --curves value %q (expected text or json)", format)
+	}
+}
+
+// survivalLifetimeDays returns how many whole days a line lived, and whether
+// it was removed (true) or is still present as of end (censored)
+func survivalLifetimeDays(line SurvivalLine, end time.Time) (int, bool) {
+	until, removed := end, false
+	if !line.Survived {
+		removed = true
+		if !line.RemovedAt.IsZero() {
+			until = line.RemovedAt
+		}
+	}
+	days := int(until.Sub(line.AddedAt).Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	return days, removed
+}
+
+// kaplanMeier estimates the survival curve of a group of lines, censoring
+// lines still present at end
+func kaplanMeier(group string, lines []SurvivalLine, end time.Time) SurvivalCurve {
+	curve := SurvivalCurve{Group: group, Lines: len(lines)}
+	if len(lines) == 0 {
+		return curve
+	}
+
+	removedAt := make(map[int]int)
+	leavingAt := make(map[int]int) // removed or censored
+	var days []int
+	for _, line := range lines {
+		d, removed := survivalLifetimeDays(line, end)
+		if _, ok := leavingAt[d]; !ok {
+			days = append(days, d)
+		}
+		leavingAt[d]++
+		if removed {
+			removedAt[d]++
+			curve.Removed++
+		}
+		if d > curve.FollowUpDays {
+			curve.FollowUpDays = d
+		}
+	}
+	sort.Ints(days)
+
+	survival := 1.0
+	atRisk := len(lines)
+	curve.Points = append(curve.Points, SurvivalPoint{Days: 0, Survival: 1, AtRisk: atRisk})
+	for _, d := range days {
+		if r := removedAt[d]; r > 0 {
+			survival *= 1 - float64(r)/float64(atRisk)
+			curve.Points = append(curve.Points, SurvivalPoint{Days: d, Survival: survival, AtRisk: atRisk, Removed: r})
+			if !curve.MedianReached && survival <= 0.5 {
+				curve.MedianReached = true
+				curve.MedianDays = d
+			}
+		}
+		atRisk -= leavingAt[d]
+	}
+	return curve
+}
+
+// survivalAt returns the curve's estimated survival at an age in days, and
+// false when the age lies beyond the group's follow-up
+func (c SurvivalCurve) survivalAt(days int) (float64, bool) {
+	if len(c.Points) == 0 || days > c.FollowUpDays {
+		return 0, false
+	}
+	survival := 1.0
+	for _, p := range c.Points {
+		if p.Days > days {
+			break
+		}
+		survival = p.Survival
+	}
+	return survival, true
+}
+
+// groupSurvivalCurves computes one curve per group, in group order
+func groupSurvivalCurves(lines []SurvivalLine, end time.Time, key func(SurvivalLine) string) []SurvivalCurve {
+	groups := make(map[string][]SurvivalLine)
+	for _, line := range lines {
+		k := key(line)
+		groups[k] = append(groups[k], line)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	curves := make([]SurvivalCurve, 0, len(names))
+	for _, name := range names {
+		curves = append(curves, kaplanMeier(name, groups[name], end))
+	}
+	return curves
+}
+
+// buildSurvivalCurves computes the overall curve, monthly cohort curves and
+// per-directory curves; directories are ordered shortest half-life first
+func buildSurvivalCurves(lines []SurvivalLine, end time.Time) *SurvivalCurves {
+	curves := &SurvivalCurves{
+		Overall: kaplanMeier("all", lines, end),
+		Cohorts: groupSurvivalCurves(lines, end, func(l SurvivalLine) string {
+			return l.AddedAt.UTC().Format("2006-01")
+		}),
+		Directories: groupSurvivalCurves(lines, end, func(l SurvivalLine) string {
+			return reportDirectory(l.Path)
+		}),
+	}
+	sortSurvivalCurvesByHalfLife(curves.Directories)
+	return curves
+}
+
+// sortSurvivalCurvesByHalfLife orders curves shortest half-life first; curves
+// that haven't reached their median follow, by estimated survival at the end
+// of follow-up
+func sortSurvivalCurvesByHalfLife(curves []SurvivalCurve) {
+	sort.SliceStable(curves, func(i, j int) bool {
+		a, b := curves[i], curves[j]
+		if a.MedianReached != b.MedianReached {
+			return a.MedianReached
+		}
+		if a.MedianReached {
+			return a.MedianDays < b.MedianDays
+		}
+		sa, _ := a.survivalAt(a.FollowUpDays)
+		sb, _ := b.survivalAt(b.FollowUpDays)
+		return sa < sb
+	})
+}
+
+// formatHalfLife describes a curve's half-life in months
+func formatHalfLife(c SurvivalCurve) string {
+	if c.MedianReached {
+		return fmt.Sprintf("%.1f mo", float64(c.MedianDays)/daysPerMonth)
+	}
+	return fmt.Sprintf(">%.1f mo", float64(c.FollowUpDays)/daysPerMonth)
+}
+
+// formatSurvivalChart draws survival by month of age as one character per
+// month; ages beyond the group's follow-up are left blank
+func formatSurvivalChart(c SurvivalCurve, months int) string {
+	var chart strings.Builder
+	for m := 0; m <= months; m++ {
+		s, ok := c.survivalAt(int(math.Round(float64(m) * daysPerMonth)))
+		if !ok {
+			chart.WriteByte(' ')
+			continue
+		}
+		level := int(math.Round(s * float64(len(tuiSparkLevels)-1)))
+		if level == 0 && s > 0 {
+			level = 1
+		}
+		chart.WriteByte(tuiSparkLevels[level])
+	}
+	return chart.String()
+}
+
+// printSurvivalCurves renders the curves as text tables and charts
+func printSurvivalCurves(curves *SurvivalCurves, limit int) {
+	overall := curves.Overall
+	fmt.Printf("Half-life (Kaplan–Meier median line lifetime): %s", formatHalfLife(overall))
+	fmt.Printf(" vs ~%.1f mo in the research median\n\n", researchMedianLifespanDays/daysPerMonth)
+
+	header := fmt.Sprintf("%-10s %7s %7s", "Cohort", "Lines", "Removed")
+	for _, m := range survivalCheckpointMonths {
+		header += fmt.Sprintf(" %6s", fmt.Sprintf("%dmo", m))
+	}
+	header += fmt.Sprintf("  %-10s %s", "Half-life", fmt.Sprintf("Curve (0-%d months)", survivalChartMonths))
+
+	fmt.Println("Survival by monthly cohort:")
+	fmt.Println(header)
+	rows := append(append([]SurvivalCurve{}, curves.Cohorts...), overall)
+	for _, c := range rows {
+		row := fmt.Sprintf("%-10s %7d %7d", c.Group, c.Lines, c.Removed)
+		for _, m := range survivalCheckpointMonths {
+			if s, ok := c.survivalAt(int(math.Round(float64(m) * daysPerMonth))); ok {
+				row += fmt.Sprintf(" %5.1f%%", s*100)
+			} else {
+				row += fmt.Sprintf(" %6s", "-")
+			}
+		}
+		row += fmt.Sprintf("  %-10s [%s]", formatHalfLife(c), formatSurvivalChart(c, survivalChartMonths))
+		fmt.Println(row)
+	}
+
+	fmt.Printf("\nShortest-lived directories (top %d):\n", limit)
+	fmt.Printf("%-40s %7s %7s  %s\n", "Directory", "Lines", "Removed", "Half-life")
+	for i, c := range curves.Directories {
+		if i >= limit {
+			break
+		}
+		fmt.Printf("%-40s %7d %7d  %s\n", c.Group, c.Lines, c.Removed, formatHalfLife(c))
+	}
+	fmt.Println()
+	fmt.Println("Half-lives shown as >N mo have not been reached within the follow-up so far.")
+}
+
+// printSurvivalCurvesJSON writes the curves as JSON series
+func printSurvivalCurvesJSON(curves *SurvivalCurves) error {
+	out, err := json.MarshalIndent(curves, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
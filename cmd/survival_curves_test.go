@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSurvivalLines(start time.Time) []SurvivalLine {
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	return []SurvivalLine{
+		{Path: "a/x.go", AddedAt: day(0), RemovedAt: day(2)},
+		{Path: "a/x.go", AddedAt: day(0), RemovedAt: day(2)},
+		{Path: "b/y.go", AddedAt: day(7), Survived: true},
+		{Path: "a/x.go", AddedAt: day(0), RemovedAt: day(5)},
+		{Path: "b/y.go", AddedAt: day(0), Survived: true},
+	}
+}
+
+func TestKaplanMeier(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	curve := kaplanMeier("all", newTestSurvivalLines(start), start.AddDate(0, 0, 10))
+
+	expected := []SurvivalPoint{
+		{Days: 0, Survival: 1, AtRisk: 5},
+		{Days: 2, Survival: 0.6, AtRisk: 5, Removed: 2},
+		// The line censored at day 3 leaves the risk set before day 5
+		{Days: 5, Survival: 0.3, AtRisk: 2, Removed: 1},
+	}
+	if len(curve.Points) != len(expected) {
+		t.Fatalf("Expected %d points, got %+v", len(expected), curve.Points)
+	}
+	for i, p := range curve.Points {
+		e := expected[i]
+		if p.Days != e.Days || p.AtRisk != e.AtRisk || p.Removed != e.Removed || math.Abs(p.Survival-e.Survival) > 1e-9 {
+			t.Errorf("Point %d: got %+v, want %+v", i, p, e)
+		}
+	}
+
+	if !curve.MedianReached || curve.MedianDays != 5 {
+		t.Errorf("Expected a median of 5 days, got reached=%v days=%d", curve.MedianReached, curve.MedianDays)
+	}
+	if curve.Lines != 5 || curve.Removed != 3 || curve.FollowUpDays != 10 {
+		t.Errorf("Unexpected totals: %+v", curve)
+	}
+
+	if empty := kaplanMeier("none", nil, start); len(empty.Points) != 0 || empty.MedianReached {
+		t.Errorf("Expected an empty curve, got %+v", empty)
+	}
+}
+
+func TestSurvivalAt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	curve := kaplanMeier("all", newTestSurvivalLines(start), start.AddDate(0, 0, 10))
+
+	tests := []struct {
+		days     int
+		expected float64
+		ok       bool
+	}{
+		{0, 1, true},
+		{1, 1, true},
+		{4, 0.6, true},
+		{10, 0.3, true},
+		{11, 0, false},
+	}
+	for _, tt := range tests {
+		s, ok := curve.survivalAt(tt.days)
+		if ok != tt.ok || math.Abs(s-tt.expected) > 1e-9 {
+			t.Errorf("survivalAt(%d) = %.2f, %v; want %.2f, %v", tt.days, s, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestBuildSurvivalCurves(t *testing.T) {
+	start := time.Date(2025, 1, 28, 0, 0, 0, 0, time.UTC)
+	curves := buildSurvivalCurves(newTestSurvivalLines(start), start.AddDate(0, 0, 10))
+
+	var cohorts []string
+	for _, c := range curves.Cohorts {
+		cohorts = append(cohorts, c.Group)
+	}
+	if strings.Join(cohorts, ",") != "2025-01,2025-02" {
+		t.Errorf("Expected cohorts 2025-01,2025-02, got %v", cohorts)
+	}
+
+	// a/ loses every line; b/ loses none, so a/ comes first
+	if len(curves.Directories) != 2 || curves.Directories[0].Group != "a/" {
+		t.Fatalf("Expected a/ first among directories, got %+v", curves.Directories)
+	}
+	if !curves.Directories[0].MedianReached || curves.Directories[1].MedianReached {
+		t.Error("Expected only a/ to reach its median")
+	}
+}
+
+func TestFormatHalfLife(t *testing.T) {
+	reached := SurvivalCurve{MedianReached: true, MedianDays: 61}
+	if result := formatHalfLife(reached); result != "2.0 mo" {
+		t.Errorf("Expected 2.0 mo, got %s", result)
+	}
+	censored := SurvivalCurve{FollowUpDays: 365}
+	if result := formatHalfLife(censored); result != ">12.0 mo" {
+		t.Errorf("Expected >12.0 mo, got %s", result)
+	}
+}
+
+func TestFormatSurvivalChart(t *testing.T) {
+	curve := SurvivalCurve{
+		FollowUpDays: 95,
+		Points: []SurvivalPoint{
+			{Days: 0, Survival: 1},
+			{Days: 40, Survival: 0.5},
+			{Days: 70, Survival: 0.01},
+		},
+	}
+
+	result := formatSurvivalChart(curve, 4)
+	expected := "##=. "
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestValidateSurvivalCurvesFormat(t *testing.T) {
+	for _, format := range []string{"", "text", "json"} {
+		if err := validateSurvivalCurvesFormat(format); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", format, err)
+		}
+	}
+	if err := validateSurvivalCurvesFormat("svg"); err == nil {
+		t.Error("Expected an error for svg")
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	survivalByAuthor    = "author"
+	survivalByDirectory = "directory"
+	survivalByExtension = "extension"
+)
+
+const (
+	// survivalLowGroupMargin is how many points below the overall survival rate
+	// a group must be to be flagged
+	survivalLowGroupMargin = 10.0
+
+	// survivalMinFlaggedLines keeps tiny groups from being flagged on noise
+	survivalMinFlaggedLines = 20
+)
+
+// SurvivalGroupStats summarizes survival for one author, directory or extension
+type SurvivalGroupStats struct {
+	Group       string
+	Added       int
+	Survived    int
+	Percent     float64
+	Removed     int
+	SelfRemoved int // removed lines that their own author removed
+	Curve       SurvivalCurve
+	FirstCommit time.Time // authors only: first commit anywhere in history
+	Low         bool      // well below the overall survival rate
+}
+
+// SelfRemovedPercent returns the share of removed lines their own author removed
+func (g SurvivalGroupStats) SelfRemovedPercent() float64 {
+	if g.Removed == 0 {
+		return 0
+	}
+	return float64(g.SelfRemoved) / float64(g.Removed) * 100
+}
+
+// validateSurvivalBy checks a --by value
+func validateSurvivalBy(by string) error {
+	switch by {
+	case "", survivalByAuthor, survivalByDirectory, survivalByExtension:
+		return nil
+	default:
+		return fmt.Errorf("invalid --by value %q (expected author, directory or extension)", by)
+	}
+}
+
+// survivalGroupKey returns the group a line belongs to. Authors are already
+// normalized the way bus-factor normalizes them.
+func survivalGroupKey(line SurvivalLine, by string) string {
+	switch by {
+	case survivalByAuthor:
+		return line.Author
+	case survivalByExtension:
+		ext := strings.ToLower(filepath.Ext(line.Path))
+		if ext == "" {
+			return "(none)"
+		}
+		return ext
+	default:
+		return reportDirectory(line.Path)
+	}
+}
+
+// groupSurvivalStats breaks survival down by group, lowest survival first, and
+// flags groups well below the overall rate
+func groupSurvivalStats(lines []SurvivalLine, by string, end time.Time) []SurvivalGroupStats {
+	groups := make(map[string][]SurvivalLine)
+	var survived int
+	for _, line := range lines {
+		key := survivalGroupKey(line, by)
+		groups[key] = append(groups[key], line)
+		if line.Survived {
+			survived++
+		}
+	}
+	overall := 0.0
+	if len(lines) > 0 {
+		overall = float64(survived) / float64(len(lines)) * 100
+	}
+
+	stats := make([]SurvivalGroupStats, 0, len(groups))
+	for name, groupLines := range groups {
+		g := SurvivalGroupStats{Group: name, Added: len(groupLines), Curve: kaplanMeier(name, groupLines, end)}
+		for _, line := range groupLines {
+			if line.Survived {
+				g.Survived++
+				continue
+			}
+			g.Removed++
+			if line.RemovedBy != "" && line.RemovedBy == line.Author {
+				g.SelfRemoved++
+			}
+		}
+		g.Percent = float64(g.Survived) / float64(g.Added) * 100
+		g.Low = g.Added >= survivalMinFlaggedLines && g.Percent <= overall-survivalLowGroupMargin
+		stats = append(stats, g)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Percent != stats[j].Percent {
+			return stats[i].Percent < stats[j].Percent
+		}
+		if stats[i].Added != stats[j].Added {
+			return stats[i].Added > stats[j].Added
+		}
+		return stats[i].Group < stats[j].Group
+	})
+	return stats
+}
+
+// authorFirstCommits returns each normalized author's first commit time across all history
+func authorFirstCommits(repo *git.Repository, head *object.Commit) (map[string]time.Time, error) {
+	cIter, err := logCommits(repo, &git.LogOptions{From: head.Hash})
+	if err != nil {
+		return nil, err
+	}
+	defer cIter.Close()
+
+	first := make(map[string]time.Time)
+	err = cIter.ForEach(func(c *object.Commit) error {
+		author := normalizeAuthorName(c.Author.Name, c.Author.Email)
+		if t, ok := first[author]; !ok || c.Author.When.Before(t) {
+			first[author] = c.Author.When
+		}
+		return nil
+	})
+	return first, err
+}
+
+// printSurvivalGroups prints the per-group breakdown, lowest survival first
+func printSurvivalGroups(stats []SurvivalGroupStats, by string, limit int) {
+	fmt.Printf("Survival by %s (lowest first, top %d):\n", by, limit)
+	header := fmt.Sprintf("%-40s %7s %7s %8s  %-10s %12s", titleCase(by), "Added", "Present", "Survival", "Half-life", "Self-removed")
+	if by == survivalByAuthor {
+		header += "  First commit"
+	}
+	fmt.Println(header)
+
+	flagged := 0
+	for i, g := range stats {
+		if i >= limit {
+			break
+		}
+		marker := "  "
+		if g.Low {
+			marker = "⚠️"
+			flagged++
+		}
+		row := fmt.Sprintf("%-40s %7d %7d %7.1f%%%s %-10s %11.1f%%", g.Group, g.Added, g.Survived, g.Percent, marker, formatHalfLife(g.Curve), g.SelfRemovedPercent())
+		if by == survivalByAuthor && !g.FirstCommit.IsZero() {
+			row += "  " + g.FirstCommit.Format("2006-01")
+		}
+		fmt.Println(row)
+	}
+
+	fmt.Println()
+	if flagged > 0 {
+		fmt.Printf("⚠️  marks groups at least %.0f points below the overall survival rate (%d+ lines).\n", survivalLowGroupMargin, survivalMinFlaggedLines)
+	}
+	fmt.Println("Self-removed is the share of a group's removed lines that were removed by the author who wrote them.")
+}
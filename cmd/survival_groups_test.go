@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSurvivalGroupKey(t *testing.T) {
+	line := SurvivalLine{Path: "src/app/Main.JAVA", Author: "alice@x.com"}
+	tests := []struct {
+		by       string
+		line     SurvivalLine
+		expected string
+	}{
+		{survivalByAuthor, line, "alice@x.com"},
+		{survivalByDirectory, line, "src/app/"},
+		{survivalByDirectory, SurvivalLine{Path: "main.go"}, "root/"},
+		{survivalByExtension, line, ".java"},
+		{survivalByExtension, SurvivalLine{Path: "Makefile"}, "(none)"},
+	}
+
+	for _, tt := range tests {
+		if result := survivalGroupKey(tt.line, tt.by); result != tt.expected {
+			t.Errorf("survivalGroupKey(%s, %s) = %s, want %s", tt.line.Path, tt.by, result, tt.expected)
+		}
+	}
+}
+
+func TestGroupSurvivalStats(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var lines []SurvivalLine
+	add := func(author string, count int, survived bool, removedBy string) {
+		for i := 0; i < count; i++ {
+			line := SurvivalLine{Path: "a.go", Author: author, AddedAt: start, Survived: survived}
+			if !survived {
+				line.RemovedAt, line.RemovedBy = start.AddDate(0, 0, 10), removedBy
+			}
+			lines = append(lines, line)
+		}
+	}
+	// alice: 30 lines, 24 survive; bob: 30 lines, 6 survive and he removed most of the rest himself
+	add("alice", 24, true, "")
+	add("alice", 6, false, "bob")
+	add("bob", 6, true, "")
+	add("bob", 18, false, "bob")
+	add("bob", 6, false, "alice")
+	// carol: too few lines to flag
+	add("carol", 2, false, "carol")
+
+	stats := groupSurvivalStats(lines, survivalByAuthor, start.AddDate(0, 1, 0))
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(stats))
+	}
+
+	order := []string{"carol", "bob", "alice"}
+	for i, name := range order {
+		if stats[i].Group != name {
+			t.Errorf("Position %d: expected %s, got %s", i, name, stats[i].Group)
+		}
+	}
+
+	bob := stats[1]
+	if bob.Added != 30 || bob.Survived != 6 || bob.Removed != 24 || bob.SelfRemoved != 18 {
+		t.Errorf("Unexpected bob stats: %+v", bob)
+	}
+	if bob.SelfRemovedPercent() != 75 {
+		t.Errorf("Expected bob to have removed 75%% of his own removed lines, got %.1f", bob.SelfRemovedPercent())
+	}
+	if !bob.Low {
+		t.Error("Expected bob to be flagged as low survival")
+	}
+	if stats[0].Low {
+		t.Error("Expected carol not to be flagged with only 2 lines")
+	}
+	if stats[2].Low {
+		t.Error("Expected alice not to be flagged")
+	}
+}
+
+func TestValidateSurvivalBy(t *testing.T) {
+	for _, by := range []string{"", "author", "directory", "extension"} {
+		if err := validateSurvivalBy(by); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", by, err)
+		}
+	}
+	if err := validateSurvivalBy("team"); err == nil {
+		t.Error("Expected an error for team")
+	}
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestMakeKey(t *testing.T) {
+	if makeKey("a.go", "x") == makeKey("b.go", "x") {
+		t.Error("Expected different keys for different files")
+	}
+	if !strings.HasSuffix(makeKey("a.go", "x"), lineKeySeparator+hashLine("x")) {
+		t.Error("Expected makeKey to end with the line hash")
+	}
+}
+
+func TestSplitSurvivalLines(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected []string
+	}{
+		{"", nil},
+		{"a\nb\n", []string{"a\n", "b\n"}},
+		{"a\nb", []string{"a\n", "b"}},
+		{"\n\n", []string{"\n", "\n"}},
+	}
+
+	for _, tt := range tests {
+		result := splitSurvivalLines(tt.content)
+		if len(result) == 0 && len(tt.expected) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("splitSurvivalLines(%q) = %q, want %q", tt.content, result, tt.expected)
+		}
+	}
+}
+
+func TestMapSurvivalLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldContent string
+		newContent string
+		expected   []int
+	}{
+		{"unchanged", "a\nb\n", "a\nb\n", []int{0, 1}},
+		{"insert in the middle", "a\nc\n", "a\nb\nc\n", []int{0, -1, 1}},
+		{"modify", "a\nb\nc\n", "a\nB\nc\n", []int{0, -1, 2}},
+		{"delete", "a\nb\nc\n", "a\nc\n", []int{0, 2}},
+		{"identical lines stay distinct", "x\nx\n", "x\ny\nx\n", []int{0, -1, 1}},
+		{"new file", "", "a\n", []int{-1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mapSurvivalLines(tt.oldContent, tt.newContent)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("mapSurvivalLines() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeSurvivalLine(t *testing.T) {
+	if normalizeSurvivalLine("\tfoo(a,  b)\n") != normalizeSurvivalLine("    foo(a, b)") {
+		t.Error("Expected re-indented lines to normalize to the same content")
+	}
+	if normalizeSurvivalLine("   \n") != "" {
+		t.Error("Expected blank lines to normalize to empty")
+	}
+}
+
+func TestLineStateEntries(t *testing.T) {
+	tracked := &trackedLine{}
+	state := lineState{"a.go": {nil, tracked}}
+
+	if e := state.entries("a.go", 2); e[1] != tracked {
+		t.Error("Expected tracked entries for a known file")
+	}
+	if e := state.entries("b.go", 3); len(e) != 3 || e[0] != nil {
+		t.Errorf("Expected 3 untracked lines for an unknown file, got %v", e)
+	}
+	// A length mismatch means the state is stale; fall back to untracked lines
+	if e := state.entries("a.go", 5); len(e) != 5 || e[1] != nil {
+		t.Errorf("Expected untracked lines on length mismatch, got %v", e)
+	}
+	if e := lineState(nil).entries("a.go", 1); len(e) != 1 {
+		t.Error("Expected a nil state to yield untracked lines")
+	}
+}
+
+func TestOrderSurvivalCommits(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(id byte, minutes int, parents ...*object.Commit) *object.Commit {
+		c := &object.Commit{Hash: plumbing.Hash{id}}
+		c.Committer.When = base.Add(time.Duration(minutes) * time.Minute)
+		for _, p := range parents {
+			c.ParentHashes = append(c.ParentHashes, p.Hash)
+		}
+		return c
+	}
+
+	root := commit(1, 0)
+	// The branch commit claims to be older than its parent (clock skew)
+	branch := commit(2, -10, root)
+	main := commit(3, 5, root)
+	merge := commit(4, 10, main, branch)
+
+	ordered := orderSurvivalCommits([]*object.Commit{merge, main, branch, root})
+	position := make(map[plumbing.Hash]int)
+	for i, c := range ordered {
+		position[c.Hash] = i
+	}
+	if len(ordered) != 4 {
+		t.Fatalf("Expected 4 commits, got %d", len(ordered))
+	}
+	for _, c := range ordered {
+		for _, p := range c.ParentHashes {
+			if position[p] > position[c.Hash] {
+				t.Errorf("Parent %x ordered after child %x", p[:1], c.Hash[:1])
+			}
+		}
+	}
+}
+
+func TestMedianRemovedLifetime(t *testing.T) {
+	added := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	lines := []SurvivalLine{
+		{AddedAt: added, RemovedAt: added.AddDate(0, 0, 2)},
+		{AddedAt: added, RemovedAt: added.AddDate(0, 0, 4)},
+		{AddedAt: added, RemovedAt: added.AddDate(0, 0, 30)},
+		{AddedAt: added, Survived: true},
+	}
+
+	median, removed := medianRemovedLifetime(lines)
+	if removed != 3 {
+		t.Errorf("Expected 3 removed lines, got %d", removed)
+	}
+	if median != 4*24*time.Hour {
+		t.Errorf("Expected a median of 4 days, got %v", median)
+	}
+
+	if _, removed := medianRemovedLifetime(nil); removed != 0 {
+		t.Errorf("Expected no removed lines, got %d", removed)
+	}
+}
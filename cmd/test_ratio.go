@@ -63,6 +63,9 @@ type TestRatioStats struct {
 	SourceFiles   int
 	OtherFiles    int
 	TotalFiles    int
+	CountMode     string
+	CommentLines  int // comment lines excluded from LOC in code mode
+	BlankLines    int // blank lines excluded from LOC in code mode
 }
 
 // isTestFile determines if a file path represents a test file based on common patterns
@@ -168,8 +171,9 @@ func classifyTestRatio(ratio float64) (string, string) {
 	}
 }
 
-// analyzeTestRatio analyzes the test-to-code ratio in the repository
-func analyzeTestRatio(repo *git.Repository, pathFilters []string) (*TestRatioStats, error) {
+// analyzeTestRatio analyzes the test-to-code ratio in the repository.
+// countMode selects which lines count toward LOC (see line_classifier.go).
+func analyzeTestRatio(repo *git.Repository, pathFilters []string, countMode string) (*TestRatioStats, error) {
 	ref, err := repo.Head()
 	if err != nil {
 		return nil, fmt.Errorf("could not get HEAD: %v", err)
@@ -185,7 +189,7 @@ func analyzeTestRatio(repo *git.Repository, pathFilters []string) (*TestRatioSta
 		return nil, fmt.Errorf("could not get HEAD tree: %v", err)
 	}
 	
-	stats := &TestRatioStats{}
+	stats := &TestRatioStats{CountMode: countMode}
 	
 	err = tree.Files().ForEach(func(f *object.File) error {
 		// Apply path filter if specified
@@ -206,6 +210,12 @@ func analyzeTestRatio(repo *git.Repository, pathFilters []string) (*TestRatioSta
 		}
 		
 		lineCount := countLines(content)
+		if countMode == countModeCode {
+			lines := classifyLines(f.Name, content)
+			lineCount = lines.Code
+			stats.CommentLines += lines.Comment
+			stats.BlankLines += lines.Blank
+		}
 		fileType := classifyFileType(f.Name)
 		
 		switch fileType {
@@ -245,6 +255,9 @@ func printTestRatioStats(stats *TestRatioStats, pathFilters []string) {
 	fmt.Printf("Source files: %d (%d LOC)\n", stats.SourceFiles, stats.SourceLOC)
 	fmt.Printf("Test files: %d (%d LOC)\n", stats.TestFiles, stats.TestLOC)
 	fmt.Printf("Other files: %d (%d LOC)\n", stats.OtherFiles, stats.OtherLOC)
+	if stats.CountMode == countModeCode {
+		fmt.Printf("LOC counts code lines only (excluded %d comment and %d blank lines)\n", stats.CommentLines, stats.BlankLines)
+	}
 	fmt.Println()
 	
 	fmt.Printf("Test-to-Code Ratio: %.2f:1 — %s\n", stats.TestRatio, stats.Status)
@@ -306,6 +319,10 @@ Classifications:
 	Run: func(cmd *cobra.Command, args []string) {
 		// Parse flags
 		pathFilters, source := getConfigPaths(cmd, "test-ratio.paths")
+		countMode, err := getCountMode(cmd)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
 		
 		// Print configuration scope
 		printCommandScope(cmd, "test-ratio", "", pathFilters, source)
@@ -315,7 +332,7 @@ Classifications:
 			log.Fatalf("Could not open repository: %v", err)
 		}
 
-		stats, err := analyzeTestRatio(repo, pathFilters)
+		stats, err := analyzeTestRatio(repo, pathFilters, countMode)
 		if err != nil {
 			log.Fatalf("Error analyzing test ratio: %v", err)
 		}
@@ -326,5 +343,6 @@ Classifications:
 
 func init() {
 	testRatioCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+	addCountFlag(testRatioCmd)
 	rootCmd.AddCommand(testRatioCmd)
 }
@@ -0,0 +1,276 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+// DORA time to restore thresholds in hours, following the State of DevOps
+// performance bands
+const (
+	eliteRestoreThreshold  = 1.0   // <1 hour
+	highRestoreThreshold   = 24.0  // <1 day
+	mediumRestoreThreshold = 168.0 // <1 week
+	// A week or more is Low performance
+)
+
+// IncidentRestore is an incident matched to what restored service
+type IncidentRestore struct {
+	Incident   Incident
+	Restored   bool
+	RestoredAt time.Time
+	Hash       plumbing.Hash // restoring commit or deployed commit
+	RestoredBy string        // description of the restoring commit or deployment
+	Inferred   bool          // restore time taken from the next deployment, not an end marker
+}
+
+// Duration returns how long service took to restore
+func (r IncidentRestore) Duration() time.Duration {
+	return r.RestoredAt.Sub(r.Incident.Start)
+}
+
+// TimeToRestoreStats summarizes how quickly incidents were resolved
+type TimeToRestoreStats struct {
+	Incidents      int
+	Restored       int
+	Open           int
+	Unmatched      int // end markers without a start
+	MedianHours    float64
+	P95Hours       float64
+	Classification string            // "Elite", "High", "Medium", "Low", "Unknown"
+	Restores       []IncidentRestore // longest first, open incidents last
+}
+
+// classifyDORATimeToRestore classifies a restore time according to DORA benchmarks
+func classifyDORATimeToRestore(hours float64) string {
+	if hours < eliteRestoreThreshold {
+		return "Elite"
+	} else if hours < highRestoreThreshold {
+		return "High"
+	} else if hours < mediumRestoreThreshold {
+		return "Medium"
+	}
+	return "Low"
+}
+
+// matchIncidentRestores matches each incident to what restored it. An end
+// marker that names a commit is restored by that commit; an end marker
+// without one by the last deployment between start and end. Without an end
+// marker the first deployment after the start restores the incident and gives
+// the restore time; with no later deployment the incident stays open.
+// deployments must be sorted oldest first.
+func matchIncidentRestores(incidents []Incident, deployments []Deployment) []IncidentRestore {
+	restores := make([]IncidentRestore, 0, len(incidents))
+	for _, inc := range incidents {
+		r := IncidentRestore{Incident: inc}
+		switch {
+		case !inc.End.IsZero() && !inc.RestoreHash.IsZero():
+			r.Restored, r.RestoredAt, r.Hash = true, inc.End, inc.RestoreHash
+			r.RestoredBy = "commit " + inc.RestoreHash.String()[:7]
+		case !inc.End.IsZero():
+			r.Restored, r.RestoredAt = true, inc.End
+			for i := len(deployments) - 1; i >= 0; i-- {
+				d := deployments[i]
+				if d.Time.After(inc.End) {
+					continue
+				}
+				if d.Time.After(inc.Start) {
+					r.Hash, r.RestoredBy = d.Hash, "deploy "+d.Ref
+				}
+				break
+			}
+		default:
+			for _, d := range deployments {
+				if d.Time.After(inc.Start) {
+					r.Restored, r.RestoredAt, r.Inferred = true, d.Time, true
+					r.Hash, r.RestoredBy = d.Hash, "deploy "+d.Ref
+					break
+				}
+			}
+		}
+		restores = append(restores, r)
+	}
+	return restores
+}
+
+// calculateTimeToRestoreStats computes median and p95 restore times
+func calculateTimeToRestoreStats(restores []IncidentRestore, unmatched int) *TimeToRestoreStats {
+	stats := &TimeToRestoreStats{Incidents: len(restores), Unmatched: unmatched}
+
+	var hours []float64
+	for _, r := range restores {
+		if !r.Restored {
+			stats.Open++
+			continue
+		}
+		stats.Restored++
+		hours = append(hours, r.Duration().Hours())
+	}
+
+	stats.Restores = append([]IncidentRestore{}, restores...)
+	sort.SliceStable(stats.Restores, func(i, j int) bool {
+		a, b := stats.Restores[i], stats.Restores[j]
+		if a.Restored != b.Restored {
+			return a.Restored
+		}
+		return a.Duration() > b.Duration()
+	})
+
+	if len(hours) == 0 {
+		stats.Classification = "Unknown"
+		return stats
+	}
+	stats.MedianHours = calculatePercentile(hours, 50)
+	stats.P95Hours = calculatePercentile(hours, 95)
+	stats.Classification = classifyDORATimeToRestore(stats.MedianHours)
+	return stats
+}
+
+// printTimeToRestoreStats displays the time to restore analysis
+func printTimeToRestoreStats(stats *TimeToRestoreStats, incidents IncidentConfig, deployments DeploymentConfig, limit int) {
+	fmt.Println("Time to Restore Analysis")
+	fmt.Printf("Incidents: %s\n", incidents.Describe())
+	fmt.Printf("Deployments: %s\n", deployments.Describe())
+	fmt.Println()
+
+	if stats.Incidents == 0 {
+		fmt.Println("No incidents found. Use --incident-source, --incident-prefix or --incidents to match how you record incidents.")
+		if stats.Unmatched > 0 {
+			fmt.Printf("%d end markers had no matching start.\n", stats.Unmatched)
+		}
+		return
+	}
+
+	fmt.Printf("Incidents: %d (%d restored, %d open)\n", stats.Incidents, stats.Restored, stats.Open)
+	if stats.Unmatched > 0 {
+		fmt.Printf("End markers without a start: %d (ignored)\n", stats.Unmatched)
+	}
+	if stats.Restored > 0 {
+		fmt.Printf("Median time to restore: %s\n", formatElapsed(hoursDuration(stats.MedianHours)))
+		fmt.Printf("P95 time to restore: %s\n", formatElapsed(hoursDuration(stats.P95Hours)))
+	}
+	fmt.Printf("DORA classification: %s\n", stats.Classification)
+
+	fmt.Printf("\nIncidents (longest first, showing %d):\n", min(len(stats.Restores), limit))
+	for i, r := range stats.Restores {
+		if i >= limit {
+			break
+		}
+		inc := r.Incident
+		if !r.Restored {
+			fmt.Printf("%d. %s  started %s, still open\n", i+1, inc.ID, inc.Start.Format("2006-01-02 15:04"))
+			continue
+		}
+		restoredBy := r.RestoredBy
+		if restoredBy == "" {
+			restoredBy = "end marker"
+		}
+		if r.Inferred {
+			restoredBy += " (no end marker)"
+		}
+		fmt.Printf("%d. %s  %s  started %s, restored by %s\n", i+1, inc.ID, formatElapsed(r.Duration()), inc.Start.Format("2006-01-02 15:04"), restoredBy)
+	}
+
+	fmt.Println()
+	fmt.Println("DORA benchmarks: Elite <1 hour, High <1 day, Medium <1 week, Low a week or more.")
+}
+
+// timeToRestoreCmd represents the time-to-restore command
+var timeToRestoreCmd = &cobra.Command{
+	Use:   "time-to-restore",
+	Short: "Measure DORA time to restore service from incident markers",
+	Long: `Measure how long it takes to restore service after a production incident.
+
+Incidents come from one of:
+- tags: tags named incident/<id>/start and incident/<id>/end (prefix set by
+  --incident-prefix); annotated tags record when they were created
+- notes: git notes (--notes-ref) with "incident-start: <id> [time]" and
+  "incident-end: <id> [time]" lines; an end note marks the restoring commit
+- file: an incidents export (--incidents), CSV or JSON with id, start and
+  end times and optionally the restoring sha
+
+Each incident is matched to the commit or deployment that restored it.
+Incidents without an end marker are restored by the first deployment after
+they started. Reports median and p95 time to restore with the DORA
+classification of the median:
+- Elite: less than one hour
+- High: less than one day
+- Medium: less than one week
+- Low: a week or more`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lastArg, _ := cmd.Flags().GetString("last")
+		limitArg, _ := cmd.Flags().GetInt("limit")
+
+		incidentCfg, err := getIncidentConfig(cmd, "time-to-restore")
+		if err != nil {
+			return err
+		}
+		deployCfg, err := getDeploymentConfig(cmd, "time-to-restore")
+		if err != nil {
+			return err
+		}
+
+		// Print configuration scope
+		printCommandScope(cmd, "time-to-restore", lastArg, nil, "")
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			return fmt.Errorf("could not open repository: %v", err)
+		}
+
+		var since time.Time
+		if lastArg != "" {
+			since, err = parseDurationArg(lastArg)
+			if err != nil {
+				return fmt.Errorf("invalid time window '%s': %v", lastArg, err)
+			}
+		}
+
+		incidents, unmatched, err := findIncidents(repo, incidentCfg, since)
+		if err != nil {
+			return fmt.Errorf("could not find incidents: %v", err)
+		}
+		deployments, err := findDeployments(repo, deployCfg, since)
+		if err != nil {
+			return fmt.Errorf("could not find deployments: %v", err)
+		}
+
+		stats := calculateTimeToRestoreStats(matchIncidentRestores(incidents, deployments), unmatched)
+		printTimeToRestoreStats(stats, incidentCfg, deployCfg, limitArg)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(timeToRestoreCmd)
+	timeToRestoreCmd.Flags().String("last", "", "Specify the time window to analyze (e.g., 30d, 6m, 1y)")
+	timeToRestoreCmd.Flags().Int("limit", 10, "Number of incidents to show")
+	addIncidentFlags(timeToRestoreCmd)
+	addDeploymentFlags(timeToRestoreCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestClassifyDORATimeToRestore(t *testing.T) {
+	tests := []struct {
+		hours    float64
+		expected string
+	}{
+		{0.5, "Elite"},
+		{1, "High"},
+		{23, "High"},
+		{24, "Medium"},
+		{167, "Medium"},
+		{168, "Low"},
+	}
+
+	for _, tt := range tests {
+		if result := classifyDORATimeToRestore(tt.hours); result != tt.expected {
+			t.Errorf("classifyDORATimeToRestore(%.1f) = %s, want %s", tt.hours, result, tt.expected)
+		}
+	}
+}
+
+func TestMatchIncidentRestores(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	hour := func(n int) time.Time { return start.Add(time.Duration(n) * time.Hour) }
+	fix := plumbing.NewHash("cccc")
+	deployments := []Deployment{
+		{Time: hour(2), Ref: "v1"},
+		{Time: hour(4), Ref: "v2"},
+		{Time: hour(10), Ref: "v3"},
+	}
+	incidents := []Incident{
+		{ID: "with-commit", Start: hour(1), End: hour(3), RestoreHash: fix},
+		{ID: "with-end", Start: hour(1), End: hour(5)},
+		{ID: "no-end", Start: hour(5)},
+		{ID: "open", Start: hour(11)},
+	}
+
+	restores := matchIncidentRestores(incidents, deployments)
+	if r := restores[0]; !r.Restored || r.Hash != fix || r.Duration() != 2*time.Hour {
+		t.Errorf("Expected the end marker's commit to restore, got %+v", r)
+	}
+	// The last deployment before the end marker restored service
+	if r := restores[1]; r.RestoredBy != "deploy v2" || r.Duration() != 4*time.Hour || r.Inferred {
+		t.Errorf("Expected v2 to restore within 4h, got %+v", r)
+	}
+	// Without an end marker the next deployment gives the restore time
+	if r := restores[2]; r.RestoredBy != "deploy v3" || r.Duration() != 5*time.Hour || !r.Inferred {
+		t.Errorf("Expected v3 to restore within 5h, got %+v", r)
+	}
+	if restores[3].Restored {
+		t.Errorf("Expected the last incident to be open, got %+v", restores[3])
+	}
+
+	stats := calculateTimeToRestoreStats(restores, 1)
+	if stats.Restored != 3 || stats.Open != 1 || stats.Unmatched != 1 {
+		t.Errorf("Expected 3 restored, 1 open and 1 unmatched, got %d, %d and %d", stats.Restored, stats.Open, stats.Unmatched)
+	}
+	if stats.MedianHours != 4 || stats.Classification != "High" {
+		t.Errorf("Expected a 4h median (High), got %.1f (%s)", stats.MedianHours, stats.Classification)
+	}
+	if stats.Restores[0].Incident.ID != "no-end" || stats.Restores[3].Incident.ID != "open" {
+		t.Errorf("Expected longest first and open incidents last, got %s ... %s", stats.Restores[0].Incident.ID, stats.Restores[3].Incident.ID)
+	}
+}
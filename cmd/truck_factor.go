@@ -0,0 +1,323 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Degree-of-authorship model (Fritz et al., calibrated by Avelino et al. 2016):
+// DOA = 3.293 + 1.098*FA + 0.164*DL - 0.321*ln(1 + AC). A developer authors a
+// file when their DOA is at least the constant term and within 75% of the
+// file's top DOA.
+const (
+	doaBase                = 3.293
+	doaFirstAuthorWeight   = 1.098
+	doaDeliveryWeight      = 0.164
+	doaAcceptanceWeight    = 0.321
+	doaNormalizedThreshold = 0.75
+)
+
+const truckFactorBenchmarkContext = "Avelino et al. found a truck factor of 1 or 2 in 65% of 133 popular GitHub projects."
+
+// fileAuthorship is the history of one HEAD file the DOA model needs
+type fileAuthorship struct {
+	FirstAuthor string         // Who created the file; empty when created before the window
+	Deliveries  map[string]int // Commits to the file by each author
+}
+
+// degreeOfAuthorship scores a developer's authorship of a file from whether
+// they created it, how many changes they made and how many changes others made
+func degreeOfAuthorship(firstAuthor bool, deliveries, acceptances int) float64 {
+	fa := 0.0
+	if firstAuthor {
+		fa = 1
+	}
+	return doaBase + doaFirstAuthorWeight*fa + doaDeliveryWeight*float64(deliveries) - doaAcceptanceWeight*math.Log(1+float64(acceptances))
+}
+
+// authors returns the file's authors under the DOA model, sorted by name
+func (f *fileAuthorship) authors() []string {
+	total := 0
+	for _, n := range f.Deliveries {
+		total += n
+	}
+
+	scores := make(map[string]float64, len(f.Deliveries))
+	best := 0.0
+	for author, n := range f.Deliveries {
+		doa := degreeOfAuthorship(author == f.FirstAuthor, n, total-n)
+		scores[author] = doa
+		best = math.Max(best, doa)
+	}
+
+	var authors []string
+	for author, doa := range scores {
+		if doa >= doaBase && doa/best > doaNormalizedThreshold {
+			authors = append(authors, author)
+		}
+	}
+	sort.Strings(authors)
+	return authors
+}
+
+// TruckFactorStep is one author's removal in the truck factor calculation
+type TruckFactorStep struct {
+	Author             string   `json:"author"`
+	FilesAuthored      int      `json:"files_authored"`
+	Orphaned           []string `json:"orphaned"` // Files left without an author by this removal
+	TotalOrphaned      int      `json:"total_orphaned"`
+	OrphanedPercentage float64  `json:"orphaned_percentage"`
+}
+
+// TruckFactorAnalysis is the repository-level truck factor
+type TruckFactorAnalysis struct {
+	TruckFactor int                 `json:"truck_factor"`
+	Authors     []string            `json:"authors"` // The authors in the truck factor, in removal order
+	TotalFiles  int                 `json:"total_files"`
+	Unowned     []string            `json:"unowned"` // Files no one authors, orphaned before any removal
+	Steps       []TruckFactorStep   `json:"steps"`
+	FileAuthors map[string][]string `json:"-"`
+}
+
+// calculateTruckFactor removes authors in order of files authored, most
+// first, until more than half the files have no author left. The truck
+// factor is the number of authors removed.
+func calculateTruckFactor(files map[string]*fileAuthorship) *TruckFactorAnalysis {
+	analysis := &TruckFactorAnalysis{
+		TotalFiles:  len(files),
+		FileAuthors: make(map[string][]string, len(files)),
+		Authors:     []string{},
+		Unowned:     []string{},
+		Steps:       []TruckFactorStep{},
+	}
+
+	remaining := make(map[string]int, len(files))
+	authorFiles := make(map[string][]string)
+	for path, f := range files {
+		authors := f.authors()
+		analysis.FileAuthors[path] = authors
+		remaining[path] = len(authors)
+		if len(authors) == 0 {
+			analysis.Unowned = append(analysis.Unowned, path)
+		}
+		for _, author := range authors {
+			authorFiles[author] = append(authorFiles[author], path)
+		}
+	}
+	sort.Strings(analysis.Unowned)
+
+	ranking := make([]string, 0, len(authorFiles))
+	for author := range authorFiles {
+		ranking = append(ranking, author)
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if len(authorFiles[ranking[i]]) != len(authorFiles[ranking[j]]) {
+			return len(authorFiles[ranking[i]]) > len(authorFiles[ranking[j]])
+		}
+		return ranking[i] < ranking[j]
+	})
+
+	orphaned := len(analysis.Unowned)
+	for _, author := range ranking {
+		if orphaned*2 > analysis.TotalFiles {
+			break
+		}
+		step := TruckFactorStep{Author: author, FilesAuthored: len(authorFiles[author]), Orphaned: []string{}}
+		for _, path := range authorFiles[author] {
+			remaining[path]--
+			if remaining[path] == 0 {
+				step.Orphaned = append(step.Orphaned, path)
+			}
+		}
+		sort.Strings(step.Orphaned)
+		orphaned += len(step.Orphaned)
+		step.TotalOrphaned = orphaned
+		step.OrphanedPercentage = float64(orphaned) / float64(analysis.TotalFiles) * 100
+		analysis.Steps = append(analysis.Steps, step)
+		analysis.Authors = append(analysis.Authors, author)
+	}
+	analysis.TruckFactor = len(analysis.Authors)
+	return analysis
+}
+
+// collectFileAuthorship walks history newest first and records, for every
+// file at HEAD, who created it and how many commits each author made to it.
+// Renames are followed back to the file's original path. Merges count as
+// deliveries under the active merge policy but don't create or rename files.
+func collectFileAuthorship(repo *git.Repository, headCommit *object.Commit, since time.Time, pathFilters []string) (map[string]*fileAuthorship, error) {
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD tree: %v", err)
+	}
+
+	// headPath maps a file's path at the commit being visited to its HEAD path
+	headPath := make(map[string]string)
+	err = headTree.Files().ForEach(func(f *object.File) error {
+		if matchesPathFilter(f.Name, pathFilters) {
+			headPath[f.Name] = f.Name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list HEAD files: %v", err)
+	}
+
+	cIter, err := logCommits(repo, &git.LogOptions{From: headCommit.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	defer cIter.Close()
+
+	var commits []*object.Commit
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if since.IsZero() || !c.Committer.When.Before(since) {
+			commits = append(commits, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	ordered := orderSurvivalCommits(commits)
+
+	files := make(map[string]*fileAuthorship)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		c := ordered[i]
+		sets, err := commitRenameChanges(c)
+		if err != nil {
+			return nil, fmt.Errorf("could not diff %s: %v", c.Hash.String(), err)
+		}
+		var changes object.Changes
+		for _, set := range sets {
+			changes = append(changes, set...)
+		}
+
+		author := normalizeAuthorName(c.Author.Name, c.Author.Email)
+		merge := c.NumParents() > 1
+		delivered := make(map[string]bool)
+		renamed := make(map[string]string)
+		var created []string
+		for _, change := range changes {
+			if change.To.Name == "" {
+				continue
+			}
+			path, ok := headPath[change.To.Name]
+			if !ok {
+				continue
+			}
+			if !delivered[path] {
+				delivered[path] = true
+				if files[path] == nil {
+					files[path] = &fileAuthorship{Deliveries: make(map[string]int)}
+				}
+				files[path].Deliveries[author]++
+			}
+			if merge {
+				continue
+			}
+			if change.From.Name == "" {
+				files[path].FirstAuthor = author
+				created = append(created, change.To.Name)
+			} else if change.From.Name != change.To.Name {
+				renamed[change.From.Name] = path
+				created = append(created, change.To.Name)
+			}
+		}
+
+		// Older commits see files under their earlier names, and paths
+		// created or renamed to here belonged to some other file before
+		for _, name := range created {
+			delete(headPath, name)
+		}
+		for from, path := range renamed {
+			headPath[from] = path
+		}
+	}
+	return files, nil
+}
+
+// analyzeTruckFactor computes the repository truck factor at HEAD
+func analyzeTruckFactor(repo *git.Repository, since time.Time, pathFilters []string) (*TruckFactorAnalysis, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD: %v", err)
+	}
+	headCommit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD commit: %v", err)
+	}
+
+	files, err := collectFileAuthorship(repo, headCommit, since, pathFilters)
+	if err != nil {
+		return nil, err
+	}
+	return calculateTruckFactor(files), nil
+}
+
+// printTruckFactor prints the truck factor and the files each removal orphans
+func printTruckFactor(analysis *TruckFactorAnalysis, limit int) {
+	fmt.Printf("\nTruck Factor (degree of authorship)\n")
+	fmt.Printf("Files analyzed: %d\n", analysis.TotalFiles)
+	if analysis.TotalFiles == 0 {
+		fmt.Println("No files with history in the analyzed window.")
+		return
+	}
+	fmt.Printf("Truck factor: %d", analysis.TruckFactor)
+	if len(analysis.Authors) > 0 {
+		fmt.Printf(" (%s)", strings.Join(analysis.Authors, ", "))
+	}
+	fmt.Println()
+	fmt.Println("Context:", truckFactorBenchmarkContext)
+
+	if len(analysis.Unowned) > 0 {
+		fmt.Printf("\nFiles without an author before any removal: %d\n", len(analysis.Unowned))
+		printTruckFactorFiles(analysis.Unowned, limit)
+	}
+
+	if len(analysis.Steps) > 0 {
+		fmt.Printf("\nRemoving top authors until more than half the files are orphaned:\n")
+	}
+	for i, step := range analysis.Steps {
+		fmt.Printf("  %d. %s authors %d files; %d newly orphaned, %d total (%.1f%%)\n",
+			i+1, step.Author, step.FilesAuthored, len(step.Orphaned), step.TotalOrphaned, step.OrphanedPercentage)
+		printTruckFactorFiles(step.Orphaned, limit)
+	}
+}
+
+// printTruckFactorFiles lists up to limit files
+func printTruckFactorFiles(files []string, limit int) {
+	for i, path := range files {
+		if i >= limit {
+			fmt.Printf("       ... and %d more\n", len(files)-limit)
+			break
+		}
+		fmt.Printf("       %s\n", path)
+	}
+}
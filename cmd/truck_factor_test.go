@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestDegreeOfAuthorship(t *testing.T) {
+	tests := []struct {
+		name        string
+		firstAuthor bool
+		deliveries  int
+		acceptances int
+		want        float64
+	}{
+		{"creator with no other changes", true, 1, 0, 4.555},
+		{"creator after others changed it", true, 1, 4, 4.555 - 0.321*math.Log(5)},
+		{"single edit to someone else's file", false, 1, 1, 3.457 - 0.321*math.Log(2)},
+		{"frequent contributor", false, 10, 2, 4.933 - 0.321*math.Log(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := degreeOfAuthorship(tt.firstAuthor, tt.deliveries, tt.acceptances)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("degreeOfAuthorship() = %.4f, want %.4f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileAuthorshipAuthors(t *testing.T) {
+	tests := []struct {
+		name string
+		file fileAuthorship
+		want []string
+	}{
+		{
+			name: "creator alone",
+			file: fileAuthorship{FirstAuthor: "alice", Deliveries: map[string]int{"alice": 3}},
+			want: []string{"alice"},
+		},
+		{
+			name: "one-off edit doesn't make an author",
+			file: fileAuthorship{FirstAuthor: "alice", Deliveries: map[string]int{"alice": 1, "bob": 1}},
+			want: []string{"alice"},
+		},
+		{
+			name: "regular contributor shares authorship",
+			file: fileAuthorship{FirstAuthor: "alice", Deliveries: map[string]int{"alice": 1, "carol": 4}},
+			want: []string{"alice", "carol"},
+		},
+		{
+			name: "created before the window",
+			file: fileAuthorship{Deliveries: map[string]int{"bob": 4, "carol": 4}},
+			want: []string{"bob", "carol"},
+		},
+		{
+			name: "diluted by many contributors",
+			file: fileAuthorship{Deliveries: map[string]int{"a": 1, "b": 1, "c": 1, "d": 1, "e": 1}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.file.authors(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("authors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateTruckFactor(t *testing.T) {
+	solo := func(author string) *fileAuthorship {
+		return &fileAuthorship{FirstAuthor: author, Deliveries: map[string]int{author: 2}}
+	}
+
+	t.Run("single author owns most files", func(t *testing.T) {
+		got := calculateTruckFactor(map[string]*fileAuthorship{
+			"a.go": solo("alice"),
+			"b.go": solo("alice"),
+			"c.go": solo("alice"),
+			"d.go": solo("bob"),
+		})
+		if got.TruckFactor != 1 || !reflect.DeepEqual(got.Authors, []string{"alice"}) {
+			t.Fatalf("truck factor = %d %v, want 1 [alice]", got.TruckFactor, got.Authors)
+		}
+		step := got.Steps[0]
+		if step.FilesAuthored != 3 || step.TotalOrphaned != 3 || step.OrphanedPercentage != 75 {
+			t.Errorf("step = %+v, want 3 files authored and orphaned (75%%)", step)
+		}
+		if !reflect.DeepEqual(step.Orphaned, []string{"a.go", "b.go", "c.go"}) {
+			t.Errorf("orphaned = %v", step.Orphaned)
+		}
+	})
+
+	t.Run("shared files survive the first removal", func(t *testing.T) {
+		shared := &fileAuthorship{FirstAuthor: "alice", Deliveries: map[string]int{"alice": 1, "bob": 4}}
+		got := calculateTruckFactor(map[string]*fileAuthorship{
+			"a.go": shared,
+			"b.go": shared,
+			"c.go": solo("alice"),
+			"d.go": solo("carol"),
+		})
+		if got.TruckFactor != 2 || !reflect.DeepEqual(got.Authors, []string{"alice", "bob"}) {
+			t.Fatalf("truck factor = %d %v, want 2 [alice bob]", got.TruckFactor, got.Authors)
+		}
+		if !reflect.DeepEqual(got.Steps[0].Orphaned, []string{"c.go"}) {
+			t.Errorf("first removal orphaned %v, want [c.go]", got.Steps[0].Orphaned)
+		}
+		if !reflect.DeepEqual(got.Steps[1].Orphaned, []string{"a.go", "b.go"}) {
+			t.Errorf("second removal orphaned %v, want [a.go b.go]", got.Steps[1].Orphaned)
+		}
+	})
+
+	t.Run("mostly unowned files", func(t *testing.T) {
+		diluted := &fileAuthorship{Deliveries: map[string]int{"a": 1, "b": 1, "c": 1, "d": 1, "e": 1}}
+		got := calculateTruckFactor(map[string]*fileAuthorship{
+			"a.go": diluted,
+			"b.go": diluted,
+			"c.go": solo("alice"),
+		})
+		if got.TruckFactor != 0 || len(got.Steps) != 0 {
+			t.Errorf("truck factor = %d with %d steps, want 0", got.TruckFactor, len(got.Steps))
+		}
+		if !reflect.DeepEqual(got.Unowned, []string{"a.go", "b.go"}) {
+			t.Errorf("unowned = %v", got.Unowned)
+		}
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		if got := calculateTruckFactor(nil); got.TruckFactor != 0 || got.TotalFiles != 0 {
+			t.Errorf("got %+v, want an empty analysis", got)
+		}
+	})
+}
+
+func TestCollectFileAuthorshipRenameOverOldName(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := newTestRepo(t)
+	// erin's b.txt is deleted before alice's a.txt is renamed to b.txt, so
+	// erin had no part in the file at HEAD
+	testRepoCommit(t, repo, map[string]string{"b.txt": "unrelated\n"}, "erin", start)
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := wt.Remove("b.txt"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sig := &object.Signature{Name: "erin", Email: "erin@example.com", When: start.Add(time.Hour)}
+	if _, err := wt.Commit("Remove b.txt", &git.CommitOptions{Author: sig, Committer: sig}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	testRepoCommit(t, repo, map[string]string{"a.txt": "one\ntwo\nthree\nfour\n"}, "alice", start.Add(2*time.Hour))
+	if _, err := wt.Move("a.txt", "b.txt"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sig = &object.Signature{Name: "bob", Email: "bob@example.com", When: start.Add(3 * time.Hour)}
+	head, err := wt.Commit("Rename a.txt", &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	headCommit, err := repo.CommitObject(head)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	files, err := collectFileAuthorship(repo, headCommit, time.Time{}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b := files["b.txt"]
+	if b == nil {
+		t.Fatalf("Expected authorship for b.txt, got %v", files)
+	}
+	want := map[string]int{"alice": 1, "bob": 1}
+	if b.FirstAuthor != "alice" || !reflect.DeepEqual(b.Deliveries, want) {
+		t.Errorf("Expected alice's file renamed by bob, got first author %s and deliveries %v", b.FirstAuthor, b.Deliveries)
+	}
+}
@@ -0,0 +1,363 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// headFilePaths lists the files at HEAD that match the path filters, for the
+// directory navigator
+func headFilePaths(repo *git.Repository, pathFilters []string) ([]string, error) {
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD: %v", err)
+	}
+	commit, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD commit: %v", err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD tree: %v", err)
+	}
+
+	var paths []string
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if matchesPathFilter(f.Name, pathFilters) {
+			paths = append(paths, f.Name)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// scopedPathFilters narrows the command's path filters to a tree scope: the
+// filters inside the scope when there are any, otherwise the scope itself
+func scopedPathFilters(pathFilters []string, scope string) []string {
+	if scope == "" {
+		return pathFilters
+	}
+	var narrowed []string
+	for _, filter := range pathFilters {
+		if matchesPathFilter(filter, []string{scope}) {
+			narrowed = append(narrowed, filter)
+		}
+	}
+	if len(narrowed) > 0 {
+		return narrowed
+	}
+	return []string{scope}
+}
+
+// tuiFileFacts is what the detail pane shows about one file at HEAD
+type tuiFileFacts struct {
+	Path          string
+	Additions     int
+	Deletions     int
+	Changes       int // commits touching the file
+	LastModified  time.Time
+	AuthorChanges map[string]int       // commits per normalized author
+	AuthorLines   map[string]int       // lines added per normalized author
+	Monthly       map[string]lineDelta // line changes keyed by "2006-01"
+}
+
+// record adds one commit's change to the file
+func (f *tuiFileFacts) record(author string, when time.Time, delta lineDelta) {
+	f.Additions += delta.Additions
+	f.Deletions += delta.Deletions
+	f.Changes++
+	f.AuthorChanges[author]++
+	f.AuthorLines[author] += delta.Additions
+	if when.After(f.LastModified) {
+		f.LastModified = when
+	}
+	month := when.Format("2006-01")
+	m := f.Monthly[month]
+	m.Additions += delta.Additions
+	m.Deletions += delta.Deletions
+	f.Monthly[month] = m
+}
+
+// collectTUIFileFacts walks history once and gathers the ownership, churn
+// history and last change of each HEAD file in a scope
+func collectTUIFileFacts(repo *git.Repository, files []string, since time.Time, pathFilters []string) (map[string]*tuiFileFacts, error) {
+	facts := make(map[string]*tuiFileFacts)
+	for _, p := range files {
+		if matchesPathFilter(p, pathFilters) {
+			facts[p] = &tuiFileFacts{
+				Path:          p,
+				AuthorChanges: make(map[string]int),
+				AuthorLines:   make(map[string]int),
+				Monthly:       make(map[string]lineDelta),
+			}
+		}
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get HEAD: %v", err)
+	}
+	cIter, err := logCommits(repo, &git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("could not get commits: %v", err)
+	}
+	defer cIter.Close()
+
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if !since.IsZero() && c.Committer.When.Before(since) {
+			return storer.ErrStop
+		}
+		if !includeMergeCommit(c) {
+			return nil
+		}
+
+		changes := make(map[string]lineDelta)
+		if c.NumParents() == 0 {
+			// A root commit is diffed against the empty tree
+			patches, err := commitRenamePatches(c)
+			if err != nil {
+				return nil // Skip commits with errors
+			}
+			for _, patch := range patches {
+				for _, stat := range patch.Stats() {
+					changes[stat.Name] = lineDelta{Additions: stat.Addition, Deletions: stat.Deletion}
+				}
+			}
+		} else {
+			lineChanges, err := commitLineChanges(c, pathFilters)
+			if err != nil {
+				return nil // Skip commits with errors
+			}
+			changes = lineChanges
+		}
+
+		author := normalizeAuthorName(c.Author.Name, c.Author.Email)
+		for p, delta := range changes {
+			if f, ok := facts[p]; ok {
+				f.record(author, c.Committer.When, delta)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking commits: %v", err)
+	}
+	return facts, nil
+}
+
+// analyzerTUILoader runs the registered analyzers, the same ones health-check
+// runs, with the path filters of a scope
+func analyzerTUILoader(repo *git.Repository, files []string, since time.Time, pathFilters []string) func(scope string) *tuiResults {
+	return func(scope string) *tuiResults {
+		analyzers := RegisteredAnalyzers()
+		filters := scopedPathFilters(pathFilters, scope)
+		ctx := &AnalysisContext{Repo: repo, Since: since, PathFilters: filters}
+		results, issues, err := runAnalyzers(ctx, analyzers)
+		if err != nil {
+			return &tuiResults{Err: err}
+		}
+		r := newTUIResults(analyzers, results, issues)
+		if r.Files, err = collectTUIFileFacts(repo, files, since, filters); err != nil {
+			log.Printf("could not collect file details for %s: %v", scopeLabel(scope), err)
+		}
+		return r
+	}
+}
+
+// runTUI drives the interactive loop on the controlling terminal. Only plain
+// ANSI escapes are used, so it works over SSH in any VT100-compatible terminal.
+func runTUI(state *tuiState) error {
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return fmt.Errorf("tui needs an interactive terminal; use the individual commands when piping output")
+	}
+
+	oldState, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("could not switch terminal to raw mode: %v", err)
+	}
+	defer term.Restore(inFd, oldState)
+
+	out := bufio.NewWriter(os.Stdout)
+	out.WriteString("\x1b[?1049h\x1b[?25l") // alternate screen, hide cursor
+	defer func() {
+		out.WriteString("\x1b[?25h\x1b[?1049l")
+		out.Flush()
+	}()
+
+	keys := make(chan []tuiKey)
+	readErrs := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				readErrs <- err
+				return
+			}
+			keys <- parseTUIKeys(buf[:n])
+		}
+	}()
+
+	// Terminals resized over SSH send no input, so poll the size
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	lastWidth, lastHeight := -1, -1
+	redraw := true
+	for {
+		width, height, err := term.GetSize(outFd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		if !state.loaded() {
+			// Analyzing a new scope re-walks history, so say so first
+			writeTUIFrame(out, state.renderLoading(width, height))
+			state.load()
+			redraw = true
+		}
+		if redraw || width != lastWidth || height != lastHeight {
+			writeTUIFrame(out, state.render(width, height))
+			lastWidth, lastHeight = width, height
+		}
+		redraw = false
+
+		select {
+		case batch := <-keys:
+			for _, k := range batch {
+				if state.handleKey(k) {
+					return nil
+				}
+			}
+			redraw = true
+		case err := <-readErrs:
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeTUIFrame repaints the screen from the top-left corner
+func writeTUIFrame(out *bufio.Writer, lines []string) {
+	out.WriteString("\x1b[H")
+	for i, line := range lines {
+		out.WriteString(line)
+		out.WriteString("\x1b[K")
+		if i < len(lines)-1 {
+			out.WriteString("\r\n")
+		}
+	}
+	out.WriteString("\x1b[J")
+	out.Flush()
+}
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse every analyzer's results interactively",
+	Long: `Open an interactive terminal UI over the results of the analyzers
+health-check runs (churn, test-ratio, bus-factor, dead-zones, commit-size and
+any custom analyzers compiled in with RegisterAnalyzer).
+
+- One table per analyzer, sortable by any column and filterable by name, plus
+  a table of the health issues they raised
+- A directory tree navigator; opening a directory re-runs the analyzers with
+  it as the path filter, so the numbers match the commands run with --path
+- A detail pane with every field of the selected row, such as a directory's
+  contributors and their share; rows naming a file add its owners, monthly
+  churn history and last change
+
+Each scope is analyzed once and cached, so switching back is instant.
+Analyzers run with their default options.
+
+Keys:
+  1-9 or [ ]   switch between analyzer tables
+  Tab          move focus between the tree and the table
+  Up/Down j/k  move the selection (PgUp/PgDn, Home/End)
+  Enter        open the selected directory (tree, or a table row naming one)
+  Backspace    go up one directory
+  s / S        sort by the next / previous column
+  r            reverse the sort order
+  /            filter rows by name (Enter to apply, Esc to clear)
+  q            quit
+
+Only plain ANSI escape sequences are used, so the UI works over SSH in any
+VT100-compatible terminal.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lastArg, _ := cmd.Flags().GetString("last")
+		pathFilters, source := getConfigPaths(cmd, "tui.paths")
+
+		// Print configuration scope
+		printCommandScope(cmd, "tui", lastArg, pathFilters, source)
+
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			return fmt.Errorf("could not open repository: %v", err)
+		}
+
+		since := time.Time{}
+		if lastArg != "" {
+			cutoff, err := parseDurationArg(lastArg)
+			if err != nil {
+				return fmt.Errorf("could not parse --last argument: %v", err)
+			}
+			since = cutoff
+		}
+
+		files, err := headFilePaths(repo, pathFilters)
+		if err != nil {
+			return err
+		}
+
+		// Analyzer failures are logged; hold them until the screen is restored
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer func() {
+			log.SetOutput(os.Stderr)
+			os.Stderr.Write(logs.Bytes())
+		}()
+
+		window := "all time"
+		if !since.IsZero() {
+			window = fmt.Sprintf("since %s", since.Format("2006-01-02"))
+		}
+		return runTUI(newTUIState(files, window, analyzerTUILoader(repo, files, since, pathFilters)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.Flags().String("last", "", "Limit analysis to a timeframe (e.g. 7d, 2m, 1y)")
+	tuiCmd.Flags().StringSlice("path", []string{}, "Limit analysis to specific paths (can be specified multiple times)")
+}
@@ -0,0 +1,438 @@
+package cmd
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// testTUIFiles are the HEAD files of the test repository
+var testTUIFiles = []string{"src/a.go", "src/util/b.go", "docs/guide.md"}
+
+// testTUIAnalysis is a bus factor result like the bus-factor analyzer returns
+func testTUIAnalysis() *BusFactorAnalysis {
+	return summarizeBusFactor(map[string]map[string]int{
+		"src/":      {"alice@x.com": 3, "bob@x.com": 1},
+		"src/util/": {"alice@x.com": 2},
+		"docs/":     {"carol@x.com": 1, "dave@x.com": 1},
+	}, time.Time{})
+}
+
+// newTestTUIState returns a state whose loader records the scopes it analyzed
+func newTestTUIState(scopes *[]string) *tuiState {
+	loader := func(scope string) *tuiResults {
+		*scopes = append(*scopes, scope)
+		return &tuiResults{
+			Views: []string{"bus-factor", "test-ratio", tuiIssuesView},
+			Tables: map[string]*tuiTable{
+				"bus-factor":  buildTUITable(testTUIAnalysis()),
+				"test-ratio":  buildTUITable(&TestRatioStats{TestLOC: 10, SourceLOC: 40, TestRatio: 0.25}),
+				tuiIssuesView: buildTUITable([]HealthIssue{{Metric: "bus-factor", Severity: "Critical", Description: "Knowledge concentration risk in src/"}}),
+			},
+		}
+	}
+	return newTUIState(testTUIFiles, "all time", loader)
+}
+
+func TestParseTUIKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []tuiKey
+	}{
+		{"arrows", "\x1b[A\x1b[B", []tuiKey{{Name: "up"}, {Name: "down"}}},
+		{"ss3 arrows", "\x1bOC", []tuiKey{{Name: "right"}}},
+		{"page keys", "\x1b[5~\x1b[6~", []tuiKey{{Name: "pgup"}, {Name: "pgdn"}}},
+		{"lone escape", "\x1b", []tuiKey{{Name: "esc"}}},
+		{"enter and backspace", "\r\x7f", []tuiKey{{Name: "enter"}, {Name: "backspace"}}},
+		{"runes", "q/é", []tuiKey{{Rune: 'q'}, {Rune: '/'}, {Rune: 'é'}}},
+		{"ctrl-c", "\x03", []tuiKey{{Name: "ctrl-c"}}},
+		{"unknown sequence skipped", "\x1b[99zj", []tuiKey{{Rune: 'j'}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseTUIKeys([]byte(tt.input))
+			if len(result) != len(tt.expected) {
+				t.Fatalf("parseTUIKeys(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("key %d: got %v, want %v", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChildDirectories(t *testing.T) {
+	dirs, counts := childDirectories(testTUIFiles, "")
+	if strings.Join(dirs, ",") != "docs,src" {
+		t.Errorf("Expected root children docs,src, got %v", dirs)
+	}
+	if counts["src"] != 2 {
+		t.Errorf("Expected 2 files under src, got %d", counts["src"])
+	}
+
+	dirs, _ = childDirectories(testTUIFiles, "src")
+	if strings.Join(dirs, ",") != "src/util" {
+		t.Errorf("Expected src children src/util, got %v", dirs)
+	}
+}
+
+func TestScopedPathFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  []string
+		scope    string
+		expected string
+	}{
+		{"root keeps the command filters", []string{"src/", "docs/"}, "", "src/,docs/"},
+		{"scope without filters", nil, "src", "src"},
+		{"scope inside a filter", []string{"src/"}, "src/util", "src/util"},
+		{"filters inside the scope", []string{"src/a.go", "docs/"}, "src", "src/a.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := strings.Join(scopedPathFilters(tt.filters, tt.scope), ","); result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildTUITable(t *testing.T) {
+	analysis := testTUIAnalysis()
+	table := buildTUITable(analysis)
+
+	if table.Columns[0].Title != "Path" || table.Columns[0].Width != 0 {
+		t.Errorf("Expected the Path column to lead and stretch, got %+v", table.Columns[0])
+	}
+	if len(table.Rows) != len(analysis.DirectoryStats) {
+		t.Fatalf("Expected %d rows, got %d", len(analysis.DirectoryStats), len(table.Rows))
+	}
+	busFactorCol := -1
+	for i, col := range table.Columns {
+		if col.Title == "BusFactor" {
+			busFactorCol = i
+		}
+	}
+	if busFactorCol < 0 {
+		t.Fatalf("Expected a BusFactor column, got %+v", table.Columns)
+	}
+	// Rows keep the analyzer's order and numbers
+	for i, stats := range analysis.DirectoryStats {
+		if table.Rows[i].Key != stats.Path || table.Rows[i].Values[busFactorCol] != stats.BusFactor {
+			t.Errorf("Row %d: expected %s with bus factor %d, got %v", i, stats.Path, stats.BusFactor, table.Rows[i].Values)
+		}
+	}
+	if !strings.Contains(table.Summary, "TotalDirectories: 3") {
+		t.Errorf("Expected the summary to include TotalDirectories, got %q", table.Summary)
+	}
+	detail := strings.Join(table.Rows[0].Detail, "\n")
+	if !strings.Contains(detail, "AuthorLines: ") {
+		t.Errorf("Expected the detail to include the author breakdown, got %q", detail)
+	}
+
+	// Results without a list of records become field/value rows
+	ratio := buildTUITable(&TestRatioStats{TestLOC: 10, SourceLOC: 40, TestRatio: 0.25})
+	if ratio.Columns[0].Title != "Field" || ratio.Rows[0].Key != "TestLOC" || ratio.Rows[0].Values[1] != "10" {
+		t.Errorf("Expected field/value rows, got %+v", ratio.Rows[0])
+	}
+
+	var missing *DeadZoneAnalysis
+	if buildTUITable(missing).Err == "" {
+		t.Error("Expected an empty result to be reported")
+	}
+}
+
+func TestDescribeTUIField(t *testing.T) {
+	result := describeTUIField(reflect.ValueOf(map[string]int{"bob": 1, "alice": 3, "carol": 1}))
+	if result != "alice 3, bob 1, carol 1" {
+		t.Errorf("Expected largest value first, got %q", result)
+	}
+}
+
+func TestFilterAndSortTUIRows(t *testing.T) {
+	rows := []tuiRow{
+		{Key: "a", Values: []interface{}{"alpha.go", 10}},
+		{Key: "b", Values: []interface{}{"beta.go", 30}},
+		{Key: "c", Values: []interface{}{"gamma.md", 20}},
+	}
+
+	tests := []struct {
+		name     string
+		filter   string
+		col      int
+		desc     bool
+		expected string
+	}{
+		{"numeric descending", "", 1, true, "b,c,a"},
+		{"numeric ascending", "", 1, false, "a,c,b"},
+		{"name ascending", "", 0, false, "a,b,c"},
+		{"filtered", ".GO", 1, true, "b,a"},
+		{"no matches", "rust", 0, false, ""},
+		{"reported order", "", -1, false, "a,b,c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			for _, row := range filterAndSortTUIRows(rows, tt.filter, tt.col, tt.desc) {
+				keys = append(keys, row.Key)
+			}
+			if strings.Join(keys, ",") != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, strings.Join(keys, ","))
+			}
+		})
+	}
+}
+
+func TestTUIStateNavigation(t *testing.T) {
+	var scopes []string
+	s := newTestTUIState(&scopes)
+
+	// Tree at the root lists docs and src; open src
+	s.handleKey(tuiKey{Name: "tab"})
+	s.handleKey(tuiKey{Name: "down"})
+	s.handleKey(tuiKey{Name: "enter"})
+	if s.scope != "src" {
+		t.Fatalf("Expected scope src, got %q", s.scope)
+	}
+	if s.loaded() {
+		t.Error("Expected src to be analyzed on demand")
+	}
+	s.load()
+
+	// Going up restores the root and keeps src selected in the tree
+	s.handleKey(tuiKey{Name: "backspace"})
+	if s.scope != "" {
+		t.Fatalf("Expected root scope, got %q", s.scope)
+	}
+	entries, _ := s.treeEntries()
+	if entries[s.treeSel] != "src" {
+		t.Errorf("Expected src selected after going up, got %s", entries[s.treeSel])
+	}
+
+	// A bus-factor row naming a directory opens it
+	s.handleKey(tuiKey{Rune: '1'})
+	s.handleKey(tuiKey{Rune: '/'})
+	for _, r := range "util" {
+		s.handleKey(tuiKey{Rune: r})
+	}
+	s.handleKey(tuiKey{Name: "enter"})
+	s.handleKey(tuiKey{Name: "enter"})
+	if s.scope != "src/util" {
+		t.Errorf("Expected scope src/util, got %q", s.scope)
+	}
+	s.load()
+
+	// Each scope is analyzed once
+	s.goUp()
+	s.goUp()
+	s.load()
+	if strings.Join(scopes, ",") != "src,,src/util" {
+		t.Errorf("Expected scopes root, src and src/util analyzed once each, got %q", scopes)
+	}
+
+	if !s.handleKey(tuiKey{Rune: 'q'}) {
+		t.Error("Expected q to quit")
+	}
+}
+
+func TestTUIViewsAndSorting(t *testing.T) {
+	var scopes []string
+	s := newTestTUIState(&scopes)
+
+	s.handleKey(tuiKey{Rune: '['})
+	if s.viewName() != tuiIssuesView {
+		t.Fatalf("Expected [ to wrap to the issues table, got %s", s.viewName())
+	}
+	s.handleKey(tuiKey{Rune: ']'})
+	if s.viewName() != "bus-factor" {
+		t.Fatalf("Expected ] to wrap to bus-factor, got %s", s.viewName())
+	}
+
+	// Sorting starts from the analyzer's order and cycles back to it
+	if col, _ := s.sortOf(); col != -1 {
+		t.Errorf("Expected analyzer order first, got column %d", col)
+	}
+	s.handleKey(tuiKey{Rune: 's'})
+	if col, desc := s.sortOf(); col != 0 || desc {
+		t.Errorf("Expected the name column ascending, got %d desc=%v", col, desc)
+	}
+	s.handleKey(tuiKey{Rune: 'S'})
+	if col, _ := s.sortOf(); col != -1 {
+		t.Errorf("Expected S to step back to analyzer order, got %d", col)
+	}
+}
+
+func TestNewTUIResults(t *testing.T) {
+	analyzers := []Analyzer{&busFactorHealthAnalyzer{}, &testRatioHealthAnalyzer{}}
+	results := newTUIResults(analyzers, map[string]interface{}{"bus-factor": testTUIAnalysis()}, nil)
+
+	if strings.Join(results.Views, ",") != "bus-factor,test-ratio,issues" {
+		t.Errorf("Expected a table per analyzer plus issues, got %v", results.Views)
+	}
+	if len(results.Tables["bus-factor"].Rows) != 3 {
+		t.Errorf("Expected 3 bus-factor rows, got %d", len(results.Tables["bus-factor"].Rows))
+	}
+	if results.Tables["test-ratio"].Err == "" {
+		t.Error("Expected the failed analyzer to be reported")
+	}
+}
+
+func TestTUIRenderFitsTerminal(t *testing.T) {
+	ansi := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	var scopes []string
+	s := newTestTUIState(&scopes)
+
+	for _, view := range []rune{'1', '2', '3'} {
+		s.handleKey(tuiKey{Rune: view})
+		for _, size := range [][2]int{{80, 24}, {120, 40}, {40, 10}} {
+			lines := s.render(size[0], size[1])
+			if len(lines) != size[1] {
+				t.Errorf("%dx%d: expected %d lines, got %d", size[0], size[1], size[1], len(lines))
+			}
+			for i, line := range lines {
+				if w := utf8.RuneCountInString(ansi.ReplaceAllString(line, "")); w != size[0] {
+					t.Errorf("%dx%d: line %d is %d columns wide: %q", size[0], size[1], i, w, line)
+				}
+			}
+		}
+	}
+
+	if lines := s.render(20, 5); len(lines) != 1 {
+		t.Errorf("Expected a single message for a tiny terminal, got %d lines", len(lines))
+	}
+}
+
+func TestWrapTUI(t *testing.T) {
+	tests := []struct {
+		text     string
+		width    int
+		expected string
+	}{
+		{"Path: src/  BusFactor: 1", 12, "Path: src/|BusFactor: 1"},
+		{"short", 10, "short"},
+		{"abcdefghij", 4, "abcd|efgh|ij"},
+		{"", 5, ""},
+	}
+
+	for _, tt := range tests {
+		if result := strings.Join(wrapTUI(tt.text, tt.width), "|"); result != tt.expected {
+			t.Errorf("wrapTUI(%q, %d) = %q, want %q", tt.text, tt.width, result, tt.expected)
+		}
+	}
+}
+
+func TestFitTUI(t *testing.T) {
+	tests := []struct {
+		text       string
+		width      int
+		alignRight bool
+		expected   string
+	}{
+		{"abc", 5, false, "abc  "},
+		{"abc", 5, true, "  abc"},
+		{"abcdef", 4, false, "abc~"},
+		{"abc", 0, false, ""},
+	}
+
+	for _, tt := range tests {
+		if result := fitTUI(tt.text, tt.width, tt.alignRight); result != tt.expected {
+			t.Errorf("fitTUI(%q, %d, %v) = %q, want %q", tt.text, tt.width, tt.alignRight, result, tt.expected)
+		}
+	}
+}
+
+func TestBuildTUIRecordTableWithoutColumns(t *testing.T) {
+	type ownersOnly struct {
+		Owners map[string]int
+		Paths  []string
+	}
+	table := buildTUITable([]ownersOnly{
+		{Owners: map[string]int{"alice": 2}, Paths: []string{"a.go"}},
+		{Owners: map[string]int{"bob": 1}},
+	})
+
+	if len(table.Columns) != 1 || table.Columns[0].Title != "Record" {
+		t.Fatalf("Expected records to be numbered, got %+v", table.Columns)
+	}
+	if len(table.Rows) != 2 || table.Rows[0].Key != "1" || table.Rows[1].Key != "2" {
+		t.Fatalf("Expected rows keyed by record number, got %+v", table.Rows)
+	}
+	if detail := strings.Join(table.Rows[0].Detail, "\n"); !strings.Contains(detail, "Owners: alice 2") {
+		t.Errorf("Expected the detail to list the map, got %q", detail)
+	}
+
+	// Filtering, opening and drawing the table must not panic
+	s := newTUIState(testTUIFiles, "all time", func(string) *tuiResults {
+		return &tuiResults{Views: []string{"owners"}, Tables: map[string]*tuiTable{"owners": table}}
+	})
+	s.filter = "2"
+	if rows := s.rows(); len(rows) != 1 || rows[0].Key != "2" {
+		t.Errorf("Expected the filter to match the record number, got %+v", rows)
+	}
+	s.open()
+	s.render(80, 24)
+}
+
+func TestTUIFileDetail(t *testing.T) {
+	repo := newTestRepo(t)
+	now := time.Now()
+	testRepoCommit(t, repo, map[string]string{"src/a.go": "a\n", "docs/guide.md": "g\n"}, "alice", now.AddDate(0, -2, 0))
+	testRepoCommit(t, repo, map[string]string{"src/a.go": "a\nb\nc\n"}, "bob", now.AddDate(0, 0, -3))
+	testRepoCommit(t, repo, map[string]string{"src/a.go": "a\nb\nc\nd\n"}, "bob", now.AddDate(0, 0, -1))
+
+	files, err := headFilePaths(repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	facts, err := collectTUIFileFacts(repo, files, time.Time{}, []string{"src"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := facts["docs/guide.md"]; ok {
+		t.Error("Expected files outside the scope to be left out")
+	}
+	a := facts["src/a.go"]
+	if a == nil || a.Changes != 3 || a.Additions != 4 || a.AuthorChanges["bob"] != 2 || a.AuthorChanges["alice"] != 1 {
+		t.Fatalf("Unexpected facts for src/a.go: %+v", a)
+	}
+
+	type fileRow struct {
+		Path string
+		LOC  int
+	}
+	s := newTUIState(files, "all time", func(string) *tuiResults {
+		return &tuiResults{
+			Views:  []string{"files"},
+			Tables: map[string]*tuiTable{"files": buildTUITable([]fileRow{{"src/a.go", 4}})},
+			Files:  facts,
+		}
+	})
+	s.now = now
+	detail := strings.Join(s.detailLines(s.table(), s.rows()), "\n")
+	for _, want := range []string{"Path: src/a.go", "Changes: 3  +4 / -0", "Owners: bob 67% (2, +3)  alice 33% (1, +1)", "Churn by month: ", "days ago"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("Expected the detail pane to include %q, got:\n%s", want, detail)
+		}
+	}
+}
+
+func TestFormatTUIHistory(t *testing.T) {
+	now := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	monthly := map[string]lineDelta{
+		"2025-01": {Additions: 10, Deletions: 2},
+		"2025-03": {Additions: 1},
+	}
+	result := formatTUIHistory(monthly, now, 3)
+	if result != "Jan 2025 [# .] Mar 2025, peak 2025-01 +10/-2" {
+		t.Errorf("Unexpected history %q", result)
+	}
+}
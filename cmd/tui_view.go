@@ -0,0 +1,1220 @@
+/*
+Copyright © 2025 Ben Ricker
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// tuiIssuesView names the table of health issues shown after the analyzers
+const tuiIssuesView = "issues"
+
+// TUI panes that can hold keyboard focus
+const (
+	tuiFocusTable = iota
+	tuiFocusTree
+)
+
+const (
+	tuiMinWidth     = 40
+	tuiMinHeight    = 10
+	tuiDetailHeight = 8 // separator plus seven detail lines
+	tuiPageSize     = 10
+)
+
+// ANSI styles; nothing beyond SGR attributes is used so plain terminals cope
+const (
+	tuiStyleReset   = "\x1b[0m"
+	tuiStyleBold    = "\x1b[1m"
+	tuiStyleReverse = "\x1b[7m"
+)
+
+// tuiSparkLevels are the ASCII bar heights used for text charts
+const tuiSparkLevels = " .:-=+*#"
+
+// tuiKey is a decoded key press: either a named key or a printable rune
+type tuiKey struct {
+	Name string
+	Rune rune
+}
+
+// tuiEscapeKeys maps CSI/SS3 sequence suffixes to key names
+var tuiEscapeKeys = map[string]string{
+	"A": "up", "B": "down", "C": "right", "D": "left",
+	"H": "home", "F": "end", "1~": "home", "4~": "end",
+	"5~": "pgup", "6~": "pgdn", "3~": "delete",
+}
+
+// parseTUIKeys decodes raw terminal input into key presses
+func parseTUIKeys(b []byte) []tuiKey {
+	var keys []tuiKey
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == 0x1b:
+			if i+2 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+				j := i + 2
+				for j < len(b) && (b[j] < 0x40 || b[j] > 0x7e) {
+					j++
+				}
+				if j == len(b) {
+					return keys // Incomplete sequence
+				}
+				if name, ok := tuiEscapeKeys[string(b[i+2:j+1])]; ok {
+					keys = append(keys, tuiKey{Name: name})
+				}
+				i = j + 1
+				continue
+			}
+			keys = append(keys, tuiKey{Name: "esc"})
+		case c == '\r' || c == '\n':
+			keys = append(keys, tuiKey{Name: "enter"})
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, tuiKey{Name: "backspace"})
+		case c == '\t':
+			keys = append(keys, tuiKey{Name: "tab"})
+		case c == 0x03:
+			keys = append(keys, tuiKey{Name: "ctrl-c"})
+		case c < 0x20:
+			// Other control characters are ignored
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			keys = append(keys, tuiKey{Rune: r})
+			i += size
+			continue
+		}
+		i++
+	}
+	return keys
+}
+
+// tuiColumn describes a table column; Width 0 stretches to fill the pane
+type tuiColumn struct {
+	Title string
+	Width int
+}
+
+// tuiRow is a table row. Values hold string, int, float64 or time.Time
+// cells; Detail describes every field of the row for the detail pane.
+type tuiRow struct {
+	Key    string
+	Values []interface{}
+	Detail []string
+}
+
+// tuiTable is one analyzer's result laid out as a table
+type tuiTable struct {
+	Columns []tuiColumn
+	Rows    []tuiRow
+	Summary string // the result's own scalar fields
+	Err     string // set when the analyzer failed or returned nothing
+}
+
+// tuiResults is one run of the analyzers for a scope
+type tuiResults struct {
+	Views  []string // analyzer names, then the issues view
+	Tables map[string]*tuiTable
+	Files  map[string]*tuiFileFacts // HEAD files in the scope, for the detail pane
+	Err    error
+}
+
+// newTUIResults lays out each analyzer's result and the issues as tables
+func newTUIResults(analyzers []Analyzer, results map[string]interface{}, issues []HealthIssue) *tuiResults {
+	r := &tuiResults{Tables: make(map[string]*tuiTable)}
+	for _, a := range analyzers {
+		r.Views = append(r.Views, a.Name())
+		result, ok := results[a.Name()]
+		if !ok {
+			r.Tables[a.Name()] = &tuiTable{Err: "analyzer failed; the error is printed on exit"}
+			continue
+		}
+		r.Tables[a.Name()] = buildTUITable(result)
+	}
+	r.Views = append(r.Views, tuiIssuesView)
+	r.Tables[tuiIssuesView] = buildTUITable(issues)
+	if len(issues) == 0 {
+		r.Tables[tuiIssuesView].Err = "no health issues"
+	}
+	return r
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// derefTUIValue follows pointers and interfaces; the result is invalid for nil
+func derefTUIValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// tuiCell converts a scalar field to a table cell, reporting false for
+// anything that isn't a scalar
+func tuiCell(v reflect.Value) (interface{}, bool) {
+	v = derefTUIValue(v)
+	if !v.IsValid() {
+		return nil, false
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time), true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Bool:
+		return fmt.Sprint(v.Bool()), true
+	}
+	return nil, false
+}
+
+// isTUIRecordSlice reports whether a value is a slice of structs, which
+// becomes the rows of a table
+func isTUIRecordSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	elem := v.Type().Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct && elem != timeType
+}
+
+// buildTUITable lays out any analyzer result. A struct's first slice of
+// structs becomes the rows, one column per scalar field, and its other scalar
+// fields the summary; results without one are shown as name/value rows.
+func buildTUITable(result interface{}) *tuiTable {
+	v := derefTUIValue(reflect.ValueOf(result))
+	if !v.IsValid() {
+		return &tuiTable{Err: "no results for this scope"}
+	}
+	if isTUIRecordSlice(v) {
+		return buildTUIRecordTable(v)
+	}
+	if v.Kind() != reflect.Struct {
+		cell, ok := tuiCell(v)
+		if !ok {
+			cell = fmt.Sprint(v.Interface())
+		}
+		return &tuiTable{
+			Columns: []tuiColumn{{"Result", 0}},
+			Rows:    []tuiRow{{Key: fmt.Sprint(cell), Values: []interface{}{cell}}},
+		}
+	}
+
+	var records reflect.Value
+	var summary []string
+	var fields []tuiRow
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		value := v.Field(i)
+		if !records.IsValid() && isTUIRecordSlice(value) {
+			records = value
+			continue
+		}
+		if cell, ok := tuiCell(value); ok {
+			summary = append(summary, fmt.Sprintf("%s: %s", field.Name, formatTUIValue(cell)))
+			fields = append(fields, tuiRow{Key: field.Name, Values: []interface{}{field.Name, formatTUIValue(cell)}})
+		} else if text := describeTUIField(value); text != "" {
+			fields = append(fields, tuiRow{Key: field.Name, Values: []interface{}{field.Name, text}})
+		}
+	}
+
+	if records.IsValid() {
+		table := buildTUIRecordTable(records)
+		table.Summary = strings.Join(summary, "  ")
+		return table
+	}
+	for i := range fields {
+		fields[i].Detail = []string{fmt.Sprint(fields[i].Values[1])}
+	}
+	return &tuiTable{Columns: []tuiColumn{{"Field", 0}, {"Value", 24}}, Rows: fields}
+}
+
+// buildTUIRecordTable turns a slice of structs into rows. The first string
+// field leads, since it names the row and is what the filter matches.
+func buildTUIRecordTable(records reflect.Value) *tuiTable {
+	elem := records.Type().Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	var columns []tuiColumn
+	var fieldIndex []int
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		width := 0
+		switch {
+		case field.Type == timeType:
+			width = 10
+		case field.Type.Kind() == reflect.String:
+			width = 14
+		case field.Type.Kind() == reflect.Bool:
+			width = 5
+		case field.Type.Kind() >= reflect.Int && field.Type.Kind() <= reflect.Float64:
+			width = 8
+		default:
+			continue // Maps and slices go to the detail pane
+		}
+		if width == 14 && (len(fieldIndex) == 0 || columns[0].Width != 0) {
+			width = 0
+			columns = append([]tuiColumn{{field.Name, width}}, columns...)
+			fieldIndex = append([]int{i}, fieldIndex...)
+			continue
+		}
+		if w := utf8.RuneCountInString(field.Name); width > 0 && w > width {
+			width = w
+		}
+		columns = append(columns, tuiColumn{field.Name, width})
+		fieldIndex = append(fieldIndex, i)
+	}
+	if len(columns) > 0 && columns[0].Width != 0 {
+		columns[0].Width = 0
+	}
+	// Records with only map and slice fields are numbered instead
+	numbered := len(columns) == 0
+	if numbered {
+		columns = []tuiColumn{{"Record", 0}}
+	}
+
+	table := &tuiTable{Columns: columns}
+	for r := 0; r < records.Len(); r++ {
+		record := derefTUIValue(records.Index(r))
+		if !record.IsValid() {
+			continue
+		}
+		row := tuiRow{}
+		if numbered {
+			row.Values = []interface{}{r + 1}
+		}
+		for _, i := range fieldIndex {
+			cell, _ := tuiCell(record.Field(i))
+			row.Values = append(row.Values, cell)
+		}
+		row.Key = fmt.Sprint(row.Values[0])
+		row.Detail = describeTUIRecord(record)
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// describeTUIRecord lists a row's scalar fields on one line and each map or
+// slice field on its own line
+func describeTUIRecord(record reflect.Value) []string {
+	var scalars, others []string
+	for i := 0; i < record.NumField(); i++ {
+		field := record.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if cell, ok := tuiCell(record.Field(i)); ok {
+			scalars = append(scalars, fmt.Sprintf("%s: %s", field.Name, formatTUIValue(cell)))
+		} else if text := describeTUIField(record.Field(i)); text != "" {
+			others = append(others, fmt.Sprintf("%s: %s", field.Name, text))
+		}
+	}
+	return append([]string{strings.Join(scalars, "  ")}, others...)
+}
+
+// describeTUIField renders a map, largest value first, or a slice
+func describeTUIField(v reflect.Value) string {
+	v = derefTUIValue(v)
+	if !v.IsValid() {
+		return ""
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		type entry struct {
+			key   string
+			cell  interface{}
+			value string
+		}
+		var entries []entry
+		for _, k := range v.MapKeys() {
+			cell, ok := tuiCell(v.MapIndex(k))
+			value := ""
+			if ok {
+				value = formatTUIValue(cell)
+			} else {
+				value = describeTUIField(v.MapIndex(k))
+			}
+			entries = append(entries, entry{fmt.Sprint(k.Interface()), cell, value})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].cell != nil && entries[j].cell != nil {
+				if c := compareTUIValues(entries[i].cell, entries[j].cell); c != 0 {
+					return c > 0
+				}
+			}
+			return entries[i].key < entries[j].key
+		})
+		parts := make([]string, len(entries))
+		for i, e := range entries {
+			parts[i] = e.key + " " + e.value
+		}
+		return strings.Join(parts, ", ")
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := derefTUIValue(v.Index(i))
+			if cell, ok := tuiCell(item); ok {
+				parts = append(parts, formatTUIValue(cell))
+			} else if item.IsValid() && item.Kind() == reflect.Struct {
+				var fields []string
+				for f := 0; f < item.NumField(); f++ {
+					if cell, ok := tuiCell(item.Field(f)); ok && item.Type().Field(f).PkgPath == "" {
+						fields = append(fields, formatTUIValue(cell))
+					}
+				}
+				parts = append(parts, strings.Join(fields, " "))
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// tuiState is everything the TUI needs to render a frame and react to keys
+type tuiState struct {
+	files      []string
+	timeWindow string
+	now        time.Time
+	loader     func(scope string) *tuiResults
+	results    map[string]*tuiResults // by scope
+
+	scope string // current directory, "" for the repository root
+	view  int
+	focus int
+
+	sortCol  map[string]int // by view name; -1 keeps the analyzer's order
+	sortDesc map[string]bool
+
+	filter        string
+	editingFilter bool
+
+	selected   int
+	offset     int
+	treeSel    int
+	treeOffset int
+}
+
+// newTUIState returns the initial state over the HEAD files; loader runs the
+// analyzers for a scope
+func newTUIState(files []string, timeWindow string, loader func(scope string) *tuiResults) *tuiState {
+	return &tuiState{
+		files:      files,
+		timeWindow: timeWindow,
+		now:        time.Now(),
+		loader:     loader,
+		results:    make(map[string]*tuiResults),
+		sortCol:    make(map[string]int),
+		sortDesc:   make(map[string]bool),
+	}
+}
+
+// loaded reports whether the current scope has been analyzed
+func (s *tuiState) loaded() bool {
+	_, ok := s.results[s.scope]
+	return ok
+}
+
+// load analyzes the current scope
+func (s *tuiState) load() *tuiResults {
+	r, ok := s.results[s.scope]
+	if !ok {
+		r = s.loader(s.scope)
+		s.results[s.scope] = r
+	}
+	return r
+}
+
+// views lists the tabs for the current scope
+func (s *tuiState) views() []string {
+	return s.load().Views
+}
+
+// viewName names the active tab
+func (s *tuiState) viewName() string {
+	views := s.views()
+	if len(views) == 0 {
+		return ""
+	}
+	return views[clampTUI(s.view, len(views))]
+}
+
+// table returns the active tab's table
+func (s *tuiState) table() *tuiTable {
+	r := s.load()
+	if r.Err != nil {
+		return &tuiTable{Err: r.Err.Error()}
+	}
+	if t, ok := r.Tables[s.viewName()]; ok {
+		return t
+	}
+	return &tuiTable{}
+}
+
+// sortOf returns the active view's sort column and direction
+func (s *tuiState) sortOf() (int, bool) {
+	col, ok := s.sortCol[s.viewName()]
+	if !ok {
+		col = -1
+	}
+	return col, s.sortDesc[s.viewName()]
+}
+
+// inTUIScope reports whether a path lies within a scope directory
+func inTUIScope(filePath, scope string) bool {
+	return scope == "" || strings.HasPrefix(filePath, scope+"/")
+}
+
+// childDirectories returns the immediate subdirectories of a scope with their file counts
+func childDirectories(files []string, scope string) ([]string, map[string]int) {
+	counts := make(map[string]int)
+	for _, p := range files {
+		if !inTUIScope(p, scope) {
+			continue
+		}
+		rest := p
+		if scope != "" {
+			rest = strings.TrimPrefix(p, scope+"/")
+		}
+		slash := strings.Index(rest, "/")
+		if slash < 0 {
+			continue
+		}
+		child := rest[:slash]
+		if scope != "" {
+			child = scope + "/" + child
+		}
+		counts[child]++
+	}
+
+	dirs := make([]string, 0, len(counts))
+	for dir := range counts {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, counts
+}
+
+// rows returns the active table's rows, filtered and sorted
+func (s *tuiState) rows() []tuiRow {
+	col, desc := s.sortOf()
+	return filterAndSortTUIRows(s.table().Rows, s.filter, col, desc)
+}
+
+// filterAndSortTUIRows keeps rows whose name contains the filter (case-insensitive)
+// and sorts them by one column, breaking ties by key. A negative column keeps
+// the rows in the order the analyzer reported them.
+func filterAndSortTUIRows(all []tuiRow, filter string, col int, desc bool) []tuiRow {
+	needle := strings.ToLower(filter)
+	rows := make([]tuiRow, 0, len(all))
+	for _, row := range all {
+		if needle == "" || strings.Contains(strings.ToLower(fmt.Sprint(row.Values[0])), needle) {
+			rows = append(rows, row)
+		}
+	}
+	if col < 0 {
+		return rows
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		c := compareTUIValues(rows[i].Values[col], rows[j].Values[col])
+		if c == 0 {
+			return rows[i].Key < rows[j].Key
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	return rows
+}
+
+// compareTUIValues orders two cells of the same column
+func compareTUIValues(a, b interface{}) int {
+	switch av := a.(type) {
+	case int:
+		bv, _ := b.(int)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case float64:
+		bv, _ := b.(float64)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	case time.Time:
+		bv, _ := b.(time.Time)
+		switch {
+		case av.Before(bv):
+			return -1
+		case av.After(bv):
+			return 1
+		}
+		return 0
+	default:
+		return strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+	}
+}
+
+// formatTUIValue renders a cell
+func formatTUIValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "-"
+	case int:
+		return fmt.Sprintf("%d", val)
+	case float64:
+		return fmt.Sprintf("%.1f", val)
+	case time.Time:
+		if val.IsZero() {
+			return "-"
+		}
+		return val.Format("2006-01-02")
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// treeEntries lists the tree pane: ".." outside the root, then child directories
+func (s *tuiState) treeEntries() ([]string, map[string]int) {
+	dirs, counts := childDirectories(s.files, s.scope)
+	if s.scope != "" {
+		dirs = append([]string{".."}, dirs...)
+	}
+	return dirs, counts
+}
+
+// isTUIDirectory reports whether a directory holds any HEAD file
+func (s *tuiState) isTUIDirectory(dir string) bool {
+	for _, p := range s.files {
+		if strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// setScope re-scopes every table to a directory
+func (s *tuiState) setScope(dir string) {
+	s.scope = dir
+	s.filter = ""
+	s.selected, s.offset = 0, 0
+	s.treeSel, s.treeOffset = 0, 0
+}
+
+// goUp moves the scope to the parent directory, keeping the old scope selected in the tree
+func (s *tuiState) goUp() {
+	if s.scope == "" {
+		return
+	}
+	previous := s.scope
+	parent := path.Dir(s.scope)
+	if parent == "." {
+		parent = ""
+	}
+	s.setScope(parent)
+	entries, _ := s.treeEntries()
+	for i, entry := range entries {
+		if entry == previous {
+			s.treeSel = i
+		}
+	}
+}
+
+// open descends into the selected tree directory, or the directory the
+// selected table row names
+func (s *tuiState) open() {
+	if s.focus == tuiFocusTree {
+		entries, _ := s.treeEntries()
+		if s.treeSel >= len(entries) {
+			return
+		}
+		if entries[s.treeSel] == ".." {
+			s.goUp()
+			return
+		}
+		s.setScope(entries[s.treeSel])
+		return
+	}
+
+	rows := s.rows()
+	if s.selected < len(rows) {
+		dir := strings.TrimSuffix(fmt.Sprint(rows[s.selected].Values[0]), "/")
+		if dir != s.scope && s.isTUIDirectory(dir) {
+			s.setScope(dir)
+		}
+	}
+}
+
+// move shifts the selection in the focused pane, clamped to its length
+func (s *tuiState) move(delta int) {
+	if s.focus == tuiFocusTree {
+		entries, _ := s.treeEntries()
+		s.treeSel = clampTUI(s.treeSel+delta, len(entries))
+		return
+	}
+	s.selected = clampTUI(s.selected+delta, len(s.rows()))
+}
+
+// switchView selects a tab, wrapping around
+func (s *tuiState) switchView(view int) {
+	n := len(s.views())
+	if n == 0 {
+		return
+	}
+	s.view = ((view % n) + n) % n
+	s.focus = tuiFocusTable
+	s.filter = ""
+	s.selected, s.offset = 0, 0
+}
+
+// clampTUI keeps an index within [0, n)
+func clampTUI(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// handleKey applies a key press and reports whether the TUI should quit
+func (s *tuiState) handleKey(k tuiKey) bool {
+	if s.editingFilter {
+		switch k.Name {
+		case "ctrl-c":
+			return true
+		case "enter":
+			s.editingFilter = false
+		case "esc":
+			s.filter = ""
+			s.editingFilter = false
+		case "backspace":
+			if s.filter != "" {
+				_, size := utf8.DecodeLastRuneInString(s.filter)
+				s.filter = s.filter[:len(s.filter)-size]
+			}
+		default:
+			if k.Rune != 0 {
+				s.filter += string(k.Rune)
+			}
+		}
+		s.selected, s.offset = 0, 0
+		return false
+	}
+
+	switch {
+	case k.Name == "ctrl-c" || k.Rune == 'q':
+		return true
+	case k.Rune >= '1' && k.Rune <= '9':
+		if view := int(k.Rune - '1'); view < len(s.views()) {
+			s.switchView(view)
+		}
+	case k.Rune == ']':
+		s.switchView(s.view + 1)
+	case k.Rune == '[':
+		s.switchView(s.view - 1)
+	case k.Name == "tab":
+		if s.focus == tuiFocusTable {
+			s.focus = tuiFocusTree
+		} else {
+			s.focus = tuiFocusTable
+		}
+	case k.Name == "up" || k.Rune == 'k':
+		s.move(-1)
+	case k.Name == "down" || k.Rune == 'j':
+		s.move(1)
+	case k.Name == "pgup":
+		s.move(-tuiPageSize)
+	case k.Name == "pgdn":
+		s.move(tuiPageSize)
+	case k.Name == "home" || k.Rune == 'g':
+		s.move(-1 << 30)
+	case k.Name == "end" || k.Rune == 'G':
+		s.move(1 << 30)
+	case k.Name == "enter" || k.Name == "right" || k.Rune == 'l':
+		s.open()
+	case k.Name == "backspace" || k.Name == "left" || k.Rune == 'h':
+		s.goUp()
+	case k.Rune == 's' || k.Rune == 'S':
+		columns := len(s.table().Columns)
+		if columns == 0 {
+			break
+		}
+		// Cycle through the columns and back to the analyzer's order
+		col, _ := s.sortOf()
+		step := 1
+		if k.Rune == 'S' {
+			step = columns
+		}
+		col = (col+1+step)%(columns+1) - 1
+		s.sortCol[s.viewName()] = col
+		// Names read best A-Z, numbers and dates largest first
+		s.sortDesc[s.viewName()] = col > 0
+		s.selected, s.offset = 0, 0
+	case k.Rune == 'r':
+		col, desc := s.sortOf()
+		if col >= 0 {
+			s.sortDesc[s.viewName()] = !desc
+			s.selected, s.offset = 0, 0
+		}
+	case k.Rune == '/':
+		s.editingFilter = true
+	case k.Name == "esc":
+		s.filter = ""
+		s.selected, s.offset = 0, 0
+	}
+	return false
+}
+
+// fitTUI truncates or pads plain text to exactly width runes
+func fitTUI(text string, width int, alignRight bool) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(text)
+	if n > width {
+		runes := []rune(text)
+		if width == 1 {
+			return string(runes[:1])
+		}
+		return string(runes[:width-1]) + "~"
+	}
+	pad := strings.Repeat(" ", width-n)
+	if alignRight {
+		return pad + text
+	}
+	return text + pad
+}
+
+// wrapTUI breaks text into lines of at most width runes at spaces where it can
+func wrapTUI(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// styleTUI wraps already-fitted text in an SGR style
+func styleTUI(style, text string) string {
+	return style + text + tuiStyleReset
+}
+
+// scopeLabel renders a scope for display
+func scopeLabel(scope string) string {
+	if scope == "" {
+		return "/"
+	}
+	return "/" + scope
+}
+
+// renderLoading draws a placeholder frame while a scope is analyzed
+func (s *tuiState) renderLoading(width, height int) []string {
+	lines := []string{fitTUI(fmt.Sprintf(" Analyzing %s ...", scopeLabel(s.scope)), width, false)}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+// render draws a full frame of exactly height lines, each width columns wide
+func (s *tuiState) render(width, height int) []string {
+	if width < tuiMinWidth || height < tuiMinHeight {
+		return []string{fitTUI(fmt.Sprintf("Terminal too small for gitallica tui (need %dx%d)", tuiMinWidth, tuiMinHeight), width, false)}
+	}
+
+	table := s.table()
+	rows := s.rows()
+	lines := make([]string, 0, height)
+	lines = append(lines, s.renderTabs(width))
+	lines = append(lines, s.renderStatus(width, len(rows)))
+	lines = append(lines, fitTUI(" "+table.Summary, width, false))
+
+	detailHeight := 0
+	if height >= 20 {
+		detailHeight = tuiDetailHeight
+	}
+	bodyHeight := height - 4 - detailHeight
+
+	treeWidth := width / 4
+	if treeWidth < 16 {
+		treeWidth = 16
+	}
+	if treeWidth > 32 {
+		treeWidth = 32
+	}
+	tree := s.renderTree(treeWidth, bodyHeight)
+	body := s.renderTable(table, rows, width-treeWidth-1, bodyHeight)
+	for i := 0; i < bodyHeight; i++ {
+		lines = append(lines, tree[i]+"|"+body[i])
+	}
+
+	if detailHeight > 0 {
+		lines = append(lines, strings.Repeat("-", width))
+		var detail []string
+		for _, text := range s.detailLines(table, rows) {
+			detail = append(detail, wrapTUI(text, width)...)
+		}
+		for i := 0; i < detailHeight-1; i++ {
+			text := ""
+			if i < len(detail) {
+				text = detail[i]
+			}
+			line := fitTUI(text, width, false)
+			if i == 0 {
+				line = styleTUI(tuiStyleBold, line)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	lines = append(lines, fitTUI("1-9/[ ] table  Tab pane  j/k move  Enter open  Bksp up  s/S sort  r reverse  / filter  q quit", width, false))
+	return lines
+}
+
+// renderTabs draws the title bar with the active view highlighted
+func (s *tuiState) renderTabs(width int) string {
+	title := " gitallica "
+	var b strings.Builder
+	b.WriteString(styleTUI(tuiStyleBold, title))
+	used := len(title)
+	active := s.viewName()
+	for i, name := range s.views() {
+		tab := fmt.Sprintf(" %d %s ", i+1, name)
+		if i >= 9 {
+			tab = fmt.Sprintf(" %s ", name)
+		}
+		if used+utf8.RuneCountInString(tab) > width {
+			break
+		}
+		if name == active {
+			b.WriteString(styleTUI(tuiStyleReverse, tab))
+		} else {
+			b.WriteString(tab)
+		}
+		used += utf8.RuneCountInString(tab)
+	}
+	b.WriteString(strings.Repeat(" ", width-used))
+	return b.String()
+}
+
+// renderStatus draws the scope, sort, filter and time window line
+func (s *tuiState) renderStatus(width, rowCount int) string {
+	col, desc := s.sortOf()
+	sortLabel := "analyzer order"
+	if columns := s.table().Columns; col >= 0 && col < len(columns) {
+		sortLabel = columns[col].Title + " asc"
+		if desc {
+			sortLabel = columns[col].Title + " desc"
+		}
+	}
+	filter := s.filter
+	if s.editingFilter {
+		filter += "_"
+	}
+	if filter == "" {
+		filter = "none"
+	}
+	status := fmt.Sprintf(" Scope: %s | %d rows | Sort: %s | Filter: %s | Window: %s",
+		scopeLabel(s.scope), rowCount, sortLabel, filter, s.timeWindow)
+	return fitTUI(status, width, false)
+}
+
+// renderTree draws the directory navigator
+func (s *tuiState) renderTree(width, height int) []string {
+	entries, counts := s.treeEntries()
+	lines := []string{styleTUI(tuiStyleBold, fitTUI(" "+scopeLabel(s.scope), width, false))}
+
+	visible := height - 1
+	s.treeSel = clampTUI(s.treeSel, len(entries))
+	if s.treeSel < s.treeOffset {
+		s.treeOffset = s.treeSel
+	}
+	if s.treeSel >= s.treeOffset+visible {
+		s.treeOffset = s.treeSel - visible + 1
+	}
+
+	for i := s.treeOffset; i < len(entries) && len(lines) < height; i++ {
+		label := " .."
+		if entries[i] != ".." {
+			label = fmt.Sprintf(" + %s/ (%d)", path.Base(entries[i]), counts[entries[i]])
+		}
+		line := fitTUI(label, width, false)
+		if i == s.treeSel {
+			if s.focus == tuiFocusTree {
+				line = styleTUI(tuiStyleReverse, line)
+			} else {
+				line = styleTUI(tuiStyleBold, line)
+			}
+		}
+		lines = append(lines, line)
+	}
+	if len(entries) == 0 {
+		lines = append(lines, fitTUI(" (no subdirectories)", width, false))
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+// tableLayout picks the columns that fit and the width of the stretching first column
+func tableLayout(columns []tuiColumn, width int) (int, int) {
+	const minFlex = 12
+	count := len(columns)
+	for count > 1 {
+		fixed := 0
+		for _, col := range columns[1:count] {
+			fixed += col.Width + 1
+		}
+		if width-fixed >= minFlex {
+			return count, width - fixed
+		}
+		count--
+	}
+	return count, width
+}
+
+// renderTable draws the active table
+func (s *tuiState) renderTable(table *tuiTable, rows []tuiRow, width, height int) []string {
+	columns := table.Columns
+	count, flex := tableLayout(columns, width)
+	sortCol, sortDesc := s.sortOf()
+
+	cellWidth := func(i int) int {
+		if i == 0 {
+			return flex
+		}
+		return columns[i].Width
+	}
+
+	var header strings.Builder
+	for i := 0; i < count; i++ {
+		title := columns[i].Title
+		if i == sortCol {
+			if sortDesc {
+				title += "v"
+			} else {
+				title += "^"
+			}
+		}
+		if i > 0 {
+			header.WriteString(" ")
+		}
+		header.WriteString(fitTUI(title, cellWidth(i), i > 0))
+	}
+	lines := []string{styleTUI(tuiStyleBold, fitTUI(header.String(), width, false))}
+
+	visible := height - 1
+	s.selected = clampTUI(s.selected, len(rows))
+	if s.selected < s.offset {
+		s.offset = s.selected
+	}
+	if s.selected >= s.offset+visible {
+		s.offset = s.selected - visible + 1
+	}
+
+	for r := s.offset; r < len(rows) && len(lines) < height; r++ {
+		var line strings.Builder
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				line.WriteString(" ")
+			}
+			line.WriteString(fitTUI(formatTUIValue(rows[r].Values[i]), cellWidth(i), i > 0))
+		}
+		text := fitTUI(line.String(), width, false)
+		if r == s.selected {
+			if s.focus == tuiFocusTable {
+				text = styleTUI(tuiStyleReverse, text)
+			} else {
+				text = styleTUI(tuiStyleBold, text)
+			}
+		}
+		lines = append(lines, text)
+	}
+	if len(rows) == 0 {
+		message := " (no rows)"
+		if table.Err != "" {
+			message = " (" + table.Err + ")"
+		}
+		lines = append(lines, fitTUI(message, width, false))
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+// detailLines describes the selected row with every field the analyzer
+// reported, and for a file its owners, churn history and last change
+func (s *tuiState) detailLines(table *tuiTable, rows []tuiRow) []string {
+	if len(rows) == 0 {
+		return []string{s.viewName(), table.Summary}
+	}
+	row := rows[clampTUI(s.selected, len(rows))]
+	f, ok := s.load().Files[row.Key]
+	if !ok || len(row.Detail) == 0 {
+		return append([]string{row.Key}, row.Detail...)
+	}
+	// The row's scalar fields lead, then the file's history, then the rest
+	lines := append([]string{row.Key, row.Detail[0]}, s.fileDetail(f)...)
+	return append(lines, row.Detail[1:]...)
+}
+
+// tuiHistoryMonths is how many months the churn history covers
+const tuiHistoryMonths = 12
+
+// fileDetail summarizes a file's history in the time window
+func (s *tuiState) fileDetail(f *tuiFileFacts) []string {
+	return []string{
+		fmt.Sprintf("Changes: %d  +%d / -%d  Last modified: %s", f.Changes, f.Additions, f.Deletions, describeTUIAge(f.LastModified, s.now)),
+		"Owners: " + formatTUIOwners(f.AuthorChanges, f.AuthorLines),
+		"Churn by month: " + formatTUIHistory(f.Monthly, s.now, tuiHistoryMonths),
+	}
+}
+
+// describeTUIAge renders a date with how long ago it was
+func describeTUIAge(when, now time.Time) string {
+	if when.IsZero() {
+		return "not in time window"
+	}
+	days := int(now.Sub(when).Hours() / 24)
+	age := fmt.Sprintf("%d days ago", days)
+	if days > 60 {
+		age = fmt.Sprintf("%d months ago", calculateFileAge(when, now))
+	}
+	return fmt.Sprintf("%s (%s)", when.Format("2006-01-02"), age)
+}
+
+// formatTUIOwners lists authors by share of commits, largest first
+func formatTUIOwners(authorChanges, authorLines map[string]int) string {
+	total := 0
+	authors := make([]string, 0, len(authorChanges))
+	for author, n := range authorChanges {
+		authors = append(authors, author)
+		total += n
+	}
+	if total == 0 {
+		return "none in time window"
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authorChanges[authors[i]] != authorChanges[authors[j]] {
+			return authorChanges[authors[i]] > authorChanges[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+
+	parts := make([]string, 0, len(authors))
+	for _, author := range authors {
+		parts = append(parts, fmt.Sprintf("%s %.0f%% (%d, +%d)",
+			author, float64(authorChanges[author])/float64(total)*100, authorChanges[author], authorLines[author]))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// formatTUIHistory renders monthly churn as an ASCII sparkline, oldest month first
+func formatTUIHistory(monthly map[string]lineDelta, now time.Time, months int) string {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -(months - 1), 0)
+
+	values := make([]int, months)
+	peak, peakMonth := 0, ""
+	for i := 0; i < months; i++ {
+		month := start.AddDate(0, i, 0).Format("2006-01")
+		delta := monthly[month]
+		values[i] = delta.Additions + delta.Deletions
+		if values[i] > peak {
+			peak, peakMonth = values[i], month
+		}
+	}
+
+	var spark strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 && v > 0 {
+			level = 1 + v*(len(tuiSparkLevels)-2)/peak
+		}
+		spark.WriteByte(tuiSparkLevels[level])
+	}
+
+	end := start.AddDate(0, months-1, 0)
+	summary := "no changes"
+	if peak > 0 {
+		delta := monthly[peakMonth]
+		summary = fmt.Sprintf("peak %s +%d/-%d", peakMonth, delta.Additions, delta.Deletions)
+	}
+	return fmt.Sprintf("%s [%s] %s, %s", start.Format("Jan 2006"), spark.String(), end.Format("Jan 2006"), summary)
+}
@@ -25,12 +25,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -84,6 +84,8 @@ func previewContent(s string) string {
 }
 
 // applyMergeCommitAdjustment applies ceiling division to line counts for merge commits.
+// It is used by the "all" merge policy (see merges.go), where a merge is diffed
+// against every parent.
 // This function implements a heuristic to estimate the actual impact of a merge commit
 // by dividing accumulated line changes by the number of parents. The ceiling division
 // ensures we don't truncate small values to zero, which would be misleading.
@@ -149,48 +151,26 @@ func matchesSinglePathFilter(filePath, pathFilter string) bool {
 	return matchesPathFilter(filePath, []string{pathFilter})
 }
 
+// reportDirectory returns the directory a file is reported under, "root/" for top-level files
+func reportDirectory(path string) string {
+	dir := filepath.Dir(path)
+	if dir == "." {
+		return "root/"
+	}
+	return dir + "/"
+}
+
 // commitAffectsPath checks if a commit affects any of the specified path filters
 func commitAffectsPath(commit *object.Commit, pathFilters []string) (bool, error) {
 	if len(pathFilters) == 0 {
 		return true, nil
 	}
 
-	// For initial commits, check if any files match the path filters
-	if commit.NumParents() == 0 {
-		tree, err := commit.Tree()
-		if err != nil {
-			return false, err
-		}
-		
-		found := false
-		err = tree.Files().ForEach(func(f *object.File) error {
-			if matchesPathFilter(f.Name, pathFilters) {
-				found = true
-				return storer.ErrStop // Stop iteration
-			}
-			return nil
-		})
-		return found, err
-	}
-
-	// For regular commits, check the diff against parent
-	parent, err := commit.Parent(0)
-	if err != nil {
-		return false, err
-	}
-
-	patch, err := parent.Patch(commit)
+	paths, err := commitChangedPaths(commit, pathFilters, true)
 	if err != nil {
 		return false, err
 	}
-
-	for _, stat := range patch.Stats() {
-		if matchesPathFilter(stat.Name, pathFilters) {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	return len(paths) > 0, nil
 }
 
 // mergeViperConfig merges configuration from source viper into target viper
@@ -278,6 +258,60 @@ func expandTimeWindow(lastArg string) string {
 	}
 }
 
+// formatElapsed renders a duration in the most readable unit
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%.0fm", d.Minutes())
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	default:
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+}
+
+// hoursDuration converts fractional hours to a duration for display
+func hoursDuration(hours float64) time.Duration {
+	return time.Duration(hours * float64(time.Hour))
+}
+
+// compileMessagePatterns compiles commit-message regexes
+func compileMessagePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchesAnyPattern reports whether a commit message matches any of the patterns
+func matchesAnyPattern(message string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// getMessagePatterns returns commit-message patterns from a string slice flag,
+// the config file or the defaults, in that order, and where they came from
+func getMessagePatterns(cmd *cobra.Command, flag, configKey string, defaults []string) ([]string, string) {
+	if patterns, _ := cmd.Flags().GetStringSlice(flag); len(patterns) > 0 {
+		return patterns, "(from CLI)"
+	}
+	if viper.IsSet(configKey) {
+		if patterns := viper.GetStringSlice(configKey); len(patterns) > 0 {
+			return patterns, "(from config)"
+		}
+	}
+	return defaults, "(default)"
+}
+
 // printCommandScope prints the configuration scope for a command
 func printCommandScope(cmd *cobra.Command, commandName string, lastArg string, pathFilters []string, source string) {
 	// Print config file information if available
@@ -302,6 +336,8 @@ func printCommandScope(cmd *cobra.Command, commandName string, lastArg string, p
 		fmt.Fprintf(os.Stderr, "Path filter: all files\n")
 	}
 	
+	fmt.Fprintf(os.Stderr, "Merge handling: %s\n", describeMergeHandling())
+	
 	fmt.Fprintf(os.Stderr, "\n")
 }
 
@@ -0,0 +1,20 @@
+package cmd
+
+import "testing"
+
+func TestReportDirectory(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"main.go", "root/"},
+		{"cmd/rework.go", "cmd/"},
+		{"a/b/c.go", "a/b/"},
+	}
+
+	for _, tt := range tests {
+		if result := reportDirectory(tt.path); result != tt.expected {
+			t.Errorf("reportDirectory(%q) = %q, want %q", tt.path, result, tt.expected)
+		}
+	}
+}